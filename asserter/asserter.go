@@ -49,6 +49,7 @@ type Asserter interface {
 	
 	// On a successful match, describes the result of applying
 	// the given matcher to the given value and invokes Fail().
+	// Also fails if the matcher could not be applied.
 	FailWhen(value interface{}, matcher *base.Matcher)
 	
 	// On an unsuccessful match, describes the result of applying
@@ -57,6 +58,7 @@ type Asserter interface {
 	
 	// On a successful match, describes the result of applying
 	// the given matcher to the given value and invokes FailNow().
+	// Also fails if the matcher could not be applied.
 	FailNowWhen(value interface{}, matcher *base.Matcher)
 	
 	// On an unsuccessful match, describes the result of applying
@@ -216,10 +218,13 @@ func (self *_Asserter) Failed() bool {
 	return self.logger.Failed()
 }
 
+// Applies the matcher to the value, converting any panic into a
+// Result that carries the recovered value and the stack at the point
+// of the panic.
 func safeMatch(value interface{}, matcher *base.Matcher) (result *base.Result) {
 	defer func() {
 		if x := recover(); x != nil {
-			result = base.NewResultf(false, "Panic: %v", x).
+			result = base.NewPanicResult(base.CapturePanic(x, 0)).
 				WithMatcherAndValue(matcher, value)
 		}
 	}()
@@ -232,7 +237,12 @@ func safeMatch(value interface{}, matcher *base.Matcher) (result *base.Result) {
 func (self *_Asserter) _LogResult(indent string, result *base.Result) {
 	value := result.Value()
 	logger := self.logger
-	if result.Matched() {
+	panicked := result.Panic()
+	if panicked != nil {
+		logger.Logf("%vMATCHER PANICKED on input: %v\n", indent, value)
+	} else if result.Err() != nil {
+		logger.Logf("%vMATCHER ERROR on input: %v\n", indent, value)
+	} else if result.Matched() {
 		logger.Logf("%vMATCHED input: %v\n", indent, value)
 	} else {
 		logger.Logf("%vDID NOT MATCH input: %v\n", indent, value)
@@ -243,6 +253,12 @@ func (self *_Asserter) _LogResult(indent string, result *base.Result) {
 		logger.Logf("%vMatcher: %v\n", detailsIndent, matcher)
 	}
 	logger.Logf("%vBecause: %v\n", detailsIndent, result)
+	if panicked != nil {
+		logger.Logf("%vPanic type: %T\n", detailsIndent, panicked.Value())
+		for _, frame := range panicked.Stack() {
+			logger.Logf("%v\tat %v\n", detailsIndent, frame)
+		}
+	}
 	if matcher != nil {
 		for _, comment := range matcher.Comments() {
 			logger.Logf("%vComment: %v\n", detailsIndent, comment)
@@ -281,7 +297,7 @@ func (self *_Asserter) LogUnless(value interface{}, matcher *base.Matcher) {
 }
 
 func (self *_Asserter) FailWhen(value interface{}, matcher *base.Matcher) {
-	if result := safeMatch(value, matcher); result.Matched() {
+	if result := safeMatch(value, matcher); result.Matched() || result.Err() != nil {
 		self.LogResult(result)
 		self.Fail()
 	}
@@ -295,7 +311,7 @@ func (self *_Asserter) FailUnless(value interface{}, matcher *base.Matcher) {
 }
 
 func (self *_Asserter) FailNowWhen(value interface{}, matcher *base.Matcher) {
-	if result := safeMatch(value, matcher); result.Matched() {
+	if result := safeMatch(value, matcher); result.Matched() || result.Err() != nil {
 		self.LogResult(result)
 		self.FailNow()
 	}
@@ -254,4 +254,52 @@ func Test_NullAsserter(t *testing.T) {
 	asserter.AssertFalse(true, "Should ignore attempts to AssertFalse")
 	asserter.AssertNil("ha!", "Should ignore attempts to AssertNil")
 	asserter.AssertNonNil(nil, "Should ignore attempts to AssertNonNil")
-}
\ No newline at end of file
+}
+
+type _PanicValue struct {
+	reason string
+}
+
+func Test_CheckThat_onPanickingMatcher(t *testing.T) {
+	buffer := newBuffer()
+	asserter := UsingWriter(buffer)
+	panicking := base.NewMatcherf(func(v interface{}) *base.Result {
+			panic(&_PanicValue{reason: "kaboom"})
+		}, MATCHER_DESCRIPTION)
+	asserter.CheckThat(MATCHING_VALUE, panicking)
+	checkAsserterFailed(t, asserter)
+	checkBufferContainsStrings(t, buffer,
+		"MATCHER PANICKED", MATCHING_VALUE, MATCHER_DESCRIPTION,
+		"kaboom", "*asserter._PanicValue", "testing.")
+}
+
+func Test_safeMatch_onPanickingMatcher(t *testing.T) {
+	panicking := base.NewMatcherf(func(v interface{}) *base.Result {
+			panic("kaboom")
+		}, MATCHER_DESCRIPTION)
+	result := safeMatch(MATCHING_VALUE, panicking)
+	checkResultIsNonMatching(t, result, "panic should not match")
+	if result.Panic() == nil {
+		t.Fatalf("Expected result to carry a Panic, was [%v]", result)
+	}
+	if value := result.Panic().Value(); value != "kaboom" {
+		t.Errorf("Expected panic value [kaboom], was [%v]", value)
+	}
+	if result.Matcher() != panicking || result.Value() != MATCHING_VALUE {
+		t.Errorf("Expected matcher and value to be recorded, was [%v] and [%v]",
+			result.Matcher(), result.Value())
+	}
+	for _, frame := range result.Panic().Stack() {
+		if strings.HasPrefix(frame.Function, "runtime.") {
+			t.Errorf("Expected runtime frames to be trimmed, found %v", frame)
+		}
+	}
+}
+
+func Test_safeMatch_onNonPanickingMatcher(t *testing.T) {
+	result := safeMatch(NONMATCHING_VALUE, MATCHER)
+	checkResultIsNonMatching(t, result, "should not match")
+	if result.Panic() != nil {
+		t.Errorf("Expected no Panic, was [%v]", result.Panic())
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package asserter
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/base"
+)
+
+// A row of a table-driven test:  the function under test is applied
+// to the Input, and its return value should be matched by the Matcher.
+type Case struct {
+	Name string
+	Input interface{}
+	Matcher *base.Matcher
+}
+
+// Runs a table-driven test, applying the given function to the Input
+// of each Case (as core.Applying does:  the Input is the function's
+// first argument, and its first return value is matched) and checking
+// the return value with the Case's Matcher.  For example:
+//    asserter.Table(t, strconv.Atoi, []asserter.Case{
+//        {"zero", "0", EqualTo(0)},
+//        {"negative", "-7", LessThan(0)},
+//    })
+//
+// Each row is run separately:  a row that fails (or panics) does not
+// stop the rows after it.  The logger is sent a summary table of the
+// rows that passed and failed, followed by the Result of each failing
+// row, and marked as failed if any row failed.
+func Table(logger Logger, function interface{}, cases []Case) {
+	if err := base.CheckApplicable(function); err != nil {
+		logger.Logf("Could not run table of %v cases: %v\n", len(cases), err)
+		logger.Fail()
+		return
+	}
+	names := make([]string, len(cases))
+	results := make([]*base.Result, len(cases))
+	passed := 0
+	for i, row := range cases {
+		names[i] = row.Name
+		if names[i] == "" {
+			names[i] = fmt.Sprintf("case #%v", i + 1)
+		}
+		results[i] = safeMatch(row.Input, _RowMatcher(names[i], function, row.Matcher))
+		if results[i].Matched() {
+			passed++
+		}
+	}
+	logger.Logf("Table: %v of %v cases passed\n", passed, len(cases))
+	for i, result := range results {
+		logger.Logf("\t%-5v %v\n", _RowStatus(result), names[i])
+	}
+	if passed == len(cases) {
+		return
+	}
+	we := &_Asserter{logger: logger}
+	for i, result := range results {
+		if !result.Matched() {
+			logger.Logf("--- %v: %v\n", _RowStatus(result), names[i])
+			we._LogResult("\t", result)
+		}
+	}
+	logger.Fail()
+}
+
+// Returns a matcher that applies the function to its input and matches
+// the return value with the given matcher.
+func _RowMatcher(name string, function interface{}, matcher *base.Matcher) *base.Matcher {
+	match := func(input interface{}) *base.Result {
+		if matcher == nil {
+			return base.NewErrorResultf("Case %v has no Matcher", name)
+		}
+		output, err := base.Apply(function, input)
+		if err != nil {
+			return base.NewErrorResultf("%v", err)
+		}
+		result := matcher.Match(output)
+		return base.NewResultf(result.Matched(), "returned %#v", output).
+			WithError(result.Err()).
+			WithCauses(result)
+	}
+	rowMatcher := base.NewMatcherf(match, "%v: %v", name, matcher)
+	if matcher == nil {
+		return rowMatcher.WithStructure("Case", []interface{}{name})
+	}
+	return rowMatcher.WithStructure("Case", []interface{}{name}, matcher)
+}
+
+func _RowStatus(result *base.Result) string {
+	switch {
+	case result.Panic() != nil:
+		return "PANIC"
+	case result.Err() != nil:
+		return "ERROR"
+	case result.Matched():
+		return "PASS"
+	}
+	return "FAIL"
+}
@@ -0,0 +1,95 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package asserter
+
+import (
+	"github.com/rdrdr/hamcrest/base"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_Table_allPass(t *testing.T) {
+	buffer := newBuffer()
+	logger := &_LoggerUsingWriter{writer: buffer}
+	Table(logger, strconv.Atoi, []Case{
+		{"zero", "0", base.EqualTo(0)},
+		{"negative", "-7", base.LessThan(0)},
+	})
+	if logger.Failed() {
+		t.Errorf("Should not have failed:\n%v", buffer.String())
+	}
+	checkBufferContainsStrings(t, buffer,
+		"Table: 2 of 2 cases passed", "PASS  zero", "PASS  negative")
+	if strings.Contains(buffer.String(), "--- ") {
+		t.Errorf("Should not have logged failing rows, was:\n%v", buffer.String())
+	}
+}
+
+func Test_Table_failures(t *testing.T) {
+	buffer := newBuffer()
+	logger := &_LoggerUsingWriter{writer: buffer}
+	explode := func(s string) int {
+		if s == "boom" {
+			panic("exploded")
+		}
+		return len(s)
+	}
+	Table(logger, explode, []Case{
+		{"short", "ab", base.EqualTo(2)},
+		{"wrong", "abc", base.EqualTo(4)},
+		{"", "boom", base.EqualTo(4)},
+		{"not a string", 5, base.EqualTo(1)},
+		{"no matcher", "a", nil},
+	})
+	if !logger.Failed() {
+		t.Errorf("Should have failed")
+	}
+	checkBufferContainsStrings(t, buffer,
+		"Table: 1 of 5 cases passed",
+		"PASS  short",
+		"FAIL  wrong",
+		"PANIC case #3",
+		"ERROR not a string",
+		"ERROR no matcher",
+		"--- FAIL: wrong",
+		"DID NOT MATCH input: abc",
+		"returned 3",
+		"--- PANIC: case #3",
+		"Cannot use int as input",
+		"has no Matcher")
+	if strings.Contains(buffer.String(), "--- PASS") {
+		t.Errorf("Should not have logged passing rows in detail, was:\n%v", buffer.String())
+	}
+}
+
+func Test_Table_notAFunction(t *testing.T) {
+	buffer := newBuffer()
+	logger := &_LoggerUsingWriter{writer: buffer}
+	Table(logger, "strconv.Atoi", []Case{{"zero", "0", base.EqualTo(0)}})
+	if !logger.Failed() {
+		t.Errorf("Should have failed")
+	}
+	checkBufferContainsStrings(t, buffer, "Could not run table of 1 cases", "expected a function")
+}
+
+func Test_Table_rowStructure(t *testing.T) {
+	zero := base.EqualTo(0)
+	var names []string
+	_RowMatcher("zero", strconv.Atoi, zero).Inspect(func(m *base.Matcher) bool {
+		names = append(names, m.Name())
+		return true
+	})
+	if len(names) != 2 || names[0] != "Case" || names[1] != "EqualTo" {
+		t.Errorf("Expected [Case EqualTo], was %v", names)
+	}
+	row := _RowMatcher("zero", strconv.Atoi, zero)
+	if params := row.Params(); len(params) != 1 || params[0] != "zero" {
+		t.Errorf("Expected params [zero], was %v", params)
+	}
+	if children := _RowMatcher("none", strconv.Atoi, nil).Children(); len(children) != 0 {
+		t.Errorf("Expected no children without a Matcher, was %v", children)
+	}
+}
@@ -5,6 +5,7 @@
 package base
 
 import (
+	"github.com/rdrdr/hamcrest/diff"
 	"reflect"
 )
 
@@ -185,7 +186,9 @@ func GreaterThan(expected interface{}) *Matcher {
 		}
 		panic("every case should have a return")
 	}
-	return NewMatcherf(match, "GreaterThan(%v)", expected)
+	return NewMatcherf(match, "GreaterThan(%v)", expected).
+		WithStructure("GreaterThan", []interface{}{expected}).
+		WithNegationf("is not greater than %v", expected)
 }
 
 // Returns a matcher that matches values that are greater-than-or-equal-to
@@ -201,7 +204,9 @@ func GreaterThanOrEqualTo(expected interface{}) *Matcher {
 		}
 		panic("every case should have a return")
 	}
-	return NewMatcherf(match, "GreaterThanOrEqualTo(%v)", expected)
+	return NewMatcherf(match, "GreaterThanOrEqualTo(%v)", expected).
+		WithStructure("GreaterThanOrEqualTo", []interface{}{expected}).
+		WithNegationf("is not greater than or equal to %v", expected)
 }
 
 // Returns a matcher that matches values that are less-than the given
@@ -217,7 +222,9 @@ func LessThan(expected interface{}) *Matcher {
 		}
 		panic("every case should have a return")
 	}
-	return NewMatcherf(match, "LessThan(%v)", expected)
+	return NewMatcherf(match, "LessThan(%v)", expected).
+		WithStructure("LessThan", []interface{}{expected}).
+		WithNegationf("is not less than %v", expected)
 }
 
 // Returns a matcher that matches values that are less-than-or-equal-to
@@ -233,7 +240,9 @@ func LessThanOrEqualTo(expected interface{}) *Matcher {
 		}
 		panic("every case should have a return")
 	}
-	return NewMatcherf(match, "LessThanOrEqualTo(%v)", expected)
+	return NewMatcherf(match, "LessThanOrEqualTo(%v)", expected).
+		WithStructure("LessThanOrEqualTo", []interface{}{expected}).
+		WithNegationf("is not less than or equal to %v", expected)
 }
 
 // Returns a matcher that matches values that are equal to the
@@ -245,11 +254,17 @@ func EqualTo(expected interface{}) *Matcher {
 		case _ORDERED_EQUAL_TO, _UNORDERED_EQUAL_TO:
 			return NewResult(true, c._Describe(actual, expected))
 		default:
-			return NewResult(false, c._Describe(actual, expected))
+			result := NewResult(false, c._Describe(actual, expected))
+			if cause := _TextDiff(actual, expected); cause != nil {
+				result = result.WithCauses(cause)
+			}
+			return result
 		}
 		panic("every case should have a return")
 	}
-	return NewMatcherf(match, "EqualTo(%v)", expected)
+	return NewMatcherf(match, "EqualTo(%v)", expected).
+		WithStructure("EqualTo", []interface{}{expected}).
+		WithNegationf("is not equal to %v", expected)
 }
 
 // Returns a matcher that matches values that are not equal to the
@@ -265,6 +280,20 @@ func NotEqualTo(expected interface{}) *Matcher {
 		}
 		panic("every case should have a return")
 	}
-	return NewMatcherf(match, "NotEqualTo(%v)", expected)
+	return NewMatcherf(match, "NotEqualTo(%v)", expected).
+		WithStructure("NotEqualTo", []interface{}{expected}).
+		WithNegationf("is equal to %v", expected)
 }
 
+// If actual and expected are both strings, returns a Result that
+// describes (lazily) where they differ;  otherwise returns nil.
+func _TextDiff(actual, expected interface{}) *Result {
+	actualString, ok1 := actual.(string)
+	expectedString, ok2 := expected.(string)
+	if !ok1 || !ok2 {
+		return nil
+	}
+	return NewResultf(false, "%v", &diff.Comparison{
+		ExpectedName: "expected", ActualName: "actual",
+		Expected: expectedString, Actual: actualString})
+}
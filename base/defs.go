@@ -6,6 +6,7 @@ package base
 
 import (
 	"fmt"
+	"os"
 	"reflect"
 )
 
@@ -48,12 +49,20 @@ func Description(format string, args...interface{}) SelfDescribing {
 // --------------------------------------------------------------------
 
 // Self-describing result of applying a Matcher to an input value.
+//
+// A Result is in one of three states:  it matched, it did not match,
+// or the Matcher could not be applied at all (for example, because
+// the input was of the wrong type, or because the Matcher panicked).
+// The last state is reported by a non-nil Err(), and such Results
+// never report Matched().
 type Result struct {
 	description SelfDescribing
 	matched bool
 	value interface{}
 	matcher *Matcher
 	causes []*Result
+	err os.Error
+	panicked *Panic
 }
 // Creates a new Result using the given description.
 func NewResult(matched bool, description SelfDescribing) *Result {
@@ -65,9 +74,49 @@ func NewResultf(matched bool, format string, args...interface{}) *Result {
 	return NewResult(matched, Description(format, args...))
 }
 
-// Returns true if the Match was successful.
+// Creates a new Result for a Matcher that could not be applied
+// because of the given error.
+func NewErrorResult(err os.Error) *Result {
+	return &Result{ description: Description("Error: %v", err), err: err }
+}
+
+// Creates a new Result for a Matcher that could not be applied,
+// using the given format/args as both the description and the error.
+func NewErrorResultf(format string, args...interface{}) *Result {
+	description := Description(format, args...)
+	return &Result{ description: description, err: description }
+}
+
+// Creates a Matcher that cannot be applied to any input, typically
+// because it could not be constructed (from an invalid pattern, for
+// example):  every Result it produces reports the given error.  The
+// Matcher is described by the given format/args.
+func NewErrorMatcherf(err os.Error, format string, args...interface{}) *Matcher {
+	match := func(actual interface{}) *Result {
+		return NewErrorResult(err)
+	}
+	return NewMatcherf(match, format, args...)
+}
+
+// Creates a new Result for a Matcher that could not be applied
+// because of a panic.  The Panic is also reported by Err().
+func NewPanicResult(panicked *Panic) *Result {
+	return &Result{
+		description: Description("Panic: %v", panicked),
+		err: panicked,
+		panicked: panicked}
+}
+
+// Returns true if the Match was successful.  Always returns false if
+// Err() is non-nil.
 func (self *Result) Matched() bool {
-	return self.matched
+	return self.matched && self.err == nil
+}
+
+// Returns the error that prevented the Matcher from being applied,
+// or nil if the Matcher produced a genuine match or mismatch.
+func (self *Result) Err() os.Error {
+	return self.err
 }
 
 // Returns the Matcher that produced this Result, or nil if this Result
@@ -76,6 +125,14 @@ func (self *Result) Matcher() *Matcher {
 	return self.matcher
 }
 
+// Returns the Panic that prevented a Matcher from completing, or nil
+// if this Result was not produced by a panicking Matcher.  A Result
+// with a non-nil Panic describes a broken Matcher rather than a value
+// that failed to meet the Matcher's criteria.
+func (self *Result) Panic() *Panic {
+	return self.panicked
+}
+
 // Returns the value that was given to the Matcher to produce this Result.
 //
 // Note: If `Matcher()` returns nil, then this will also return nil.
@@ -119,7 +176,23 @@ func (self *Result) WithCauses(causes... *Result) *Result {
 		description:self.description,
 		matcher:self.matcher,
 		value:self.value,
-		causes:causes}
+		causes:causes,
+		err:self.err,
+		panicked:self.panicked}
+}
+
+// Returns a new Result, identical to this one, except with the given
+// error.  A non-nil error marks the new Result as a Matcher error
+// rather than a mismatch.
+func (self *Result) WithError(err os.Error) *Result {
+	return &Result{
+		matched:self.matched,
+		description:self.description,
+		matcher:self.matcher,
+		value:self.value,
+		causes:self.Causes(),
+		err:err,
+		panicked:self.panicked}
 }
 
 // Returns a new Result, identical to this one, except with
@@ -133,7 +206,9 @@ func (self *Result) WithMatcherAndValue(matcher *Matcher, value interface{}) *Re
 		description:self.description,
 		matcher:matcher,
 		value:value,
-		causes:self.Causes()}
+		causes:self.Causes(),
+		err:self.err,
+		panicked:self.panicked}
 }
 
 // --------------------------------------------------------------------
@@ -148,6 +223,10 @@ type Matcher struct {
 	description SelfDescribing
 	match func(v interface{}) *Result
 	comments []SelfDescribing
+	name string
+	params []interface{}
+	children []*Matcher
+	negation SelfDescribing
 }
 
 // Creates a new Matcher using the given matching function, with the
@@ -220,7 +299,7 @@ func normalizeMatchFunction(fn interface{}) func(interface{}) *Result {
 			interpretOutputValues = func(values []reflect.Value) (result *Result) {
 				defer func() {
 					if err := recover(); err != nil {
-						result = NewResultf(false,
+						result = NewErrorResultf(
 							"Error:  expected *Result from %T, got %v", fn, values)
 					}
 				}()
@@ -231,7 +310,7 @@ func normalizeMatchFunction(fn interface{}) func(interface{}) *Result {
 			interpretOutputValues = func(values []reflect.Value) (result *Result) {
 				defer func() {
 					if err := recover(); err != nil {
-						result = NewResultf(false,
+						result = NewErrorResultf(
 							"Error:  expected bool from %T, got %v", fn, values)
 					}
 				}()
@@ -251,7 +330,7 @@ func normalizeMatchFunction(fn interface{}) func(interface{}) *Result {
 		return func(actual interface{}) *Result {
 			inputValues, problem := constructInputValues(actual)
 			if problem != nil {
-				return NewResultf(false, "Could not apply %T to input of type %T: %v",
+				return NewErrorResultf("Could not apply %T to input of type %T: %v",
 					fn, actual, problem)
 			}
 			outputValues := funcValue.Call(inputValues)
@@ -306,7 +385,9 @@ func (self *Matcher) Comment(comments...interface{}) *Matcher {
 		}
 		all = append(all, selfDescribing)
 	}
-	return &Matcher{description:self.description, match:self.match, comments:all}
+	matcher := *self
+	matcher.comments = all
+	return &matcher
 }
 
 // Returns a *new* Matcher similar to this one, but with the
@@ -315,6 +396,8 @@ func (self *Matcher) Commentf(format string, args...interface{}) *Matcher {
 	all := make([]SelfDescribing, 0, len(self.comments) + 1)
 	copy(all, self.comments)
 	all = append(all, Description(format, args...))
-	return &Matcher{description:self.description, match:self.match, comments:all}
+	matcher := *self
+	matcher.comments = all
+	return &matcher
 }
 
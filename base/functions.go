@@ -0,0 +1,88 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// Checks that the given function can be used with Apply:  that it is
+// a function that accepts at least one parameter and returns at least
+// one value.
+func CheckApplicable(function interface{}) os.Error {
+	funcValue, ok := reflect.NewValue(function).(*reflect.FuncValue)
+	if !ok {
+		return os.NewError(fmt.Sprintf("expected a function, was %T", function))
+	}
+	funcType := funcValue.Type().(*reflect.FuncType)
+	if numIn := funcType.NumIn(); numIn == 0 {
+		return os.NewError(fmt.Sprintf(
+			"function must accept at least one value, was %v by function %v", numIn, function))
+	}
+	if numOut := funcType.NumOut(); numOut == 0 {
+		return os.NewError(fmt.Sprintf(
+			"function must return at least one value, was %v by function %v", numOut, function))
+	}
+	return nil
+}
+
+// Sets dst to the given value, returning false if the value cannot be
+// assigned to dst.  A nil value can only be assigned to a type that has
+// a nil value (pointer, interface, slice, map, channel or function).
+func Assign(dst reflect.Value, value interface{}) (ok bool) {
+	if value == nil {
+		switch dst.Type().(type) {
+		case *reflect.PtrType, *reflect.InterfaceType, *reflect.SliceType,
+				*reflect.MapType, *reflect.ChanType, *reflect.FuncType:
+			dst.SetValue(reflect.MakeZero(dst.Type()))
+			return true
+		}
+		return false
+	}
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	dst.SetValue(reflect.NewValue(value))
+	return true
+}
+
+// Invokes the given function with the input as its first argument (or,
+// if its only parameter is variadic, as its single variadic argument)
+// and zero values for any other parameters, and returns its first
+// return value.  Returns an error if the function cannot be used (see
+// CheckApplicable) or the input cannot be assigned to its first
+// parameter (see Assign).  If the function panics, so does Apply.
+func Apply(function interface{}, input interface{}) (output interface{}, err os.Error) {
+	if err := CheckApplicable(function); err != nil {
+		return nil, err
+	}
+	funcValue := reflect.NewValue(function).(*reflect.FuncValue)
+	funcType := funcValue.Type().(*reflect.FuncType)
+	cannotUse := os.NewError(fmt.Sprintf("Cannot use %T as input to %T", input, function))
+	numIn := funcType.NumIn()
+	argValues := make([]reflect.Value, numIn)
+	inType := funcType.In(0)
+	if numIn == 1 && funcType.DotDotDot() {
+		inSlice := reflect.MakeSlice(inType.(*reflect.SliceType), 1, 1)
+		if !Assign(inSlice.Elem(0), input) {
+			return nil, cannotUse
+		}
+		argValues[0] = inSlice
+	} else {
+		inValue := reflect.MakeZero(inType)
+		if !Assign(inValue, input) {
+			return nil, cannotUse
+		}
+		argValues[0] = inValue
+	}
+	for i := 1; i < numIn; i++ {
+		argValues[i] = reflect.MakeZero(funcType.In(i))
+	}
+	return funcValue.Call(argValues)[0].Interface(), nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Apply(t *testing.T) {
+	toLength := func(s string) int { return len(s) }
+	if out, err := Apply(toLength, "four"); err != nil || out != 4 {
+		t.Errorf("Expected 4 from Apply(toLength, \"four\"), was %v (error %v)", out, err)
+	}
+	if out, err := Apply(toLength, 4); err == nil {
+		t.Errorf("Expected an error from Apply(toLength, 4), was %v", out)
+	}
+	sum := func(values...int) int { return values[0] * 2 }
+	if out, err := Apply(sum, 3); err != nil || out != 6 {
+		t.Errorf("Expected 6 from Apply(sum, 3), was %v (error %v)", out, err)
+	}
+	prefix := func(s string, n int) string { return s + "!" }
+	if out, err := Apply(prefix, "hi"); err != nil || out != "hi!" {
+		t.Errorf("Expected \"hi!\" from Apply(prefix, \"hi\"), was %v (error %v)", out, err)
+	}
+}
+
+func Test_Assign(t *testing.T) {
+	var s string
+	if !Assign(reflect.NewValue(&s).(*reflect.PtrValue).Elem(), "x") || s != "x" {
+		t.Errorf("Expected to assign \"x\" to a string, was %q", s)
+	}
+	if Assign(reflect.NewValue(&s).(*reflect.PtrValue).Elem(), 4) {
+		t.Errorf("Expected not to assign 4 to a string")
+	}
+	if Assign(reflect.NewValue(&s).(*reflect.PtrValue).Elem(), nil) {
+		t.Errorf("Expected not to assign nil to a string")
+	}
+	p := &s
+	if !Assign(reflect.NewValue(&p).(*reflect.PtrValue).Elem(), nil) || p != nil {
+		t.Errorf("Expected to assign nil to a *string, was %v", p)
+	}
+}
+
+func Test_CheckApplicable(t *testing.T) {
+	if err := CheckApplicable(func(s string) int { return 0 }); err != nil {
+		t.Errorf("Expected no error, was %v", err)
+	}
+	if err := CheckApplicable("not a function"); err == nil ||
+			!strings.Contains(err.String(), "expected a function") {
+		t.Errorf("Expected an error for a non-function, was %v", err)
+	}
+	if err := CheckApplicable(func() int { return 0 }); err == nil {
+		t.Errorf("Expected an error for a function without parameters")
+	}
+	if err := CheckApplicable(func(s string) {}); err == nil {
+		t.Errorf("Expected an error for a function without results")
+	}
+	if _, err := Apply(func() int { return 0 }, 1); err == nil {
+		t.Errorf("Expected Apply to reject a function without parameters")
+	}
+}
@@ -74,6 +74,7 @@ func Test_DidNotMatch(t *testing.T) {
 	
 	checkResultIsNonMatching(t, matcher, passResult, "matching")
 	checkResultIsMatching(t, matcher, failResult, "non-matching")
+	checkResultIsNonMatching(t, matcher, NewErrorResultf("broken"), "errored")
 	checkResultIsNonMatching(t, matcher, nil, "nil")
 	checkResultIsNonMatching(t, matcher, "foo", "not a Result")
 }
@@ -176,3 +177,87 @@ func Test_NewMatcher_Func_StringDotDotDotIn_BoolStringOut(t *testing.T) {
 
 
 
+
+func Test_CapturePanic(t *testing.T) {
+	var captured *Panic
+	func() {
+		defer func() {
+			captured = CapturePanic(recover(), 0)
+		}()
+		panic("oops")
+	}()
+	if captured.Value() != "oops" {
+		t.Errorf("Expected recovered value [oops], was [%v]", captured.Value())
+	}
+	if s := captured.String(); !strings.Contains(s, "string") || !strings.Contains(s, "oops") {
+		t.Errorf("Expected description to include type and value, was [%v]", s)
+	}
+	for _, frame := range captured.Stack() {
+		if strings.HasPrefix(frame.Function, _HamcrestPrefix) {
+			t.Errorf("Expected hamcrest frames to be trimmed, found %v", frame)
+		}
+	}
+	result := NewPanicResult(captured)
+	if result.Matched() {
+		t.Errorf("Panic result should not match, was [%v]", result)
+	}
+	if result.WithCauses().Panic() != captured {
+		t.Errorf("WithCauses should preserve the Panic")
+	}
+}
+
+func Test_NewErrorMatcherf(t *testing.T) {
+	err := Description("invalid pattern")
+	matcher := NewErrorMatcherf(err, "HasPattern[%v]", "(")
+	if matcher.String() != "HasPattern[(]" {
+		t.Errorf("Expected description HasPattern[(], was %v", matcher)
+	}
+	for _, input := range []interface{}{"(", 42, nil} {
+		result := matcher.Match(input)
+		if result.Err() != err || result.Matched() {
+			t.Errorf("Expected error result for %v, was [%v]", input, result)
+		}
+	}
+}
+
+func Test_NewMatcher_typeMismatchIsAnError(t *testing.T) {
+	matcher := NewMatcherf(func(s string) bool { return s == "bar" }, "bar")
+	result := matcher.Match(39)
+	if result.Err() == nil {
+		t.Fatalf("Expected an error result on wrong input type, was [%v]", result)
+	}
+	if result.Matched() {
+		t.Errorf("Error result should not match, was [%v]", result)
+	}
+	checkResultIsMatching(t, Errored(), result, "type mismatch is an error")
+	checkResultIsNonMatching(t, DidNotMatch(), result, "an error is not a mismatch")
+	checkResultIsNonMatching(t, Matched(), result, "an error did not match")
+	checkResultIsNonMatching(t, Errored(), matcher.Match("foo"), "a mismatch is not an error")
+}
+
+func Test_Result_WithError(t *testing.T) {
+	result := NewResultf(true, "pass").WithError(Description("broken"))
+	if result.Matched() {
+		t.Errorf("Result with an error should never match, was [%v]", result)
+	}
+	if result.WithCauses().Err() == nil {
+		t.Errorf("WithCauses should preserve the error")
+	}
+	if NewErrorResultf("broken %v", 1).Err() == nil {
+		t.Errorf("NewErrorResultf should set the error")
+	}
+}
+
+func Test_EqualTo_describesStringDifferences(t *testing.T) {
+	result := checkResultIsNonMatching(t, EqualTo("one\ntwo\n"), "one\n2\n", "different text")
+	if len(result.Causes()) != 1 {
+		t.Fatalf("Expected a diff cause, was %v", result.Causes())
+	}
+	if s := result.Causes()[0].String(); !strings.Contains(s, "-two\n+2\n") {
+		t.Errorf("Expected a unified diff, was:\n%v", s)
+	}
+	result = checkResultIsNonMatching(t, EqualTo(1), 2, "different ints")
+	if len(result.Causes()) != 0 {
+		t.Errorf("Expected no diff cause for non-strings, was %v", result.Causes())
+	}
+}
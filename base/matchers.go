@@ -24,14 +24,21 @@ func init() {
 			if result.Matched() {
 				return NewResultf(true, "was a matching result").WithCauses(result)
 			}
+			if result.Err() != nil {
+				return NewResultf(false, "was a matcher error").WithCauses(result)
+			}
 			return NewResultf(false, "was a result that did not match").WithCauses(result)
 		}
 		return NewResultf(false, "[%v] was not a result", actual)
 	}
-	_Matched = NewMatcherf(match, "Matched")
+	_Matched = NewMatcherf(match, "Matched").
+		WithStructure("Matched", nil).
+		WithNegationf("is not a matching result")
 }
 
-// Returns a Matcher that matches the boolean value false.
+// Returns a Matcher that matches Results that did not match.  Results
+// whose Matcher could not be applied (see Errored) do not match:  use
+// AnyOf(DidNotMatch(), Errored()) to accept either.
 func DidNotMatch() *Matcher {
 	return _DidNotMatch
 }
@@ -42,11 +49,41 @@ func init() {
 			if result.Matched() {
 				return NewResultf(false, "was a matching result").WithCauses(result)
 			}
+			if err := result.Err(); err != nil {
+				return NewResultf(false,
+					"was a matcher error, not a result that did not match: %v", err).
+					WithCauses(result)
+			}
 			return NewResultf(true, "was a result that did not match").WithCauses(result)
 		}
 		return NewResultf(false, "[%v] was not a result", actual)
 	}
-	_DidNotMatch = NewMatcherf(match, "DidNotMatch")
+	_DidNotMatch = NewMatcherf(match, "DidNotMatch").
+		WithStructure("DidNotMatch", nil).
+		WithNegationf("is not a non-matching result")
+}
+
+// Returns a Matcher that matches Results whose Matcher could not be
+// applied (that is, Results with a non-nil Err()).
+func Errored() *Matcher {
+	return _Errored
+}
+var _Errored *Matcher // singleton
+func init() {
+	match := func (actual interface{}) *Result {
+		if result, ok := actual.(*Result); ok {
+			if err := result.Err(); err != nil {
+				return NewResultf(true, "was a matcher error: %v", err).
+					WithCauses(result)
+			}
+			return NewResultf(false, "was a result without an error").
+				WithCauses(result)
+		}
+		return NewResultf(false, "[%v] was not a result", actual)
+	}
+	_Errored = NewMatcherf(match, "Errored").
+		WithStructure("Errored", nil).
+		WithNegationf("is not a matcher error")
 }
 
 // Returns a Matcher that matches the boolean value true.
@@ -64,7 +101,9 @@ func init() {
 		}
 		return NewResultf(false, "[%v] was not bool", actual)
 	}
-	_True = NewMatcherf(match, "True")
+	_True = NewMatcherf(match, "True").
+		WithStructure("True", nil).
+		WithNegationf("is not true")
 }
 
 // Returns a Matcher that matches the boolean value false.
@@ -82,7 +121,9 @@ func init() {
 		}
 		return NewResultf(false, "[%v] was not bool", actual)
 	}
-	_False = NewMatcherf(match, "False")
+	_False = NewMatcherf(match, "False").
+		WithStructure("False", nil).
+		WithNegationf("is not false")
 }
 
 // Helper function for Nil/NonNil
@@ -112,7 +153,9 @@ func init() {
 		}
 		return NewResultf(false, "[%v] was not nil", actual)
 	}
-	_Nil = NewMatcherf(match, "Nil")
+	_Nil = NewMatcherf(match, "Nil").
+		WithStructure("Nil", nil).
+		WithNegationf("is not nil")
 }
 
 // Returns a Matcher that matches if the actual value is 
@@ -129,7 +172,9 @@ func init() {
 		}
 		return NewResultf(true, "[%v] was not nil", actual)
 	}
-	_NonNil = NewMatcherf(match, "NonNil")
+	_NonNil = NewMatcherf(match, "NonNil").
+		WithStructure("NonNil", nil).
+		WithNegationf("is nil")
 }
 
 // Returns a Matcher that checks if the actual value is (deeply)
@@ -146,7 +191,9 @@ func DeepEqualTo(expected interface{}) *Matcher {
 		return NewResultf(false,
 			"[%v] was not deeply equal to [%v]", actual, expected)
 	}
-	return NewMatcherf(match, "DeepEqualTo[%v]", expected)
+	return NewMatcherf(match, "DeepEqualTo[%v]", expected).
+		WithStructure("DeepEqualTo", []interface{}{expected}).
+		WithNegationf("is not deeply equal to %v", expected)
 }
 
 
@@ -0,0 +1,35 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+// --------------------------------------------------------------------
+// Negation
+// --------------------------------------------------------------------
+
+// Returns a *new* Matcher similar to this one, but with the given
+// format/args as its negated description:  a phrase that describes the
+// values this Matcher does *not* match, such as "is not greater than 5"
+// or "does not contain \"x\"".  Not and the matchers of the logic
+// package use negated descriptions to explain their Results.
+func (self *Matcher) WithNegationf(format string, args...interface{}) *Matcher {
+	matcher := *self
+	matcher.negation = Description(format, args...)
+	return &matcher
+}
+
+// Returns true if this Matcher was given a negated description (see
+// WithNegationf).
+func (self *Matcher) HasNegation() bool {
+	return self.negation != nil
+}
+
+// Returns the negated description of this Matcher (see WithNegationf),
+// or, if it has none, a generic one:  "does not match <description>".
+func (self *Matcher) Negation() SelfDescribing {
+	if self.negation != nil {
+		return self.negation
+	}
+	return Description("does not match %v", self)
+}
@@ -0,0 +1,61 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"testing"
+)
+
+func Test_WithNegationf(t *testing.T) {
+	plain := NewMatcherf(func(actual interface{}) bool { return actual == 3 }, "IsThree")
+	matcher := plain.WithNegationf("is not %v", 3)
+	if !matcher.HasNegation() {
+		t.Errorf("Expected %v to have a negation", matcher)
+	}
+	if negation := matcher.Negation().String(); negation != "is not 3" {
+		t.Errorf("Expected negation \"is not 3\", was %q", negation)
+	}
+	if matcher.String() != "IsThree" {
+		t.Errorf("Expected description to be unchanged, was %v", matcher)
+	}
+	checkResultIsMatching(t, matcher, 3, "should still match")
+	checkResultIsNonMatching(t, matcher, 4, "should still not match")
+	if plain.HasNegation() {
+		t.Errorf("Expected original matcher to have no negation")
+	}
+}
+
+func Test_Negation_defaultsToDescription(t *testing.T) {
+	matcher := NewMatcherf(func(actual interface{}) bool { return true }, "IsThree")
+	if negation := matcher.Negation().String(); negation != "does not match IsThree" {
+		t.Errorf("Expected default negation, was %q", negation)
+	}
+}
+
+func Test_Negation_preservedByCommentsAndStructure(t *testing.T) {
+	matcher := GreaterThan(5).Comment("first").Commentf("second %v", 2).
+		WithStructure("Renamed", nil)
+	if negation := matcher.Negation().String(); negation != "is not greater than 5" {
+		t.Errorf("Expected negation to be preserved, was %q", negation)
+	}
+}
+
+func Test_Negation_ofBaseMatchers(t *testing.T) {
+	for matcher, negation := range map[*Matcher]string{
+			True(): "is not true", False(): "is not false",
+			Nil(): "is not nil", NonNil(): "is nil",
+			GreaterThan(5): "is not greater than 5",
+			GreaterThanOrEqualTo(5): "is not greater than or equal to 5",
+			LessThan(5): "is not less than 5",
+			LessThanOrEqualTo(5): "is not less than or equal to 5",
+			EqualTo(5): "is not equal to 5",
+			NotEqualTo(5): "is equal to 5",
+			DeepEqualTo(5): "is not deeply equal to 5"} {
+		if !matcher.HasNegation() || matcher.Negation().String() != negation {
+			t.Errorf("Expected %v to have negation %q, was %q",
+				matcher, negation, matcher.Negation())
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Import path prefix shared by every hamcrest package.  Stack frames
+// whose function names begin with this prefix are trimmed from the
+// stack of a captured Panic.
+const _HamcrestPrefix = "github.com/rdrdr/hamcrest/"
+
+// Maximum number of stack frames recorded for a captured Panic.
+const _MaxStackDepth = 64
+
+// --------------------------------------------------------------------
+// StackFrame
+// --------------------------------------------------------------------
+
+// A single frame of a goroutine's stack.
+type StackFrame struct {
+	Function string
+	File string
+	Line int
+}
+
+// Implements fmt.Stringer.
+func (self StackFrame) String() string {
+	return fmt.Sprintf("%v (%v:%v)", self.Function, self.File, self.Line)
+}
+
+// --------------------------------------------------------------------
+// Panic
+// --------------------------------------------------------------------
+
+// Describes a value recovered from a panic, along with the stack of
+// the goroutine at the point where the panic was recovered.
+type Panic struct {
+	value interface{}
+	stack []StackFrame
+}
+
+// Creates a Panic from a value returned by recover().  This must be
+// invoked directly from the deferred function that called recover();
+// skip is the number of additional callers to discard from the top of
+// the stack.
+//
+// Frames belonging to the Go runtime and to hamcrest itself are
+// trimmed from the captured stack, so that the first frame is usually
+// the one that raised the panic.
+func CapturePanic(recovered interface{}, skip int) *Panic {
+	pcs := make([]uintptr, _MaxStackDepth)
+	n := runtime.Callers(skip + 2, pcs)
+	stack := make([]StackFrame, 0, n)
+	for _, pc := range pcs[:n] {
+		fn := runtime.FuncForPC(pc)
+		if fn == nil {
+			continue
+		}
+		name := fn.Name()
+		if _IsInternalFrame(name) {
+			continue
+		}
+		file, line := fn.FileLine(pc)
+		stack = append(stack,
+			StackFrame{Function: name, File: file, Line: line})
+	}
+	return &Panic{value: recovered, stack: stack}
+}
+
+func _IsInternalFrame(function string) bool {
+	function = strings.TrimLeft(function, "\"")
+	return strings.HasPrefix(function, _HamcrestPrefix) ||
+		strings.HasPrefix(function, "runtime.")
+}
+
+// Returns the value that was passed to panic().
+func (self *Panic) Value() interface{} {
+	return self.value
+}
+
+// Returns the stack frames (outside of hamcrest and the Go runtime)
+// that were active when the panic was recovered, innermost first.
+func (self *Panic) Stack() []StackFrame {
+	stack := make([]StackFrame, len(self.stack))
+	copy(stack, self.stack)
+	return stack
+}
+
+// Implements fmt.Stringer.
+func (self *Panic) String() string {
+	return fmt.Sprintf("panic(%T): %v", self.value, self.value)
+}
+
+// Returns a multi-line description of the panic, including one line
+// per stack frame.
+func (self *Panic) StackTrace() string {
+	lines := make([]string, 0, len(self.stack) + 1)
+	lines = append(lines, self.String())
+	for _, frame := range self.stack {
+		lines = append(lines, "\tat " + frame.String())
+	}
+	return strings.Join(lines, "\n")
+}
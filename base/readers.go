@@ -0,0 +1,32 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// Reads the given reader to its end without using it up where it can,
+// so that other matchers can read the same input again:  the unread
+// bytes of a *bytes.Buffer are copied (not read), and an io.Seeker is
+// returned to the position where reading began.  Any other reader is
+// consumed by the first matcher to read it, and later matchers read no
+// data from it (which they cannot tell apart from an empty input).
+func ReadAllRewinding(reader io.Reader) (data []byte, err os.Error) {
+	if buffer, ok := reader.(*bytes.Buffer); ok {
+		return append([]byte{}, buffer.Bytes()...), nil
+	}
+	if seeker, ok := reader.(io.Seeker); ok {
+		if start, err := seeker.Seek(0, 1); err == nil {
+			data, err = ioutil.ReadAll(reader)
+			seeker.Seek(start, 0)
+			return data, err
+		}
+	}
+	return ioutil.ReadAll(reader)
+}
@@ -0,0 +1,58 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+// A minimal io.ReadSeeker over a string.
+type _Seekable struct {
+	data string
+	pos int64
+}
+
+func (self *_Seekable) Read(p []byte) (n int, err os.Error) {
+	if self.pos >= int64(len(self.data)) {
+		return 0, os.EOF
+	}
+	n = copy(p, self.data[self.pos:])
+	self.pos += int64(n)
+	return n, nil
+}
+
+func (self *_Seekable) Seek(offset int64, whence int) (int64, os.Error) {
+	switch whence {
+	case 0:
+		self.pos = offset
+	case 1:
+		self.pos += offset
+	default:
+		self.pos = int64(len(self.data)) + offset
+	}
+	return self.pos, nil
+}
+
+func Test_ReadAllRewinding(t *testing.T) {
+	for _, reader := range []io.Reader{
+			bytes.NewBufferString("rewound"), &_Seekable{data: "rewound"}} {
+		for i := 0; i < 2; i++ {
+			if data, err := ReadAllRewinding(reader); string(data) != "rewound" || err != nil {
+				t.Errorf("Expected read %v of %T to be rewound, was %q (error %v)",
+					i, reader, data, err)
+			}
+		}
+	}
+	limited := io.LimitReader(bytes.NewBufferString("limited"), 100)
+	if data, err := ReadAllRewinding(limited); string(data) != "limited" || err != nil {
+		t.Errorf("Expected %q, was %q (error %v)", "limited", data, err)
+	}
+	if data, err := ReadAllRewinding(limited); len(data) != 0 || err != nil {
+		t.Errorf("Expected a consumed reader to be empty, was %q (error %v)", data, err)
+	}
+}
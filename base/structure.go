@@ -0,0 +1,95 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+// --------------------------------------------------------------------
+// Structure
+// --------------------------------------------------------------------
+
+// Returns a *new* Matcher similar to this one, but with a structured
+// description:  a name (usually the name of the function that created
+// the Matcher), the parameters it was created with (other than
+// matchers), and the child matchers it delegates to.  For example:
+//    return base.NewMatcherf(match, "HasField[%v](%v)", name, matcher).
+//        WithStructure("HasField", []interface{}{name}, matcher)
+//
+// The structure is for tools that inspect matchers (see Walk), such as
+// ones that render matcher trees or record which matchers failed;  it
+// does not change the description or the behavior of the Matcher.
+func (self *Matcher) WithStructure(name string, params []interface{}, children...*Matcher) *Matcher {
+	matcher := *self
+	matcher.name = name
+	matcher.params = make([]interface{}, len(params))
+	copy(matcher.params, params)
+	matcher.children = make([]*Matcher, len(children))
+	copy(matcher.children, children)
+	return &matcher
+}
+
+// Returns the name in this Matcher's structured description, or "" if
+// it has none (see WithStructure).
+func (self *Matcher) Name() string {
+	return self.name
+}
+
+// Returns the parameters in this Matcher's structured description.
+func (self *Matcher) Params() []interface{} {
+	params := make([]interface{}, len(self.params))
+	copy(params, self.params)
+	return params
+}
+
+// Returns the child matchers in this Matcher's structured description.
+func (self *Matcher) Children() []*Matcher {
+	children := make([]*Matcher, len(self.children))
+	copy(children, self.children)
+	return children
+}
+
+// A Visitor's Visit method is invoked for each Matcher encountered by
+// Walk.  If the result visitor w is not nil, Walk visits each of the
+// children of the Matcher with w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(matcher *Matcher) (w Visitor)
+}
+
+// Traverses this Matcher and its children (see Children) in depth-first
+// order:  it starts by calling visitor.Visit(self);  if the visitor w
+// returned by visitor.Visit(self) is not nil, Walk is invoked
+// recursively with visitor w for each child, followed by a call of
+// w.Visit(nil).
+func (self *Matcher) Walk(visitor Visitor) {
+	if self == nil {
+		return
+	}
+	if visitor = visitor.Visit(self); visitor == nil {
+		return
+	}
+	for _, child := range self.children {
+		child.Walk(visitor)
+	}
+	visitor.Visit(nil)
+}
+
+type _Inspector func(*Matcher) bool
+
+func (f _Inspector) Visit(matcher *Matcher) Visitor {
+	if matcher != nil && f(matcher) {
+		return f
+	}
+	return nil
+}
+
+// Traverses this Matcher and its children in depth-first order,
+// calling f for each Matcher.  If f returns true, Inspect visits the
+// children of that Matcher.  For example, to list every matcher in
+// a tree:
+//    matcher.Inspect(func(m *base.Matcher) bool {
+//        fmt.Println(m.Name(), m.Params())
+//        return true
+//    })
+func (self *Matcher) Inspect(f func(*Matcher) bool) {
+	self.Walk(_Inspector(f))
+}
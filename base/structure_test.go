@@ -0,0 +1,162 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func leaf(name string, params...interface{}) *Matcher {
+	return NewMatcherf(func(actual interface{}) bool { return actual == name }, "Leaf[%v]", name).
+		WithStructure(name, params)
+}
+
+func node(name string, children...*Matcher) *Matcher {
+	return NewMatcherf(func(actual interface{}) bool { return true }, "Node[%v]", name).
+		WithStructure(name, nil, children...)
+}
+
+// Records the names of visited matchers, and "nil" for the calls of
+// Visit(nil) that end each list of children.
+type _Recorder struct {
+	visited []string
+}
+
+func (self *_Recorder) Visit(matcher *Matcher) Visitor {
+	if matcher == nil {
+		self.visited = append(self.visited, "nil")
+	} else {
+		self.visited = append(self.visited, matcher.Name())
+	}
+	return self
+}
+
+func Test_WithStructure(t *testing.T) {
+	unstructured := NewMatcherf(func(actual interface{}) bool { return actual == 3 }, "IsThree")
+	matcher := unstructured.WithStructure("IsThree", []interface{}{3}, True(), False())
+	if matcher.Name() != "IsThree" {
+		t.Errorf("Expected name IsThree, was %v", matcher.Name())
+	}
+	if params := matcher.Params(); !reflect.DeepEqual(params, []interface{}{3}) {
+		t.Errorf("Expected params [3], was %v", params)
+	}
+	if children := matcher.Children(); len(children) != 2 || children[0] != True() || children[1] != False() {
+		t.Errorf("Expected children [True False], was %v", children)
+	}
+	if matcher.String() != "IsThree" {
+		t.Errorf("Expected description to be unchanged, was %v", matcher)
+	}
+	checkResultIsMatching(t, matcher, 3, "should still match")
+	checkResultIsNonMatching(t, matcher, 4, "should still not match")
+	if unstructured.Name() != "" || len(unstructured.Params()) != 0 || len(unstructured.Children()) != 0 {
+		t.Errorf("Expected original matcher to have no structure, was %v %v %v",
+			unstructured.Name(), unstructured.Params(), unstructured.Children())
+	}
+}
+
+func Test_WithStructure_copiesSlices(t *testing.T) {
+	params := []interface{}{1}
+	children := []*Matcher{True()}
+	matcher := NewMatcherf(func(actual interface{}) bool { return true }, "M").
+		WithStructure("M", params, children...)
+	params[0] = 2
+	children[0] = False()
+	matcher.Params()[0] = 3
+	matcher.Children()[0] = False()
+	if matcher.Params()[0] != 1 || matcher.Children()[0] != True() {
+		t.Errorf("Expected structure to be unaffected by slice changes, was %v %v",
+			matcher.Params(), matcher.Children())
+	}
+}
+
+func Test_Comment_preservesStructure(t *testing.T) {
+	matcher := leaf("A", "x").Comment("first").Commentf("second %v", 2)
+	if matcher.Name() != "A" || len(matcher.Params()) != 1 {
+		t.Errorf("Expected comments to preserve structure, was %v %v",
+			matcher.Name(), matcher.Params())
+	}
+}
+
+func Test_Walk(t *testing.T) {
+	tree := node("R", node("C", leaf("A"), leaf("B")), leaf("D"))
+	recorder := &_Recorder{}
+	tree.Walk(recorder)
+	visited := strings.Join(recorder.visited, " ")
+	if expected := "R C A nil B nil nil D nil nil"; visited != expected {
+		t.Errorf("Expected to visit %v, visited %v", expected, visited)
+	}
+}
+
+func Test_Walk_unstructuredAndNilMatchers(t *testing.T) {
+	unstructured := NewMatcherf(func(actual interface{}) bool { return true }, "M")
+	recorder := &_Recorder{}
+	node("R", unstructured, nil).Walk(recorder)
+	visited := strings.Join(recorder.visited, " ")
+	if expected := "R  nil nil"; visited != expected {
+		t.Errorf("Expected to visit %q, visited %q", expected, visited)
+	}
+	var nilMatcher *Matcher
+	nilMatcher.Walk(recorder) // should not panic
+}
+
+func Test_Inspect(t *testing.T) {
+	tree := node("R", node("C", leaf("A"), leaf("B")), leaf("D"))
+	var visited []string
+	tree.Inspect(func(matcher *Matcher) bool {
+		visited = append(visited, matcher.Name())
+		return matcher.Name() != "C"
+	})
+	if names := strings.Join(visited, " "); names != "R C D" {
+		t.Errorf("Expected to inspect R C D (skipping the children of C), was %v", names)
+	}
+}
+
+func Test_Inspect_rendersTree(t *testing.T) {
+	tree := node("AllOf", leaf("GreaterThan", 3), node("Not", leaf("EqualTo", 5)))
+	var lines []string
+	depth := 0
+	var visitor _DepthVisitor
+	visitor = func(matcher *Matcher) Visitor {
+		if matcher == nil {
+			depth--
+			return nil
+		}
+		lines = append(lines, fmt.Sprintf("%v%v%v", strings.Repeat("  ", depth), matcher.Name(), matcher.Params()))
+		depth++
+		return visitor
+	}
+	tree.Walk(visitor)
+	expected := "AllOf[]\n  GreaterThan[3]\n  Not[]\n    EqualTo[5]"
+	if rendered := strings.Join(lines, "\n"); rendered != expected {
+		t.Errorf("Expected tree:\n%v\nwas:\n%v", expected, rendered)
+	}
+}
+
+type _DepthVisitor func(matcher *Matcher) Visitor
+
+func (f _DepthVisitor) Visit(matcher *Matcher) Visitor {
+	return f(matcher)
+}
+
+func Test_Structure_ofBaseMatchers(t *testing.T) {
+	for matcher, name := range map[*Matcher]string{
+			Matched(): "Matched", DidNotMatch(): "DidNotMatch", Errored(): "Errored",
+			True(): "True", False(): "False", Nil(): "Nil", NonNil(): "NonNil"} {
+		if matcher.Name() != name || len(matcher.Params()) != 0 {
+			t.Errorf("Expected structure %v[], was %v%v", name, matcher.Name(), matcher.Params())
+		}
+	}
+	for matcher, name := range map[*Matcher]string{
+			GreaterThan(5): "GreaterThan", GreaterThanOrEqualTo(5): "GreaterThanOrEqualTo",
+			LessThan(5): "LessThan", LessThanOrEqualTo(5): "LessThanOrEqualTo",
+			EqualTo(5): "EqualTo", NotEqualTo(5): "NotEqualTo", DeepEqualTo(5): "DeepEqualTo"} {
+		if matcher.Name() != name || !reflect.DeepEqual(matcher.Params(), []interface{}{5}) {
+			t.Errorf("Expected structure %v[5], was %v%v", name, matcher.Name(), matcher.Params())
+		}
+	}
+}
@@ -38,7 +38,8 @@ func AnyElement(matcher *base.Matcher) *base.Matcher {
 		}
 		return matcher.Match(v)
 	}
-	return base.NewMatcherf(match, "AnyElement[%v]", matcher)
+	return base.NewMatcherf(match, "AnyElement[%v]", matcher).
+		WithStructure("AnyElement", nil, matcher)
 }
 
 // Returns a matcher that matches on any array or slice input value
@@ -72,7 +73,8 @@ func EveryElement(matcher *base.Matcher) *base.Matcher {
 		return base.NewResultf(true,
 			"Matched all of the %v elements", n)
 	}
-	return base.NewMatcherf(match, "EveryElement[%v]", matcher)
+	return base.NewMatcherf(match, "EveryElement[%v]", matcher).
+		WithStructure("EveryElement", nil, matcher)
 }
 
 func AnyMapElement(matcher *base.Matcher) *base.Matcher {
@@ -97,7 +99,8 @@ func AnyMapElement(matcher *base.Matcher) *base.Matcher {
 		return base.NewResultf(false,
 			"Matched none of the %v elements", len(keys))
 	}
-	return base.NewMatcherf(match, "AnyMapElement[%v]", matcher)
+	return base.NewMatcherf(match, "AnyMapElement[%v]", matcher).
+		WithStructure("AnyMapElement", nil, matcher)
 }
 
 func EveryMapElement(matcher *base.Matcher) *base.Matcher {
@@ -122,7 +125,8 @@ func EveryMapElement(matcher *base.Matcher) *base.Matcher {
 		return base.NewResultf(true,
 			"Matched all of the %v map elements", len(keys))
 	}
-	return base.NewMatcherf(match, "EveryMapElement[%v]", matcher)
+	return base.NewMatcherf(match, "EveryMapElement[%v]", matcher).
+		WithStructure("EveryMapElement", nil, matcher)
 }
 
 type _HasLen interface { Len() int }
@@ -140,7 +144,9 @@ func ToLen(matcher *base.Matcher) *base.Matcher {
 		return base.NewResultf(false,
 			"Can't determine Len() for %T", actual)
 	}
-	return base.NewMatcherf(match, "ToLen[%v]", matcher)
+	return base.NewMatcherf(match, "ToLen[%v]", matcher).
+		WithStructure("ToLen", nil, matcher).
+		WithNegationf("has a length that %v", matcher.Negation())
 }
 
 
@@ -155,6 +161,8 @@ func Empty() *base.Matcher {
 		}
 		return base.NewResultf(false, "Can't determine length of type %T", actual)
 	}
-	return base.NewMatcherf(match, "Empty")
+	return base.NewMatcherf(match, "Empty").
+		WithStructure("Empty", nil).
+		WithNegationf("is not empty")
 }
 
@@ -6,6 +6,7 @@ package collections
 
 import (
 	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
 	. "github.com/rdrdr/hamcrest/core"
 	//"reflect"
 	//"runtime"
@@ -124,3 +125,16 @@ func Test_Empty_onMaps(t *testing.T) {
 	we.CheckThat(hasTwo, Is(Not(Empty())))
 }
 
+
+func Test_structure(t *testing.T) {
+	we := asserter.Using(t)
+	inner := EqualTo(1)
+	for _, matcher := range []*base.Matcher{
+			AnyElement(inner), EveryElement(inner),
+			AnyMapElement(inner), EveryMapElement(inner), ToLen(inner)} {
+		we.CheckThat(len(matcher.Children()), EqualTo(1))
+		we.CheckThat(matcher.Children()[0] == inner, Is(True()))
+	}
+	we.CheckThat(AnyElement(inner).Name(), EqualTo("AnyElement"))
+	we.CheckThat(Empty().Name(), EqualTo("Empty"))
+}
@@ -0,0 +1,266 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/base"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// A self-describing record of invoking a function with explicit
+// arguments.  The function is invoked lazily (at most once), the
+// first time its return values are needed.  Use with Returns and
+// ReturnsError, for example:
+//    we.CheckThat(Calling(strconv.Atoi, "42"), Returns(EqualTo(42), Nil()))
+//    we.CheckThat(Calling(os.Open, "/missing"), ReturnsError(NonNil()))
+type Call struct {
+	function interface{}
+	args []interface{}
+	invoked bool
+	results []interface{}
+	err os.Error
+	panicked *base.Panic
+}
+
+// Creates a Call of the given function with the given arguments.
+// Nil arguments are converted to the zero value of the corresponding
+// parameter type.  If the function is variadic, trailing arguments
+// are collected into its final slice parameter.
+func Calling(function interface{}, args...interface{}) *Call {
+	return &Call{function:function, args:args}
+}
+
+// Returns the values returned by the function, invoking it if it has
+// not already been invoked.  Returns nil if the function could not be
+// invoked or panicked.  (See Err and Panic.)
+func (self *Call) Results() []interface{} {
+	self.invoke()
+	if self.results == nil {
+		return nil
+	}
+	results := make([]interface{}, len(self.results))
+	copy(results, self.results)
+	return results
+}
+
+// Returns the error that prevented the function from being invoked
+// (for example, arguments of the wrong type), or nil.
+func (self *Call) Err() os.Error {
+	self.invoke()
+	return self.err
+}
+
+// Returns the Panic raised by the function, or nil if the function
+// could not be invoked or returned normally.
+func (self *Call) Panic() *base.Panic {
+	self.invoke()
+	return self.panicked
+}
+
+// Implements fmt.Stringer, describing the call (but not its results).
+func (self *Call) String() string {
+	args := make([]string, len(self.args))
+	for i, arg := range self.args {
+		args[i] = fmt.Sprintf("%#v", arg)
+	}
+	return fmt.Sprintf("%v(%v)", _FunctionName(self.function),
+		strings.Join(args, ", "))
+}
+
+// Implements fmt.Formatter.
+func (self *Call) Format(s fmt.State, ch int) {
+	fmt.Fprint(s, self.String())
+}
+
+func _FunctionName(function interface{}) string {
+	if funcValue, ok := reflect.NewValue(function).(*reflect.FuncValue); ok {
+		if fn := runtime.FuncForPC(funcValue.Get()); fn != nil {
+			return fn.Name()
+		}
+	}
+	return fmt.Sprintf("%T", function)
+}
+
+func (self *Call) invoke() {
+	if self.invoked {
+		return
+	}
+	self.invoked = true
+	funcValue, ok := reflect.NewValue(self.function).(*reflect.FuncValue)
+	if !ok || funcValue.IsNil() {
+		self.err = os.NewError(fmt.Sprintf(
+			"Cannot call %T: not a function", self.function))
+		return
+	}
+	argValues, err := _ArgValues(funcValue.Type().(*reflect.FuncType), self.args)
+	if err != nil {
+		self.err = err
+		return
+	}
+	defer func() {
+		if x := recover(); x != nil {
+			self.panicked = base.CapturePanic(x, 0)
+		}
+	}()
+	outValues := funcValue.Call(argValues)
+	results := make([]interface{}, len(outValues))
+	for i, outValue := range outValues {
+		results[i] = outValue.Interface()
+	}
+	self.results = results
+}
+
+// Converts the given arguments to values of the parameter types of
+// the given function type.  (A nil argument becomes the zero value.)
+func _ArgValues(funcType *reflect.FuncType, args []interface{}) (values []reflect.Value, err os.Error) {
+	numIn := funcType.NumIn()
+	numFixed := numIn
+	if funcType.DotDotDot() {
+		numFixed = numIn - 1
+		if len(args) < numFixed {
+			return nil, os.NewError(fmt.Sprintf(
+				"Expected at least %v args, got %v", numFixed, len(args)))
+		}
+	} else if len(args) != numIn {
+		return nil, os.NewError(fmt.Sprintf(
+			"Expected %v args, got %v", numIn, len(args)))
+	}
+	values = make([]reflect.Value, numIn)
+	for i := 0; i < numFixed; i++ {
+		value := reflect.MakeZero(funcType.In(i))
+		if args[i] != nil && !base.Assign(value, args[i]) {
+			return nil, os.NewError(fmt.Sprintf(
+				"Cannot use %T as arg #%v (%v)", args[i], i+1, funcType.In(i)))
+		}
+		values[i] = value
+	}
+	if funcType.DotDotDot() {
+		sliceType := funcType.In(numFixed).(*reflect.SliceType)
+		extra := len(args) - numFixed
+		slice := reflect.MakeSlice(sliceType, extra, extra)
+		for i := 0; i < extra; i++ {
+			arg := args[numFixed + i]
+			if arg != nil && !base.Assign(slice.Elem(i), arg) {
+				return nil, os.NewError(fmt.Sprintf(
+					"Cannot use %T as variadic arg #%v (%v)",
+					arg, numFixed + i + 1, sliceType.Elem()))
+			}
+		}
+		values[numFixed] = slice
+	}
+	return values, nil
+}
+
+// Returns a Matcher that matches a *Call whose function returns
+// exactly as many values as there are matchers, where each return
+// value matches the corresponding matcher.  Every return value is
+// tested, and each Result is reported as a cause.
+func Returns(matchers...*base.Matcher) *base.Matcher {
+	match := func(actual interface{}) *base.Result {
+		call, ok := actual.(*Call)
+		if !ok {
+			return base.NewErrorResultf(
+				"Returns can only be applied to a *Call, got %T", actual)
+		}
+		if result := _CheckCall(call); result != nil {
+			return result
+		}
+		values := call.Results()
+		if len(values) != len(matchers) {
+			return base.NewErrorResultf(
+				"%v returned %v values, but %v matchers were given",
+				call, len(values), len(matchers))
+		}
+		results := make([]*base.Result, len(values))
+		failures := 0
+		for i, value := range values {
+			result := matchers[i].Match(value)
+			results[i] = result
+			if err := result.Err(); err != nil {
+				return base.NewResultf(false,
+					"Error in matcher for return value #%v of %v", i+1, call).
+					WithError(err).
+					WithCauses(results[:i+1]...)
+			}
+			if !result.Matched() {
+				failures++
+			}
+		}
+		if failures > 0 {
+			return base.NewResultf(false,
+				"%v of %v return values of %v did not match: %v",
+				failures, len(values), call, _Values(values)).
+				WithCauses(results...)
+		}
+		return base.NewResultf(true,
+			"all %v return values of %v matched: %v",
+			len(values), call, _Values(values)).
+			WithCauses(results...)
+	}
+	descriptions := make([]interface{}, len(matchers))
+	for index, matcher := range matchers {
+		descriptions[index] = base.Description("[#%v: %v]", index+1, matcher)
+	}
+	return base.NewMatcherf(match, "Returns%v", descriptions).
+		WithStructure("Returns", nil, matchers...)
+}
+
+// Returns a Matcher that matches a *Call whose function's last return
+// value is an os.Error that matches the given matcher.  Other return
+// values are ignored.
+func ReturnsError(errorMatcher *base.Matcher) *base.Matcher {
+	match := func(actual interface{}) *base.Result {
+		call, ok := actual.(*Call)
+		if !ok {
+			return base.NewErrorResultf(
+				"ReturnsError can only be applied to a *Call, got %T", actual)
+		}
+		if result := _CheckCall(call); result != nil {
+			return result
+		}
+		funcType := reflect.Typeof(call.function).(*reflect.FuncType)
+		numOut := funcType.NumOut()
+		errorType := reflect.Typeof((*os.Error)(nil)).(*reflect.PtrType).Elem()
+		if numOut == 0 || funcType.Out(numOut - 1) != errorType {
+			return base.NewErrorResultf(
+				"%v does not return an os.Error as its last value", call)
+		}
+		values := call.Results()
+		err := values[numOut - 1]
+		result := errorMatcher.Match(err)
+		return base.NewResultf(result.Matched(),
+			"%v returned error [%v]", call, err).
+			WithError(result.Err()).
+			WithCauses(result)
+	}
+	return base.NewMatcherf(match, "ReturnsError[%v]", errorMatcher).
+		WithStructure("ReturnsError", nil, errorMatcher)
+}
+
+// Returns a Result if the call could not be completed, or nil if its
+// return values are available.
+func _CheckCall(call *Call) *base.Result {
+	if err := call.Err(); err != nil {
+		return base.NewErrorResultf("Could not invoke %v: %v", call, err)
+	}
+	if panicked := call.Panic(); panicked != nil {
+		return base.NewResultf(false,
+			"%v panicked instead of returning: %v", call, panicked).
+			WithCauses(_StackCause(false, panicked))
+	}
+	return nil
+}
+
+func _Values(values []interface{}) string {
+	descriptions := make([]string, len(values))
+	for i, value := range values {
+		descriptions[i] = fmt.Sprintf("%v", value)
+	}
+	return "(" + strings.Join(descriptions, ", ") + ")"
+}
@@ -0,0 +1,78 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func divide(x, y int) (int, os.Error) {
+	if y == 0 {
+		return 0, os.NewError("division by zero")
+	}
+	return x / y, nil
+}
+
+func sum(prefix string, values...int) string {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return prefix + strconv.Itoa(total)
+}
+
+func Test_Calling_Returns(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(Returns(EqualTo(3), Nil()).Match(Calling(divide, 6, 2)), Matched)
+	we.CheckThat(Returns(EqualTo(4), Nil()).Match(Calling(divide, 6, 2)), DidNotMatch)
+	we.CheckThat(Returns(EqualTo(0), NonNil()).Match(Calling(divide, 6, 0)), Matched)
+	we.CheckThat(Returns(EqualTo(3)).Match(Calling(divide, 6, 2)),
+		Errored.Comment("wrong number of matchers"))
+	logSamples(t, Returns(EqualTo(3), Nil()))
+}
+
+func Test_Calling_ReturnsError(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ReturnsError(Nil()).Match(Calling(divide, 6, 2)), Matched)
+	we.CheckThat(ReturnsError(NonNil()).Match(Calling(divide, 6, 0)), Matched)
+	we.CheckThat(ReturnsError(Nil()).Match(Calling(divide, 6, 0)), DidNotMatch)
+	we.CheckThat(ReturnsError(Nil()).Match(Calling(sum, "x")),
+		Errored.Comment("sum does not return an os.Error"))
+}
+
+func Test_Calling_variadic(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(Returns(EqualTo("x0")).Match(Calling(sum, "x")), Matched)
+	we.CheckThat(Returns(EqualTo("x6")).Match(Calling(sum, "x", 1, 2, 3)), Matched)
+	we.CheckThat(Returns(Anything()).Match(Calling(sum, "x", "y")),
+		Errored.Comment("wrong variadic arg type"))
+}
+
+func Test_Calling_badArguments(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(Returns(Anything(), Anything()).Match(Calling(divide, 6)), Errored)
+	we.CheckThat(Returns(Anything(), Anything()).Match(Calling(divide, "6", 2)), Errored)
+	we.CheckThat(Returns(Anything()).Match(Calling("not a function")), Errored)
+	we.CheckThat(Returns(Anything()).Match("not a call"), Errored)
+}
+
+func Test_Calling_panickingFunction(t *testing.T) {
+	we := asserter.Using(t)
+	call := Calling(func(n int) int { return 1 / n }, 0)
+	we.CheckThat(Returns(Anything()).Match(call), DidNotMatch)
+	we.CheckNonNil(call.Panic(), "should record the panic")
+}
+
+func Test_Calling_invokesOnce(t *testing.T) {
+	we := asserter.Using(t)
+	count := 0
+	call := Calling(func() int { count++; return count })
+	we.CheckThat(Returns(EqualTo(1)).Match(call), Matched)
+	we.CheckThat(Returns(EqualTo(1)).Match(call), Matched)
+	we.CheckThat(count, EqualTo(1))
+}
@@ -0,0 +1,120 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/base"
+	"reflect"
+	"sync"
+)
+
+// Returns a Matcher that decorates another matcher and, whenever the
+// underlying matcher matches, stores the matched input in the variable
+// that dst points to, so that it can be used in later assertions.  For
+// example, to pull an id out of a URL:
+//    var id string
+//    we.AssertThat(url, OnPatternGroup(`/users/(\d+)`, 1)(Capture(&id, Anything())))
+//    we.CheckThat(store.Load(id), NonNil())
+//
+// dst must be a non-nil pointer; if not, this function panics.  If the
+// matched input cannot be assigned to *dst, the Result has an error.
+// (A nil input sets *dst to its zero value.)
+func Capture(dst interface{}, matcher *base.Matcher) *base.Matcher {
+	ptrValue, ok := reflect.NewValue(dst).(*reflect.PtrValue)
+	if !ok || ptrValue.IsNil() {
+		panic(fmt.Sprintf("Capture requires a non-nil pointer, was %T", dst))
+	}
+	elemType := ptrValue.Type().(*reflect.PtrType).Elem()
+	match := func(actual interface{}) *base.Result {
+		result := matcher.Match(actual)
+		if !result.Matched() {
+			return base.NewResultf(false, "did not capture %v", actual).
+				WithError(result.Err()).
+				WithCauses(result)
+		}
+		if actual == nil {
+			ptrValue.Elem().SetValue(reflect.MakeZero(elemType))
+		} else if !base.Assign(ptrValue.Elem(), actual) {
+			return base.NewErrorResultf(
+				"Could not capture %T into a variable of type %v", actual, elemType).
+				WithCauses(result)
+		}
+		return base.NewResultf(true, "captured %v", actual).
+			WithCauses(result)
+	}
+	return base.NewMatcherf(match, "Capture[%v]", matcher).
+		WithStructure("Capture", nil, matcher)
+}
+
+// Records every input matched by its matchers, in the order matched.
+// Useful when a matcher is applied many times (by a collection matcher,
+// for example, or as an argument matcher of a mock) and all of the
+// matched values are wanted:
+//    captor := &Captor{}
+//    we.CheckThat(lines, EachElem(captor.Capture(HasPrefix("ERROR"))))
+//    we.CheckThat(captor.Len(), EqualTo(2))
+//
+// The zero value is an empty Captor, ready to use.  A Captor may be
+// shared between goroutines.
+type Captor struct {
+	lock sync.Mutex
+	values []interface{}
+}
+
+// Returns a Matcher that decorates another matcher and, whenever the
+// underlying matcher matches, records the matched input in this Captor.
+func (self *Captor) Capture(matcher *base.Matcher) *base.Matcher {
+	match := func(actual interface{}) *base.Result {
+		result := matcher.Match(actual)
+		if !result.Matched() {
+			return base.NewResultf(false, "did not capture %v", actual).
+				WithError(result.Err()).
+				WithCauses(result)
+		}
+		self.lock.Lock()
+		self.values = append(self.values, actual)
+		count := len(self.values)
+		self.lock.Unlock()
+		return base.NewResultf(true, "captured %v (value #%v)", actual, count).
+			WithCauses(result)
+	}
+	return base.NewMatcherf(match, "Capture[%v]", matcher).
+		WithStructure("Capture", nil, matcher)
+}
+
+// Returns every captured value, in the order captured.
+func (self *Captor) Values() []interface{} {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	values := make([]interface{}, len(self.values))
+	copy(values, self.values)
+	return values
+}
+
+// Returns the number of captured values.
+func (self *Captor) Len() int {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return len(self.values)
+}
+
+// Returns the most recently captured value, or nil if none has been
+// captured.
+func (self *Captor) Last() interface{} {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if len(self.values) == 0 {
+		return nil
+	}
+	return self.values[len(self.values)-1]
+}
+
+// Discards all captured values.
+func (self *Captor) Reset() {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.values = nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	"testing"
+)
+
+func Test_Capture(t *testing.T) {
+	we := asserter.Using(t)
+	var captured int
+	we.CheckThat(Capture(&captured, GreaterThan(5)).Match(7), Matched)
+	we.CheckThat(captured, EqualTo(7))
+	we.CheckThat(Capture(&captured, GreaterThan(5)).Match(3), DidNotMatch)
+	we.CheckThat(captured, EqualTo(7).Comment("unchanged by a mismatch"))
+	we.CheckThat(Capture(&captured, Anything()).Match("seven"),
+		Errored.Comment("string cannot be stored in an int"))
+	we.CheckThat(captured, EqualTo(7).Comment("unchanged by an error"))
+	logSamples(t, Capture(&captured, GreaterThan(5)))
+}
+
+func Test_Capture_nil(t *testing.T) {
+	we := asserter.Using(t)
+	captured := []int{1}
+	we.CheckThat(Capture(&captured, Anything()).Match(nil), Matched)
+	we.CheckThat(captured, Nil())
+
+	var any interface{}
+	we.CheckThat(Capture(&any, Anything()).Match("x"), Matched)
+	we.CheckThat(any, EqualTo("x"))
+}
+
+func Test_Capture_composed(t *testing.T) {
+	we := asserter.Using(t)
+	var length int
+	toLength := Applying(func(s string) int { return len(s) }, "ToLength")
+	we.CheckThat("hello", toLength(Capture(&length, Anything())))
+	we.CheckThat(length, EqualTo(5))
+}
+
+func Test_Capture_requiresPointer(t *testing.T) {
+	we := asserter.Using(t)
+	panicsWhenCapturing := PanicWhenApplying(func(dst interface{}) {
+		Capture(dst, Anything())
+	}, "Capture")
+	var nilPtr *int
+	we.CheckThat(5, panicsWhenCapturing)
+	we.CheckThat(nilPtr, panicsWhenCapturing)
+	we.CheckThat(new(int), Not(panicsWhenCapturing))
+}
+
+func Test_Captor(t *testing.T) {
+	we := asserter.Using(t)
+	captor := &Captor{}
+	we.CheckNil(captor.Last())
+	matcher := captor.Capture(GreaterThan(2))
+	for _, value := range []int{1, 3, 2, 5} {
+		matcher.Match(value)
+	}
+	we.CheckThat(captor.Len(), EqualTo(2))
+	values := captor.Values()
+	we.AssertThat(len(values), EqualTo(2))
+	we.CheckThat(values[0], EqualTo(3))
+	we.CheckThat(values[1], EqualTo(5))
+	we.CheckThat(captor.Last(), EqualTo(5))
+	captor.Reset()
+	we.CheckThat(captor.Len(), EqualTo(0))
+	logSamples(t, captor.Capture(GreaterThan(2)))
+}
@@ -5,9 +5,7 @@
 package core
 
 import (
-	"fmt"
 	"github.com/rdrdr/hamcrest/base"
-	"reflect"
 )
 
 // Returns a Matcher that matches any input value.
@@ -19,7 +17,7 @@ func init() {
 	match := func (actual interface{}) *base.Result {
 		return base.NewResultf(true, "always matches")
 	}
-	_Anything = base.NewMatcherf(match, "Anything")
+	_Anything = base.NewMatcherf(match, "Anything").WithStructure("Anything", nil)
 }
 
 
@@ -37,90 +35,81 @@ func False() *base.Matcher {
 // functionOrMatcher to panic.
 //
 // functionOrMatcher should either be a function that accepts one
-// parameter or a Matcher.
+// parameter, a function that accepts no parameters, or a Matcher.
+// (See Panics to also match on the recovered value.)
+//
+// If the input cannot be passed to the function, the function is not
+// called, and the matcher cannot be applied (see base.Errored).
 func PanicWhenApplying(functionOrMatcher interface{}, name string) *base.Matcher {
-	var doSomething func(interface{})
-	if matcher, ok := functionOrMatcher.(*base.Matcher); ok {
-		doSomething = func(actual interface{}) { matcher.Match(actual) }
-	} else {
-		value := reflect.NewValue(functionOrMatcher)
-		if funcValue, ok := value.(*reflect.FuncValue); ok {
-			funcType := funcValue.Type().(*reflect.FuncType)
-			numIn := funcType.NumIn()
-			if numIn == 0 {
-				panic(fmt.Sprintf("func must accept a single arg, was %T", functionOrMatcher))
-			}
-			inType := funcType.In(0)
-			switch {
-			case numIn == 1: // always ok
-			case numIn == 2 && funcType.DotDotDot(): // ok
-			default:
-				panic(fmt.Sprintf("func must accept a single arg, was %T", functionOrMatcher))
-			}
-			doSomething = func(actual interface{}) {
-				actualValue := reflect.NewValue(actual)
-				argValues := make([]reflect.Value, numIn, numIn)
-				if numIn == 1 && funcType.DotDotDot() {
-					inSlice := reflect.MakeSlice(inType.(*reflect.SliceType), 1, 1)
-					inValue := inSlice.Elem(0)
-					inValue.SetValue(actualValue)
-					argValues[0] = inSlice
-				} else {
-					inValue := reflect.MakeZero(inType)
-					inValue.SetValue(actualValue)
-					argValues[0] = inValue
-					if numIn == 2 && funcType.DotDotDot() {
-						inType2 := funcType.In(1)
-						argValues[1] = reflect.MakeSlice(inType2.(*reflect.SliceType), 0, 0)
-					}
-				}
-				funcValue.Call(argValues)
-			}
+	invoke := _Invoker(functionOrMatcher)
+	match := func (actual interface{}) *base.Result {
+		call, err := invoke(actual)
+		if err != nil {
+			return base.NewErrorResultf("%v", err)
 		}
+		if panicked := _Attempt(call); panicked != nil {
+			return base.NewResultf(true, "Panicked with %v", panicked).
+				WithCauses(_StackCause(true, panicked))
+		}
+		return base.NewResultf(false, "Did not panic")
 	}
-	match := func (actual interface{}) (result *base.Result) {
-		defer func() {
-			if recover() != nil {
-				result = base.NewResultf(true, "Panicked")
-			}
-		}()
-		doSomething(actual)
-		result = base.NewResultf(false, "Did not panic")
-		return
-	}
-	return base.NewMatcherf(match,
-		"PanicWhenApplying[%v]", name)
+	matcher := base.NewMatcherf(match, "PanicWhenApplying[%v]", name)
+	return _WithApplied(matcher, "PanicWhenApplying", functionOrMatcher)
 }
 
 
 // Returns a Matcher that decorates another matcher and only matches
 // when the underlying matcher does not match (and vice versa).
+//
+// If the underlying matcher has a negated description (see
+// base.Matcher.WithNegationf), Not describes itself and its Results
+// with it:  Not(GreaterThan(5)) is described as "is not greater than 5".
+//
+// If the underlying matcher could not be applied (its Result has a
+// non-nil Err()), the error is propagated rather than inverted.
 func Not(matcher *base.Matcher) *base.Matcher {
+	negation := matcher.Negation()
 	match := func (actual interface{}) *base.Result {
 		result := matcher.Match(actual)
+		if err := result.Err(); err != nil {
+			return base.NewResultf(false,
+				"'Not' could not be applied because inner matcher failed with an error: %v", matcher).
+				WithError(err).
+				WithCauses(result)
+		}
 		if result.Matched() {
 			return base.NewResultf(false,
-				"'Not' failed because inner matcher passed: %v", matcher).
+				"expected a value that %v, but was [%v]", negation, actual).
 				WithCauses(result)
 		}
-		return base.NewResultf(true,
-			"'Not' passed because inner matcher failed: %v", matcher).
+		return base.NewResultf(true, "[%v] %v", actual, negation).
 			WithCauses(result)
 	}
-	return base.NewMatcherf(match, "Not[%v]", matcher)
+	var description *base.Matcher
+	if matcher.HasNegation() {
+		description = base.NewMatcherf(match, "%v", negation)
+	} else {
+		description = base.NewMatcherf(match, "Not[%v]", matcher)
+	}
+	return description.
+		WithStructure("Not", nil, matcher).
+		WithNegationf("matches %v", matcher)
 }
 
-// Returns a Matcher that decorates another matcher.
+// Returns a Matcher that decorates another matcher.  Its negated
+// description is that of the underlying matcher.
 func Is(matcher *base.Matcher) *base.Matcher {
 	match := func (actual interface{}) *base.Result {
 		result := matcher.Match(actual)
 		return base.NewResult(result.Matched(), result).
+			WithError(result.Err()).
 			WithCauses(result.Causes()...)
 	}
-	return base.NewMatcherf(match, "Is[%v]", matcher)
+	return base.NewMatcherf(match, "Is[%v]", matcher).
+		WithStructure("Is", nil, matcher).
+		WithNegationf("%v", matcher.Negation())
 }
 
-
 // Returns a Matcher that matches if the actual value is nil
 // or the nil value of its type.  (Note that this is *not*
 // equivalent to DeeplyEqualTo(nil).)
@@ -183,13 +172,21 @@ func NotEqualTo(expected interface{}) *base.Matcher {
 // Returns a short-circuiting Matcher that matches whenever all of
 // the given matchers match a given input value.  If any component
 // matcher fails to match an input value, later matchers are not
-// attempted.
+// attempted.  If any component matcher fails with an error, the
+// error is propagated.
 func AllOf(matchers...*base.Matcher) *base.Matcher {
 	match := func (actual interface{}) *base.Result {
 		var results []*base.Result
 		for index, matcher := range matchers {
 			result := matcher.Match(actual)
-			results := append(results, result)
+			results = append(results, result)
+			if err := result.Err(); err != nil {
+				return base.NewResultf(false,
+					"Error in matcher %v of %v: [%v]",
+					index+1, len(matchers), matcher).
+					WithError(err).
+					WithCauses(results...)
+			}
 			if !result.Matched() {
 				return base.NewResultf(false,
 					"Failed matcher %v of %v: [%v]",
@@ -205,19 +202,28 @@ func AllOf(matchers...*base.Matcher) *base.Matcher {
 	for index, matcher := range matchers {
 		descriptions[index] = base.Description("[#%v: %v]", index+1, matcher)
 	}
-	return base.NewMatcherf(match, "AllOf%v", descriptions)
+	return base.NewMatcherf(match, "AllOf%v", descriptions).
+		WithStructure("AllOf", nil, matchers...)
 }
 
-// Returns a short-circuiting Matcher that matches whenever all of
+// Returns a short-circuiting Matcher that matches whenever any of
 // the given matchers match a given input value.  If any component
-// matcher fails to match an input value, later matchers are not
-// attempted.
+// matcher matches an input value, later matchers are not attempted.
+// If a component matcher fails with an error before any matcher
+// matches, the error is propagated.
 func AnyOf(matchers...*base.Matcher) *base.Matcher {
 	match := func (actual interface{}) *base.Result {
 		var results []*base.Result
 		for index, matcher := range matchers {
 			result := matcher.Match(actual)
-			results := append(results, result)
+			results = append(results, result)
+			if err := result.Err(); err != nil {
+				return base.NewResultf(false,
+					"Error in matcher %v of %v: [%v]",
+					index+1, len(matchers), matcher).
+					WithError(err).
+					WithCauses(results...)
+			}
 			if result.Matched() {
 				return base.NewResultf(true,
 					"Matched on matcher %v of %v: [%v]",
@@ -233,7 +239,8 @@ func AnyOf(matchers...*base.Matcher) *base.Matcher {
 	for index, matcher := range matchers {
 		descriptions[index] = base.Description("[#%v: %v]", index+1, matcher)
 	}
-	return base.NewMatcherf(match, "AnyOf%v", descriptions)
+	return base.NewMatcherf(match, "AnyOf%v", descriptions).
+		WithStructure("AnyOf", nil, matchers...)
 }
 
 
@@ -247,56 +254,22 @@ func AnyOf(matchers...*base.Matcher) *base.Matcher {
 // The given function must be able to accept a single argument and
 // return a single argument.
 func Applying(function interface{}, name string) func(*base.Matcher) *base.Matcher  {
-	funcValue := reflect.NewValue(function).(*reflect.FuncValue)
-	funcType := funcValue.Type().(*reflect.FuncType)
-	numIn := funcType.NumIn()
-	numOut := funcType.NumOut()
-	if numIn == 0 {
-		panic(fmt.Sprintf("function must accept at least one value, was %v by function %v", numIn, function))
-	}
-	if numOut == 0 {
-		panic(fmt.Sprintf("function must return at least one value, was %v by function %v", numOut, function))
+	if err := base.CheckApplicable(function); err != nil {
+		panic(err.String())
 	}
 	return func(matcher *base.Matcher) *base.Matcher {
 		match := func (actual interface{}) *base.Result {
-			Assign := func(dst reflect.Value, src reflect.Value) (ok bool) {
-				defer func() { recover(); }()
-				dst.SetValue(src)
-				ok = true
-				return
-			}
-			actualValue := reflect.NewValue(actual)
-			argValues := make([]reflect.Value, numIn, numIn)
-			if numIn > 0 {
-				inType := funcType.In(0)
-				if numIn == 1 && funcType.DotDotDot() {
-					inSlice := reflect.MakeSlice(inType.(*reflect.SliceType), 1, 1)
-					if !Assign(inSlice.Elem(0), actualValue) {
-						return base.NewResultf(false,
-							"Cannot use %T as input to %T", actual, function)
-					}
-					argValues[0] = inSlice
-				} else {
-					inValue := reflect.MakeZero(inType)
-					if !Assign(inValue, actualValue) {
-						return base.NewResultf(false,
-							"Cannot use %T as input to %T", actual, function)
-					}
-					argValues[0] = inValue
-				}
-				for i := 1; i < numIn; i++ {
-					inType = funcType.In(i)
-					argValues[i] = reflect.MakeZero(inType)
-				}
+			out, err := base.Apply(function, actual)
+			if err != nil {
+				return base.NewErrorResultf("%v", err)
 			}
-			outValues := funcValue.Call(argValues)
-			outValue := outValues[0]
-			out := outValue.Interface()
 			result := matcher.Match(out)
 			return base.NewResultf(result.Matched(),
 				"%v(%#v) = %v", name, actual, out).
+				WithError(result.Err()).
 				WithCauses(result)
 		}
-		return base.NewMatcherf(match, "%v[%v]", name, matcher)
+		return base.NewMatcherf(match, "%v[%v]", name, matcher).
+			WithStructure(name, nil, matcher)
 	}
 }
@@ -128,12 +128,31 @@ func Test_Not(t *testing.T) {
 	logSamples(t, matcher)
 }
 
+func Test_Not_usesNegatedDescriptions(t *testing.T) {
+	we := asserter.Using(t)
+	matcher := Not(GreaterThan(5))
+	we.CheckThat(matcher.String(), EqualTo("is not greater than 5"))
+	we.CheckThat(matcher.Match(3).String(), EqualTo("[3] is not greater than 5"))
+	we.CheckThat(matcher.Match(7).String(),
+		EqualTo("expected a value that is not greater than 5, but was [7]"))
+	we.CheckThat(matcher.Negation().String(), EqualTo("matches GreaterThan(5)"))
+	we.CheckThat(Not(matcher).String(), EqualTo("matches GreaterThan(5)"))
+	we.CheckThat(Not(matcher).Match(7), Matched)
+
+	plain := base.NewMatcherf(func(actual interface{}) bool { return actual == 3 }, "IsThree")
+	we.CheckThat(Not(plain).String(), EqualTo("Not[IsThree]"))
+	we.CheckThat(Not(plain).Match(3).String(),
+		EqualTo("expected a value that does not match IsThree, but was [3]"))
+}
+
 func Test_Is(t *testing.T) {
 	we := asserter.Using(t)
 	matcher := Is(True())
 	we.CheckThat(matcher.Match(true), Matched)
 	we.CheckThat(matcher.Match(false), DidNotMatch)
 	logSamples(t, matcher)
+	we.CheckThat(Is(LessThan(2)).Negation().String(), EqualTo("is not less than 2"))
+	we.CheckThat(Not(Is(LessThan(2))).String(), EqualTo("is not less than 2"))
 }
 
 func Test_Nil(t *testing.T) {
@@ -324,13 +343,13 @@ func Test_PanicWhen_onFunctionAcceptingBool(t *testing.T) {
 	}, "PanicOnFalse")
 
 	functionInvoked = false
-	we.CheckThat(PanicOnFalse.Match("true"), Matched.
-		Comment("Should panic when can't invoke function"))
+	we.CheckThat(PanicOnFalse.Match("true"), Errored.
+		Comment("Can't invoke function with string"))
 	we.CheckFalse(functionInvoked, "Shouldn't have invoked function")
 	
 	functionInvoked = false
-	we.CheckThat(PanicOnFalse.Match(nil), Matched.
-		Comment("Can't invoke function with string"))
+	we.CheckThat(PanicOnFalse.Match(nil), Errored.
+		Comment("Can't invoke function with nil"))
 	we.CheckFalse(functionInvoked, "Shouldn't have invoked function")
 	
 	functionInvoked = false
@@ -356,8 +375,8 @@ func Test_PanicWhen_onFunctionAcceptingOneArgDotDotDot(t *testing.T) {
 	}, "Disallow13")
 
 	functionInvoked = false
-	we.CheckThat(PanicOn13.Match("thirteen"), Matched.
-		Comment("Should panic when can't invoke function"))
+	we.CheckThat(PanicOn13.Match("thirteen"), Errored.
+		Comment("Can't invoke function with string"))
 	we.CheckFalse(functionInvoked, "Shouldn't have invoked function")
 	
 	functionInvoked = false
@@ -383,8 +402,8 @@ func Test_PanicWhen_onFunctionAcceptingTwoArgsDotDotDot(t *testing.T) {
 	}, "Disallow13")
 
 	functionInvoked = false
-	we.CheckThat(PanicOn13.Match("thirteen"), Matched.
-		Comment("Should panic when can't invoke function"))
+	we.CheckThat(PanicOn13.Match("thirteen"), Errored.
+		Comment("Can't invoke function with string"))
 	we.CheckFalse(functionInvoked, "Shouldn't have invoked function")
 	
 	functionInvoked = false
@@ -399,3 +418,64 @@ func Test_PanicWhen_onFunctionAcceptingTwoArgsDotDotDot(t *testing.T) {
 }
 
 
+var Errored = base.Errored()
+
+func Test_Not_propagatesErrors(t *testing.T) {
+	we := asserter.Using(t)
+	broken := base.NewMatcherf(func(s string) bool { return true }, "OnlyStrings")
+	we.CheckThat(Not(broken).Match(42), Errored.Comment("must not invert an error"))
+	we.CheckThat(Not(Not(broken)).Match(42), Errored)
+	we.CheckThat(Not(broken).Match("42"), DidNotMatch)
+	we.CheckThat(Is(broken).Match(42), Errored)
+}
+
+func Test_AllOf_AnyOf_propagateErrors(t *testing.T) {
+	we := asserter.Using(t)
+	yes, no := Anything(), Not(Anything())
+	broken := base.NewMatcherf(func(s string) bool { return true }, "OnlyStrings")
+	we.CheckThat(AllOf(yes, broken, yes).Match(42), Errored)
+	we.CheckThat(AllOf(no, broken).Match(42), Not(Errored).Comment("short-circuits before error"))
+	we.CheckThat(AnyOf(no, broken, yes).Match(42), Errored)
+	we.CheckThat(AnyOf(yes, broken).Match(42), Matched)
+}
+
+func Test_structure(t *testing.T) {
+	we := asserter.Using(t)
+	greater, less := GreaterThan(3), LessThan(10)
+	allOf := AllOf(greater, Not(less))
+	we.CheckThat(allOf.Name(), EqualTo("AllOf"))
+	children := allOf.Children()
+	we.CheckThat(len(children), EqualTo(2))
+	we.CheckThat(children[0] == greater, Is(True()))
+	we.CheckThat(children[1].Name(), EqualTo("Not"))
+	we.CheckThat(children[1].Children()[0] == less, Is(True()))
+	we.CheckThat(greater.Params(), DeepEqualTo([]interface{}{3}))
+
+	var names []string
+	allOf.Inspect(func(matcher *base.Matcher) bool {
+		names = append(names, matcher.Name())
+		return true
+	})
+	we.CheckThat(names, DeepEqualTo([]string{"AllOf", "GreaterThan", "Not", "LessThan"}))
+
+	we.CheckThat(AnyOf(Anything()).Name(), EqualTo("AnyOf"))
+	we.CheckThat(Is(Anything()).Children()[0] == Anything(), Is(True()))
+	toLength := Applying(func(s string) int { return len(s) }, "ToLength")
+	we.CheckThat(toLength(greater).Name(), EqualTo("ToLength"))
+	we.CheckThat(toLength(greater).Children()[0] == greater, Is(True()))
+}
+
+func Test_structure_ofMatchersApplyingFunctionsOrMatchers(t *testing.T) {
+	we := asserter.Using(t)
+	panicky := func() { panic("!") }
+	matcher := Panics(panicky, Anything())
+	we.CheckThat(matcher.Name(), EqualTo("Panics"))
+	we.CheckThat(len(matcher.Params()), EqualTo(1))
+	we.CheckThat(matcher.Children()[0] == Anything(), Is(True()))
+
+	inner := EqualTo(1)
+	matcher = DoesNotPanic(inner)
+	we.CheckThat(len(matcher.Params()), EqualTo(0))
+	we.CheckThat(matcher.Children()[0] == inner, Is(True()))
+	we.CheckThat(PanicWhenApplying(inner, "inner").Children()[0] == inner, Is(True()))
+}
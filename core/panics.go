@@ -0,0 +1,191 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/base"
+	"os"
+	"reflect"
+)
+
+// Returns a Matcher that matches on values that cause the given
+// functionOrMatcher to panic with a value that matches valueMatcher.
+//
+// functionOrMatcher may be a Matcher, a function that accepts one
+// parameter (which is given the input value), or a function that
+// accepts no parameters (which is simply invoked, ignoring the input
+// value).  For example:
+//    Panics(func() { var m map[int]int; m[0] = 1 }, Anything())
+//    Panics(regexp.MustCompile, ToString(Contains("regexp")))
+//
+// When the function panics, the Result's causes include the
+// Result of valueMatcher and the stack at the point of the panic.
+// If the input value cannot be passed to the function, the function
+// is not called, and the matcher cannot be applied (see base.Errored).
+func Panics(functionOrMatcher interface{}, valueMatcher *base.Matcher) *base.Matcher {
+	invoke := _Invoker(functionOrMatcher)
+	match := func(actual interface{}) *base.Result {
+		call, err := invoke(actual)
+		if err != nil {
+			return base.NewErrorResultf("%v", err)
+		}
+		panicked := _Attempt(call)
+		if panicked == nil {
+			return base.NewResultf(false, "Did not panic")
+		}
+		result := valueMatcher.Match(panicked.Value())
+		if result.Matched() {
+			return base.NewResultf(true,
+				"Panicked with %v, which matched", panicked).
+				WithCauses(result, _StackCause(true, panicked))
+		}
+		return base.NewResultf(false,
+			"Panicked with %v, which did not match", panicked).
+			WithError(result.Err()).
+			WithCauses(result, _StackCause(false, panicked))
+	}
+	matcher := base.NewMatcherf(match, "Panics[%T][%v]",
+		functionOrMatcher, valueMatcher)
+	return _WithApplied(matcher, "Panics", functionOrMatcher, valueMatcher)
+}
+
+// Returns a Matcher that matches input values that are functions
+// accepting no arguments (func()) that, when invoked, panic with an
+// os.Error value (such as a runtime.Error) that matches the given
+// errorMatcher.
+func PanicsWith(errorMatcher *base.Matcher) *base.Matcher {
+	match := func(actual interface{}) *base.Result {
+		function, ok := actual.(func())
+		if !ok {
+			return base.NewErrorResultf(
+				"PanicsWith can only invoke a func(), got %T", actual)
+		}
+		panicked := _Attempt(function)
+		if panicked == nil {
+			return base.NewResultf(false, "Did not panic")
+		}
+		err, ok := panicked.Value().(os.Error)
+		if !ok {
+			return base.NewResultf(false,
+				"Panicked with %v, which is not an os.Error", panicked).
+				WithCauses(_StackCause(false, panicked))
+		}
+		result := errorMatcher.Match(err)
+		if result.Matched() {
+			return base.NewResultf(true,
+				"Panicked with error %v, which matched", panicked).
+				WithCauses(result, _StackCause(true, panicked))
+		}
+		return base.NewResultf(false,
+			"Panicked with error %v, which did not match", panicked).
+			WithError(result.Err()).
+			WithCauses(result, _StackCause(false, panicked))
+	}
+	return base.NewMatcherf(match, "PanicsWith[%v]", errorMatcher).
+		WithStructure("PanicsWith", nil, errorMatcher)
+}
+
+// Returns a Matcher that matches on values for which the given
+// functionOrMatcher completes without panicking.  Accepts the same
+// kinds of functionOrMatcher as Panics.  When the function does panic,
+// the Result describes the value that was raised and its stack.  As with
+// Panics, an input that cannot be passed to the function is an error.
+func DoesNotPanic(functionOrMatcher interface{}) *base.Matcher {
+	invoke := _Invoker(functionOrMatcher)
+	match := func(actual interface{}) *base.Result {
+		call, err := invoke(actual)
+		if err != nil {
+			return base.NewErrorResultf("%v", err)
+		}
+		if panicked := _Attempt(call); panicked != nil {
+			return base.NewResultf(false, "Panicked with %v", panicked).
+				WithCauses(_StackCause(false, panicked))
+		}
+		return base.NewResultf(true, "Did not panic")
+	}
+	matcher := base.NewMatcherf(match, "DoesNotPanic[%T]", functionOrMatcher)
+	return _WithApplied(matcher, "DoesNotPanic", functionOrMatcher)
+}
+
+// Calls the given function, returning the captured Panic if it panics
+// (or nil, if it does not).
+func _Attempt(call func()) (panicked *base.Panic) {
+	defer func() {
+		if x := recover(); x != nil {
+			panicked = base.CapturePanic(x, 0)
+		}
+	}()
+	call()
+	return
+}
+
+// Creates a Result that describes the stack of a captured Panic, for
+// use as a cause of a panic-detecting matcher's Result.
+func _StackCause(matched bool, panicked *base.Panic) *base.Result {
+	return base.NewResultf(matched, "%v", panicked.StackTrace())
+}
+
+// Adds a structured description to a matcher that applies the given
+// functionOrMatcher:  a function is a parameter, and a Matcher is a
+// child (before any other children).
+func _WithApplied(matcher *base.Matcher, name string, functionOrMatcher interface{},
+		children...*base.Matcher) *base.Matcher {
+	if applied, ok := functionOrMatcher.(*base.Matcher); ok {
+		return matcher.WithStructure(name, nil, append([]*base.Matcher{applied}, children...)...)
+	}
+	return matcher.WithStructure(name, []interface{}{functionOrMatcher}, children...)
+}
+
+// Converts a Matcher, a one-argument function or a zero-argument
+// function into a function that prepares to apply it to an input
+// value:  it returns a function that makes the call, or an error if
+// the input cannot be passed to the function (in which case nothing is
+// called).  Panics if functionOrMatcher is none of these.
+func _Invoker(functionOrMatcher interface{}) func(interface{}) (func(), os.Error) {
+	if matcher, ok := functionOrMatcher.(*base.Matcher); ok {
+		return func(actual interface{}) (func(), os.Error) {
+			return func() { matcher.Match(actual) }, nil
+		}
+	}
+	funcValue, ok := reflect.NewValue(functionOrMatcher).(*reflect.FuncValue)
+	if !ok {
+		panic(fmt.Sprintf("must be a func or Matcher, was %T", functionOrMatcher))
+	}
+	funcType := funcValue.Type().(*reflect.FuncType)
+	numIn := funcType.NumIn()
+	switch {
+	case numIn == 0:
+		return func(actual interface{}) (func(), os.Error) {
+			return func() { funcValue.Call(nil) }, nil
+		}
+	case numIn == 1: // always ok
+	case numIn == 2 && funcType.DotDotDot(): // ok
+	default:
+		panic(fmt.Sprintf("func must accept zero or one arg, was %T", functionOrMatcher))
+	}
+	inType := funcType.In(0)
+	return func(actual interface{}) (func(), os.Error) {
+		argValues := make([]reflect.Value, numIn, numIn)
+		var inValue reflect.Value
+		if numIn == 1 && funcType.DotDotDot() {
+			inSlice := reflect.MakeSlice(inType.(*reflect.SliceType), 1, 1)
+			inValue = inSlice.Elem(0)
+			argValues[0] = inSlice
+		} else {
+			inValue = reflect.MakeZero(inType)
+			argValues[0] = inValue
+			if numIn == 2 && funcType.DotDotDot() {
+				inType2 := funcType.In(1)
+				argValues[1] = reflect.MakeSlice(inType2.(*reflect.SliceType), 0, 0)
+			}
+		}
+		if !base.Assign(inValue, actual) {
+			return nil, os.NewError(fmt.Sprintf(
+				"Cannot use %T as input to %T", actual, functionOrMatcher))
+		}
+		return func() { funcValue.Call(argValues) }, nil
+	}
+}
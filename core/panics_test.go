@@ -0,0 +1,88 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	"os"
+	"testing"
+)
+
+func Test_Panics_onZeroArgFunction(t *testing.T) {
+	we := asserter.Using(t)
+	explode := func() { panic("kaboom") }
+	fizzle := func() { }
+	we.CheckThat(Panics(explode, EqualTo("kaboom")).Match(nil), Matched)
+	we.CheckThat(Panics(explode, EqualTo("fizzle")).Match(nil), DidNotMatch)
+	we.CheckThat(Panics(fizzle, Anything()).Match(nil), DidNotMatch)
+	logSamples(t, Panics(explode, EqualTo("kaboom")))
+}
+
+func Test_Panics_onOneArgFunction(t *testing.T) {
+	we := asserter.Using(t)
+	PanicsOn13 := Panics(func(n int) {
+		if n == 13 {
+			panic(n)
+		}
+	}, EqualTo(13))
+	we.CheckThat(PanicsOn13.Match(12), DidNotMatch)
+	we.CheckThat(PanicsOn13.Match(13), Matched)
+	logSamples(t, PanicsOn13)
+}
+
+func Test_Panics_includesStackCause(t *testing.T) {
+	we := asserter.Using(t)
+	result := Panics(func() { panic("kaboom") }, Anything()).Match(nil)
+	we.CheckThat(result, Matched)
+	we.CheckThat(len(result.Causes()), EqualTo(2).Comment("value and stack causes"))
+}
+
+func Test_PanicsWith(t *testing.T) {
+	we := asserter.Using(t)
+	IsBadThing := EqualTo(os.EINVAL)
+	we.CheckThat(PanicsWith(IsBadThing).Match(func() { panic(os.EINVAL) }), Matched)
+	we.CheckThat(PanicsWith(IsBadThing).Match(func() { panic(os.EPERM) }), DidNotMatch)
+	we.CheckThat(PanicsWith(Anything()).Match(func() { panic("not an error") }), DidNotMatch)
+	we.CheckThat(PanicsWith(Anything()).Match(func() { }), DidNotMatch)
+	we.CheckThat(PanicsWith(Anything()).Match("not a func"), Errored)
+	we.CheckThat(PanicsWith(Anything()).Match(func() {
+			var m map[int]int
+			m[0] = 1
+		}), Matched.Comment("runtime errors are os.Errors"))
+}
+
+func Test_DoesNotPanic(t *testing.T) {
+	we := asserter.Using(t)
+	DoesNotPanicOn13 := DoesNotPanic(func(n int) {
+		if n == 13 {
+			panic("Superstition")
+		}
+	})
+	we.CheckThat(DoesNotPanicOn13.Match(12), Matched)
+	we.CheckThat(DoesNotPanicOn13.Match(13), DidNotMatch)
+	we.CheckThat(DoesNotPanic(func() {}).Match(nil), Matched)
+	logSamples(t, DoesNotPanicOn13)
+}
+
+func Test_Panics_inputOfWrongTypeIsAnError(t *testing.T) {
+	we := asserter.Using(t)
+	invoked := false
+	panicOn13 := func(n int) {
+		invoked = true
+		if n == 13 {
+			panic(n)
+		}
+	}
+	we.CheckThat(Panics(panicOn13, Anything()).Match("thirteen"), Errored)
+	we.CheckThat(Panics(panicOn13, Anything()).Match(nil), Errored)
+	we.CheckThat(DoesNotPanic(panicOn13).Match("thirteen"), Errored)
+	we.CheckThat(DoesNotPanic(panicOn13).Match(nil), Errored)
+	we.CheckFalse(invoked, "should not invoke the function")
+
+	result := DoesNotPanic(panicOn13).Match("thirteen")
+	we.CheckThat(result.String(), EqualTo("Cannot use string as input to func(int)"))
+	we.CheckThat(DoesNotPanic(func(p *int) {}).Match(nil), Matched.
+		Comment("nil can be passed as a pointer"))
+}
@@ -0,0 +1,302 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// The kind of change represented by an Edit.
+type Op int
+
+const (
+	Equal = Op(iota)
+	Delete
+	Insert
+)
+
+// A single line of a line-by-line comparison:  a line common to both
+// inputs (Equal), a line only in the first input (Delete) or a line
+// only in the second input (Insert).
+type Edit struct {
+	Op Op
+	Line string
+}
+
+// Splits s into lines, without their trailing newlines.  A final
+// newline does not produce an additional empty line.
+func SplitLines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	lines := strings.Split(s, "\n", -1)
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Computes a minimal line-by-line edit script that transforms the
+// lines of from into the lines of to.  Within each run of changes,
+// deletions come before insertions.
+//
+// Uses Myers' O((n+m)D) difference algorithm, in linear space, after
+// trimming the lines that the inputs have in common at each end, so
+// that long inputs with few differences are compared quickly.
+func Lines(from, to []string) []Edit {
+	edits := _Diff(from, to, make([]Edit, 0, len(from) + len(to)))
+	// Order each run of changes as deletions, then insertions.
+	for start := 0; start < len(edits); {
+		if edits[start].Op == Equal {
+			start++
+			continue
+		}
+		end := start
+		var inserted []Edit
+		for i := start; i < len(edits) && edits[i].Op != Equal; i++ {
+			if edits[i].Op == Delete {
+				edits[end] = edits[i]
+				end++
+			} else {
+				inserted = append(inserted, edits[i])
+			}
+		}
+		end += copy(edits[end:], inserted)
+		start = end
+	}
+	return edits
+}
+
+// Appends to edits a minimal edit script from from to to.
+func _Diff(from, to []string, edits []Edit) []Edit {
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+	for _, line := range from[:prefix] {
+		edits = append(edits, Edit{Equal, line})
+	}
+	from, to = from[prefix:], to[prefix:]
+	suffix := 0
+	for suffix < len(from) && suffix < len(to) &&
+			from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+	common := from[len(from)-suffix:]
+	from, to = from[:len(from)-suffix], to[:len(to)-suffix]
+	switch {
+	case len(from) == 0:
+		for _, line := range to {
+			edits = append(edits, Edit{Insert, line})
+		}
+	case len(to) == 0:
+		for _, line := range from {
+			edits = append(edits, Edit{Delete, line})
+		}
+	default:
+		// With no common prefix or suffix, at least two edits are
+		// needed, so both halves are smaller problems.
+		x0, y0, x1, y1 := _MiddleSnake(from, to)
+		edits = _Diff(from[:x0], to[:y0], edits)
+		for _, line := range from[x0:x1] {
+			edits = append(edits, Edit{Equal, line})
+		}
+		edits = _Diff(from[x1:], to[y1:], edits)
+	}
+	for _, line := range common {
+		edits = append(edits, Edit{Equal, line})
+	}
+	return edits
+}
+
+// Finds the middle snake of a shortest edit script from a to b:  a run
+// of common lines, from a[x0:x1] (and b[y0:y1]), that some shortest
+// edit script passes through with half of its edits on each side.
+// Searches forward from the start and backward from the end at once,
+// keeping only the furthest point reached on each diagonal (k = x - y
+// going forward, and c = x - y in the reversed inputs going backward).
+func _MiddleSnake(a, b []string) (x0, y0, x1, y1 int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	max := (n + m + 1) / 2
+	offset := max + 1
+	forward := make([]int, 2 * max + 3)
+	backward := make([]int, 2 * max + 3)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1 // not reached
+	}
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := _Furthest(forward, offset, k, d, n, m)
+			if x < 0 {
+				continue
+			}
+			startX, startY := x, x - k
+			y := startY
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x + 1, y + 1
+			}
+			forward[offset + k] = x
+			if c := delta - k; delta % 2 != 0 && -(d - 1) <= c && c <= d - 1 &&
+					backward[offset + c] >= 0 && x + backward[offset + c] >= n {
+				return startX, startY, x, y
+			}
+		}
+		for c := -d; c <= d; c += 2 {
+			x := _Furthest(backward, offset, c, d, n, m)
+			if x < 0 {
+				continue
+			}
+			startX, startY := x, x - c
+			y := startY
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x, y = x + 1, y + 1
+			}
+			backward[offset + c] = x
+			if k := delta - c; delta % 2 == 0 && -d <= k && k <= d &&
+					forward[offset + k] >= 0 && forward[offset + k] + x >= n {
+				return n - x, m - y, n - startX, m - startY
+			}
+		}
+	}
+	panic("unreachable")
+}
+
+// Returns the furthest x on diagonal k (within the n by m grid) that
+// can be reached with one more edit from the points reached with d-1
+// edits, or -1 if there is none.  At d = 0, returns the origin.
+func _Furthest(v []int, offset, k, d, n, m int) int {
+	if d == 0 {
+		return 0
+	}
+	best := -1
+	if x := v[offset + k + 1]; x >= 0 && x - k <= m { // down from k+1
+		best = x
+	}
+	if x := v[offset + k - 1] + 1; x > 0 && x <= n && x > best { // right from k-1
+		best = x
+	}
+	return best
+}
+
+// Returns true if the edit script contains no changes.
+func Unchanged(edits []Edit) bool {
+	for _, edit := range edits {
+		if edit.Op != Equal {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns a unified diff of from and to, with the given number of
+// lines of context around each change, using fromName and toName as
+// the file labels.  Lines are written using Escape.  Returns the empty
+// string if from and to have the same lines.
+func Unified(fromName, toName, from, to string, context int) string {
+	edits := Lines(SplitLines(from), SplitLines(to))
+	if Unchanged(edits) {
+		return ""
+	}
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "--- %v\n+++ %v\n", fromName, toName)
+	for _, hunk := range _Hunks(edits, context) {
+		hunk.write(&buffer)
+	}
+	return buffer.String()
+}
+
+type _Hunk struct {
+	fromStart, fromCount int
+	toStart, toCount int
+	edits []Edit
+}
+
+func (self *_Hunk) write(buffer *bytes.Buffer) {
+	fmt.Fprintf(buffer, "@@ -%v +%v @@\n",
+		_Range(self.fromStart, self.fromCount),
+		_Range(self.toStart, self.toCount))
+	for _, edit := range self.edits {
+		switch edit.Op {
+		case Equal:
+			buffer.WriteString(" ")
+		case Delete:
+			buffer.WriteString("-")
+		case Insert:
+			buffer.WriteString("+")
+		}
+		buffer.WriteString(Escape(edit.Line))
+		buffer.WriteString("\n")
+	}
+}
+
+// Formats a hunk range as in GNU diff: 1-based, with the count
+// omitted when it is 1, and the start preceding the hunk when empty.
+func _Range(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%v,0", start)
+	case 1:
+		return fmt.Sprintf("%v", start + 1)
+	}
+	return fmt.Sprintf("%v,%v", start + 1, count)
+}
+
+// Groups an edit script into hunks of changes, each surrounded by up
+// to context unchanged lines.  Hunks whose context would overlap are
+// merged.
+func _Hunks(edits []Edit, context int) []*_Hunk {
+	var hunks []*_Hunk
+	lo, hi := -1, -1 // range of edits in the current hunk
+	for index, edit := range edits {
+		if edit.Op == Equal {
+			continue
+		}
+		start, end := index - context, index + context + 1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(edits) {
+			end = len(edits)
+		}
+		if lo >= 0 && start <= hi {
+			hi = end
+			continue
+		}
+		if lo >= 0 {
+			hunks = append(hunks, _NewHunk(edits, lo, hi))
+		}
+		lo, hi = start, end
+	}
+	if lo >= 0 {
+		hunks = append(hunks, _NewHunk(edits, lo, hi))
+	}
+	return hunks
+}
+
+// Creates a hunk from edits[lo:hi].
+func _NewHunk(edits []Edit, lo, hi int) *_Hunk {
+	hunk := &_Hunk{}
+	for _, edit := range edits[:lo] {
+		if edit.Op != Insert {
+			hunk.fromStart++
+		}
+		if edit.Op != Delete {
+			hunk.toStart++
+		}
+	}
+	for _, edit := range edits[lo:hi] {
+		hunk.edits = append(hunk.edits, edit)
+		if edit.Op != Insert {
+			hunk.fromCount++
+		}
+		if edit.Op != Delete {
+			hunk.toCount++
+		}
+	}
+	return hunk
+}
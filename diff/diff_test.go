@@ -0,0 +1,162 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_SplitLines(t *testing.T) {
+	if lines := SplitLines(""); len(lines) != 0 {
+		t.Errorf("Expected no lines, was %#v", lines)
+	}
+	if lines := SplitLines("a\nb\n"); len(lines) != 2 {
+		t.Errorf("Expected 2 lines, was %#v", lines)
+	}
+	if lines := SplitLines("a\nb"); len(lines) != 2 {
+		t.Errorf("Expected 2 lines, was %#v", lines)
+	}
+}
+
+func Test_Lines(t *testing.T) {
+	edits := Lines([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+	expected := []Edit{
+		{Equal, "a"}, {Delete, "b"}, {Insert, "x"}, {Equal, "c"}, {Insert, "d"},
+	}
+	if len(edits) != len(expected) {
+		t.Fatalf("Expected %v, was %v", expected, edits)
+	}
+	for i := range edits {
+		if edits[i] != expected[i] {
+			t.Errorf("Edit #%v: expected %v, was %v", i, expected[i], edits[i])
+		}
+	}
+	if Unchanged(edits) {
+		t.Errorf("Expected changes in %v", edits)
+	}
+	if !Unchanged(Lines([]string{"a"}, []string{"a"})) {
+		t.Errorf("Expected no changes")
+	}
+}
+
+func Test_Lines_isMinimal(t *testing.T) {
+	from := []string{"a", "b", "c", "a", "b", "b", "a"}
+	to := []string{"c", "b", "a", "b", "a", "c"}
+	edits := Lines(from, to)
+	changes, fromIndex, toIndex := 0, 0, 0
+	for _, edit := range edits {
+		switch edit.Op {
+		case Equal:
+			if from[fromIndex] != edit.Line || to[toIndex] != edit.Line {
+				t.Fatalf("Invalid edit script %v", edits)
+			}
+			fromIndex++
+			toIndex++
+		case Delete:
+			if from[fromIndex] != edit.Line {
+				t.Fatalf("Invalid edit script %v", edits)
+			}
+			fromIndex++
+			changes++
+		case Insert:
+			if to[toIndex] != edit.Line {
+				t.Fatalf("Invalid edit script %v", edits)
+			}
+			toIndex++
+			changes++
+		}
+	}
+	if fromIndex != len(from) || toIndex != len(to) {
+		t.Errorf("Expected script to cover both inputs, was %v", edits)
+	}
+	if changes != 5 {
+		t.Errorf("Expected 5 changes (as in Myers' paper), was %v in %v", changes, edits)
+	}
+}
+
+func Test_Lines_longInputs(t *testing.T) {
+	from := make([]string, 20000)
+	for i := range from {
+		from[i] = fmt.Sprint(i)
+	}
+	to := append(append([]string{}, from[:10000]...), from[10001:]...)
+	edits := Lines(from, to)
+	if len(edits) != len(from) || edits[10000] != (Edit{Delete, "10000"}) {
+		t.Errorf("Expected one deletion, was %v edits", len(edits))
+	}
+}
+
+func Test_Unified(t *testing.T) {
+	from := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	to := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nK\nl\n"
+	expected := "--- from\n+++ to\n" +
+		"@@ -1,4 +1,4 @@\n a\n-b\n+B\n c\n d\n" +
+		"@@ -9,3 +9,4 @@\n i\n j\n-k\n+K\n+l\n"
+	if actual := Unified("from", "to", from, to, 2); actual != expected {
+		t.Errorf("Expected:\n%v\nWas:\n%v", expected, actual)
+	}
+	if actual := Unified("from", "to", from, from, 2); actual != "" {
+		t.Errorf("Expected no diff, was:\n%v", actual)
+	}
+	expected = "--- from\n+++ to\n@@ -0,0 +1 @@\n+new\n"
+	if actual := Unified("from", "to", "", "new\n", 3); actual != expected {
+		t.Errorf("Expected:\n%v\nWas:\n%v", expected, actual)
+	}
+}
+
+func Test_Escape(t *testing.T) {
+	cases := map[string]string{
+		"plain": "plain",
+		"tab\there": `tab\there`,
+		"crlf\r": `crlf\r`,
+		"zero\u200bwidth": `zero\u200bwidth`,
+		"no\u00a0break": `no\u00a0break`,
+		"trailing  ": `trailing\x20\x20`,
+	}
+	for input, expected := range cases {
+		if actual := Escape(input); actual != expected {
+			t.Errorf("Escape(%#v): expected %v, was %v", input, expected, actual)
+		}
+	}
+}
+
+func Test_FirstDifference(t *testing.T) {
+	if offset := FirstDifference("abc", "abc"); offset != -1 {
+		t.Errorf("Expected -1, was %v", offset)
+	}
+	if offset := FirstDifference("abc", "abd"); offset != 2 {
+		t.Errorf("Expected 2, was %v", offset)
+	}
+	if offset := FirstDifference("ab", "abc"); offset != 2 {
+		t.Errorf("Expected 2, was %v", offset)
+	}
+	if offset := FirstDifference("h\u00e9llo", "h\u00e9lp"); offset != 4 {
+		t.Errorf("Expected 4, was %v", offset)
+	}
+	if offset := FirstDifference("\xff", "\xfe"); offset != 0 {
+		t.Errorf("Expected different invalid bytes to differ at 0, was %v", offset)
+	}
+	if offset := FirstDifference("ok\xff", "ok\u00e9"); offset != 2 {
+		t.Errorf("Expected 2, was %v", offset)
+	}
+	if offset := FirstDifference("\xff\xfe", "\xff\xfe"); offset != -1 {
+		t.Errorf("Expected identical invalid bytes to be equal, was %v", offset)
+	}
+	if divergence := Divergence("\xff", "\xfe"); divergence == "" {
+		t.Errorf("Expected a divergence between different invalid bytes")
+	}
+}
+
+func Test_Divergence(t *testing.T) {
+	expected := "first difference at line 2, column 3:\n" +
+		"  expected: a\\tc\n  actual:   a\\td\n               ^"
+	if actual := Divergence("x\na\tc", "x\na\td"); actual != expected {
+		t.Errorf("Expected:\n%v\nWas:\n%v", expected, actual)
+	}
+	if actual := Divergence("same", "same"); actual != "" {
+		t.Errorf("Expected no divergence, was %v", actual)
+	}
+}
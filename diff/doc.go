@@ -0,0 +1,12 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+	Computes line-by-line differences between strings and formats them
+	as unified diffs, for use in the descriptions of matcher Results.
+	
+	This package depends only on the standard library, so that it may
+	be used by the base package.
+*/
+package diff
@@ -0,0 +1,132 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+	"utf8"
+)
+
+// Number of unchanged lines shown around each change by Comparison.
+const DefaultContext = 3
+
+// Returns s with invisible characters made visible:  tabs, carriage
+// returns and other control or formatting runes are written as Go
+// escapes (such as \t, \r or \u200b), unusual spaces are written as
+// \u escapes, and trailing spaces are written as \x20.
+func Escape(s string) string {
+	trimmed := strings.TrimRight(s, " ")
+	var buffer bytes.Buffer
+	for _, rune := range trimmed {
+		switch {
+		case rune == '\t':
+			buffer.WriteString(`\t`)
+		case rune == '\r':
+			buffer.WriteString(`\r`)
+		case rune == '\n':
+			buffer.WriteString(`\n`)
+		case rune == utf8.RuneError:
+			buffer.WriteString(`\ufffd`)
+		case rune == ' ':
+			buffer.WriteRune(rune)
+		case unicode.Is(unicode.Cc, rune), unicode.Is(unicode.Cf, rune),
+				unicode.Is(unicode.Zs, rune), unicode.Is(unicode.Zl, rune),
+				unicode.Is(unicode.Zp, rune):
+			fmt.Fprintf(&buffer, `\u%04x`, rune)
+		default:
+			buffer.WriteRune(rune)
+		}
+	}
+	for i := len(trimmed); i < len(s); i++ {
+		buffer.WriteString(`\x20`)
+	}
+	return buffer.String()
+}
+
+// Returns the byte offset of the first rune at which a and b differ,
+// or -1 if they are equal.  Runes are compared by their bytes, so that
+// different invalid bytes (which both decode as utf8.RuneError) differ.
+func FirstDifference(a, b string) int {
+	offset := 0
+	for offset < len(a) && offset < len(b) {
+		_, size := utf8.DecodeRuneInString(a[offset:])
+		_, otherSize := utf8.DecodeRuneInString(b[offset:])
+		if size != otherSize || a[offset:offset+size] != b[offset:offset+size] {
+			return offset
+		}
+		offset += size
+	}
+	if len(a) != len(b) {
+		return offset
+	}
+	return -1
+}
+
+// Describes the first point at which expected and actual diverge,
+// showing both (escaped) lines with a caret under the first differing
+// character.  Returns the empty string if they are equal.
+func Divergence(expected, actual string) string {
+	offset := FirstDifference(expected, actual)
+	if offset < 0 {
+		return ""
+	}
+	lineStart := strings.LastIndex(expected[:offset], "\n") + 1
+	lineNumber := strings.Count(expected[:offset], "\n") + 1
+	expectedLine := _LineAt(expected, lineStart)
+	actualLine := _LineAt(actual, lineStart)
+	prefix := Escape(expected[lineStart:offset])
+	column := utf8.RuneCountInString(expected[lineStart:offset]) + 1
+	marker := strings.Repeat(" ", utf8.RuneCountInString(prefix)) + "^"
+	return fmt.Sprintf(
+		"first difference at line %v, column %v:\n" +
+		"  expected: %v\n  actual:   %v\n            %v",
+		lineNumber, column, Escape(expectedLine), Escape(actualLine), marker)
+}
+
+// Returns the line of s beginning at the given offset (or the empty
+// string, if s is shorter than offset).
+func _LineAt(s string, start int) string {
+	if start >= len(s) {
+		return ""
+	}
+	line := s[start:]
+	if end := strings.Index(line, "\n"); end >= 0 {
+		line = line[:end]
+	}
+	return line
+}
+
+// A lazily-formatted, self-describing comparison of two texts.  Its
+// String() describes the first divergence, followed by a unified diff
+// (with escaped lines) when the texts span more than one line.
+type Comparison struct {
+	ExpectedName, ActualName string
+	Expected, Actual string
+}
+
+// Implements fmt.Stringer.
+func (self *Comparison) String() string {
+	divergence := Divergence(self.Expected, self.Actual)
+	if divergence == "" {
+		return "texts are identical"
+	}
+	if !strings.Contains(self.Expected, "\n") && !strings.Contains(self.Actual, "\n") {
+		return divergence
+	}
+	unified := Unified(self.ExpectedName, self.ActualName,
+		self.Expected, self.Actual, DefaultContext)
+	if unified == "" {
+		return divergence + "\n(texts differ only in their final newline)"
+	}
+	return divergence + "\n" + unified
+}
+
+// Implements fmt.Formatter.
+func (self *Comparison) Format(s fmt.State, ch int) {
+	fmt.Fprint(s, self.String())
+}
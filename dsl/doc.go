@@ -0,0 +1,28 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+	Builds matchers from text, in a small expression language of
+	named matcher factories, so that matchers can be read from
+	configuration files, command lines or test data:
+	
+		matcher, err := dsl.Parse(`allOf(greaterThan(3), lessThan(10))`)
+		matcher, err := dsl.Parse(`hasField("Name", containsString("x"))`)
+	
+	Parse knows the matchers of the core, strings, collections and
+	reflect packages (see NewStandardRegistry).  A Registry can be
+	extended with other factories:
+	
+		registry := dsl.NewStandardRegistry()
+		registry.Register("between", func(low, high int) *base.Matcher {
+			return AllOf(GreaterThanOrEqualTo(low), LessThanOrEqualTo(high))
+		})
+		matcher, err := registry.Parse(`anyOf(between(1, 5), equalTo(10))`)
+	
+	Invalid expressions are reported by a *ParseError, which points to
+	the problem.  Each matcher that Parse builds is described by the
+	canonical text of its expression, so its String() can be parsed
+	again to build the same matcher.
+*/
+package dsl
@@ -0,0 +1,288 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dsl
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/base"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Parses an expression, building matchers with the factories of the
+// standard registry (see NewStandardRegistry).  An expression is a
+// call of a named factory, whose arguments are expressions or literals:
+//    allOf(greaterThan(3), lessThan(10))
+//    hasField("Name", containsString("x"))
+//    anyOf(nil(), strings.toLen(equalTo(0)))
+// Literals are strings (double-quoted with Go escapes, or back-quoted),
+// integers (which must fit in an int), floating-point numbers, true,
+// false and nil.  The parentheses of a call without arguments may be
+// omitted (except for the factories named true, false and nil).
+//
+// The description of each matcher built by Parse is the canonical text
+// of its expression, which Parse accepts, so that (for example) rules
+// can be normalized and written back to a configuration file:
+//    m, _ := dsl.Parse(`allOf( greaterThan(3),lessThan(1e1) )`)
+//    m.String() // "allOf(greaterThan(3), lessThan(10.0))"
+//
+// If the expression is not valid, the returned error is a *ParseError.
+func Parse(text string) (*base.Matcher, os.Error) {
+	return _Standard.Parse(text)
+}
+
+// Describes an invalid expression.
+type ParseError struct {
+	Text string   // the expression
+	Offset int    // the byte offset of the problem in the expression
+	Message string
+}
+
+// Implements os.Error, showing the position of the problem:
+//    unknown matcher grater at offset 6:
+//        allOf(grater(3))
+//              ^
+func (self *ParseError) String() string {
+	return fmt.Sprintf("%v at offset %v:\n\t%v\n\t%v^",
+		self.Message, self.Offset, self.Text, strings.Repeat(" ", self.Offset))
+}
+
+func _ParseErrorAt(text string, offset int, message string) *ParseError {
+	return &ParseError{Text: text, Offset: offset, Message: message}
+}
+
+type _Kind int
+
+const (
+	_Call _Kind = iota
+	_String
+	_Int
+	_Float
+	_Bool
+	_Nil
+)
+
+// A parsed expression:  a call or a literal value.
+type _Node struct {
+	kind _Kind
+	offset int
+	name string        // of a call
+	args []*_Node      // of a call
+	value interface{}  // of a literal
+}
+
+// Returns the canonical text of the expression.
+func (self *_Node) String() string {
+	switch self.kind {
+	case _Call:
+		args := make([]string, len(self.args))
+		for i, arg := range self.args {
+			args[i] = arg.String()
+		}
+		return self.name + "(" + strings.Join(args, ", ") + ")"
+	case _String:
+		return strconv.Quote(self.value.(string))
+	case _Float:
+		text := strconv.Ftoa64(self.value.(float64), 'g', -1)
+		if !strings.ContainsAny(text, ".eIN") {
+			text += ".0"
+		}
+		return text
+	case _Nil:
+		return "nil"
+	}
+	return fmt.Sprint(self.value)
+}
+
+// Describes the expression for an error message.
+func (self *_Node) describe() string {
+	switch self.kind {
+	case _Call:
+		return "matcher " + self.String()
+	case _String:
+		return "string " + self.String()
+	case _Int, _Float:
+		return "number " + self.String()
+	}
+	return self.String()
+}
+
+type _Parser struct {
+	text string
+	pos int
+}
+
+// Parses the text as a single expression.
+func _ParseExpression(text string) (*_Node, os.Error) {
+	parser := &_Parser{text: text}
+	node, err := parser.expression()
+	if err != nil {
+		return nil, err
+	}
+	parser.skipSpace()
+	if parser.pos < len(text) {
+		return nil, parser.errorf("unexpected %q after expression", text[parser.pos:])
+	}
+	return node, nil
+}
+
+func (self *_Parser) errorf(format string, args...interface{}) os.Error {
+	return _ParseErrorAt(self.text, self.pos, fmt.Sprintf(format, args...))
+}
+
+func (self *_Parser) skipSpace() {
+	for self.pos < len(self.text) && strings.IndexRune(" \t\r\n", int(self.text[self.pos])) >= 0 {
+		self.pos++
+	}
+}
+
+// Returns the next byte (after any space) without consuming it, or 0
+// at the end of the text.
+func (self *_Parser) peek() byte {
+	self.skipSpace()
+	if self.pos < len(self.text) {
+		return self.text[self.pos]
+	}
+	return 0
+}
+
+func (self *_Parser) expression() (*_Node, os.Error) {
+	c := self.peek()
+	start := self.pos
+	switch {
+	case c == 0:
+		return nil, self.errorf("unexpected end of expression")
+	case c == '"' || c == '`':
+		return self.stringLiteral()
+	case c == '-' || c == '+' || c == '.' || _IsDigit(c):
+		return self.number()
+	case _IsNameStart(c):
+		for self.pos < len(self.text) && _IsNameByte(self.text[self.pos]) {
+			self.pos++
+		}
+		name := self.text[start:self.pos]
+		if !_IsName(name) {
+			self.pos = start
+			return nil, self.errorf("invalid name %q", name)
+		}
+		if self.peek() != '(' {
+			switch name {
+			case "true", "false":
+				return &_Node{kind: _Bool, offset: start, value: name == "true"}, nil
+			case "nil":
+				return &_Node{kind: _Nil, offset: start}, nil
+			}
+			return &_Node{kind: _Call, offset: start, name: name}, nil
+		}
+		self.pos++ // '('
+		node := &_Node{kind: _Call, offset: start, name: name}
+		if self.peek() == ')' {
+			self.pos++
+			return node, nil
+		}
+		for {
+			arg, err := self.expression()
+			if err != nil {
+				return nil, err
+			}
+			node.args = append(node.args, arg)
+			switch self.peek() {
+			case ',':
+				self.pos++
+			case ')':
+				self.pos++
+				return node, nil
+			case 0:
+				return nil, self.errorf("missing \")\" to close %v(", name)
+			default:
+				return nil, self.errorf("expected \",\" or \")\", found %q", self.text[self.pos:self.pos+1])
+			}
+		}
+	}
+	return nil, self.errorf("unexpected %q", self.text[self.pos:self.pos+1])
+}
+
+func (self *_Parser) stringLiteral() (*_Node, os.Error) {
+	start := self.pos
+	quote := self.text[start]
+	end := start + 1
+	for end < len(self.text) && self.text[end] != quote {
+		if quote == '"' && self.text[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	if end >= len(self.text) {
+		return nil, self.errorf("unterminated string")
+	}
+	end++
+	value, err := strconv.Unquote(self.text[start:end])
+	if err != nil {
+		return nil, self.errorf("invalid string %v", self.text[start:end])
+	}
+	self.pos = end
+	return &_Node{kind: _String, offset: start, value: value}, nil
+}
+
+func (self *_Parser) number() (*_Node, os.Error) {
+	start := self.pos
+	end := start
+	if self.text[end] == '-' || self.text[end] == '+' {
+		end++
+	}
+	for end < len(self.text) && strings.IndexRune("0123456789.eE", int(self.text[end])) >= 0 {
+		if (self.text[end] == 'e' || self.text[end] == 'E') && end + 1 < len(self.text) &&
+				(self.text[end+1] == '-' || self.text[end+1] == '+') {
+			end++
+		}
+		end++
+	}
+	literal := self.text[start:end]
+	if !strings.ContainsAny(literal, ".eE") {
+		value, err := strconv.Atoi(literal)
+		if err == nil {
+			self.pos = end
+			return &_Node{kind: _Int, offset: start, value: value}, nil
+		}
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Error == os.ERANGE {
+			return nil, self.errorf("integer out of range %v", literal)
+		}
+	}
+	value, err := strconv.Atof64(literal)
+	if err != nil {
+		return nil, self.errorf("invalid number %v", literal)
+	}
+	self.pos = end
+	return &_Node{kind: _Float, offset: start, value: value}, nil
+}
+
+func _IsDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func _IsNameStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func _IsNameByte(c byte) bool {
+	return _IsNameStart(c) || _IsDigit(c) || c == '.'
+}
+
+// Returns true if the text is a valid factory name:  identifiers
+// separated by dots.
+func _IsName(name string) bool {
+	for _, part := range strings.Split(name, ".", -1) {
+		if part == "" || !_IsNameStart(part[0]) {
+			return false
+		}
+		for i := 1; i < len(part); i++ {
+			if !_IsNameByte(part[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
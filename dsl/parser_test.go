@@ -0,0 +1,126 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dsl
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	. "github.com/rdrdr/hamcrest/core"
+	"github.com/rdrdr/hamcrest/strings"
+	"testing"
+)
+
+var Matched = base.Matched()
+var DidNotMatch = base.DidNotMatch()
+var Errored = base.Errored()
+
+// Checks that the text parses to an expression with the given
+// canonical text.
+func checkCanonical(t *testing.T, text string, canonical string) {
+	node, err := _ParseExpression(text)
+	if err != nil {
+		t.Errorf("Parsing %q: unexpected error %v", text, err)
+		return
+	}
+	if node.String() != canonical {
+		t.Errorf("Parsing %q: expected %q, was %q", text, canonical, node.String())
+	}
+}
+
+// Checks that parsing the text fails with the given message at the
+// given offset.
+func checkParseError(t *testing.T, text string, offset int, message string) {
+	_, err := _ParseExpression(text)
+	parseError, ok := err.(*ParseError)
+	if !ok {
+		t.Errorf("Parsing %q: expected a *ParseError, was %#v", text, err)
+		return
+	}
+	if parseError.Offset != offset || parseError.Message != message {
+		t.Errorf("Parsing %q: expected %q at offset %v, was %q at offset %v",
+			text, message, offset, parseError.Message, parseError.Offset)
+	}
+}
+
+func Test_ParseExpression_calls(t *testing.T) {
+	checkCanonical(t, "anything()", "anything()")
+	checkCanonical(t, "anything", "anything()")
+	checkCanonical(t, "  not( anything )  ", "not(anything())")
+	checkCanonical(t, "allOf( greaterThan(3),lessThan(1e1) )", "allOf(greaterThan(3), lessThan(10.0))")
+	checkCanonical(t, "strings.toLen(equalTo(0))", "strings.toLen(equalTo(0))")
+	checkCanonical(t, "true()", "true()")
+	checkCanonical(t, "nil ( )", "nil()")
+}
+
+func Test_ParseExpression_literals(t *testing.T) {
+	checkCanonical(t, "f(42, -7, +3)", "f(42, -7, 3)")
+	checkCanonical(t, "f(2.5, -0.25, 1e3, 1.5e-7)", "f(2.5, -0.25, 1000.0, 1.5e-07)")
+	checkCanonical(t, `f("abc", "a\tb\"c", "")`, `f("abc", "a\tb\"c", "")`)
+	checkCanonical(t, "f(`a\\b`)", `f("a\\b")`)
+	checkCanonical(t, "f(true, false, nil)", "f(true, false, nil)")
+}
+
+func Test_ParseExpression_literalsAtTopLevel(t *testing.T) {
+	checkCanonical(t, "42", "42")
+	checkCanonical(t, `"text"`, `"text"`)
+	checkCanonical(t, "true", "true")
+	checkCanonical(t, "nil", "nil")
+}
+
+func Test_ParseExpression_errors(t *testing.T) {
+	checkParseError(t, "", 0, "unexpected end of expression")
+	checkParseError(t, "   ", 3, "unexpected end of expression")
+	checkParseError(t, "allOf(1,", 8, "unexpected end of expression")
+	checkParseError(t, "allOf(1", 7, `missing ")" to close allOf(`)
+	checkParseError(t, "allOf(1 2)", 8, `expected "," or ")", found "2"`)
+	checkParseError(t, "allOf(,)", 6, `unexpected ","`)
+	checkParseError(t, "foo(#)", 4, `unexpected "#"`)
+	checkParseError(t, "foo() bar", 6, `unexpected "bar" after expression`)
+	checkParseError(t, `f("abc)`, 2, "unterminated string")
+	checkParseError(t, `f("\q")`, 2, `invalid string "\q"`)
+	checkParseError(t, "f(1.2.3)", 2, "invalid number 1.2.3")
+	checkParseError(t, "f(-)", 2, "invalid number -")
+	checkParseError(t, "f(99999999999999999999)", 2,
+		"integer out of range 99999999999999999999")
+	checkParseError(t, "f(-99999999999999999999)", 2,
+		"integer out of range -99999999999999999999")
+	checkParseError(t, "foo..bar()", 0, `invalid name "foo..bar"`)
+	checkParseError(t, "foo.()", 0, `invalid name "foo."`)
+}
+
+func Test_ParseError_String(t *testing.T) {
+	we := asserter.Using(t)
+	_, err := _ParseExpression("allOf(1 2)")
+	we.CheckThat(err, Is(NonNil()))
+	we.CheckThat(err.String(), EqualTo(
+		"expected \",\" or \")\", found \"2\" at offset 8:\n" +
+		"\tallOf(1 2)\n" +
+		"\t        ^"))
+}
+
+func Test_IsName(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(_IsName("anything"), Is(True()))
+	we.CheckThat(_IsName("_x9"), Is(True()))
+	we.CheckThat(_IsName("strings.toLen"), Is(True()))
+	we.CheckThat(_IsName(""), Is(False()))
+	we.CheckThat(_IsName("9x"), Is(False()))
+	we.CheckThat(_IsName("a.9"), Is(False()))
+	we.CheckThat(_IsName(".a"), Is(False()))
+	we.CheckThat(_IsName("a-b"), Is(False()))
+}
+
+func Test_Parse(t *testing.T) {
+	we := asserter.Using(t)
+	matcher, err := Parse("allOf(greaterThan(3), lessThan(10))")
+	we.CheckThat(err, Is(Nil()))
+	we.CheckThat(matcher.Match(5), Matched)
+	we.CheckThat(matcher.Match(3), DidNotMatch)
+	we.CheckThat(matcher.Match(10), DidNotMatch)
+
+	_, err = Parse("allOf(grater(3))")
+	we.CheckThat(err, Is(NonNil()))
+	we.CheckThat(err.String(), strings.HasPrefix("unknown matcher grater at offset 6:"))
+}
@@ -0,0 +1,270 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dsl
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/base"
+	"os"
+	"reflect"
+	"sort"
+)
+
+var _MatcherType = reflect.Typeof((*base.Matcher)(nil))
+var _StringType = reflect.Typeof("")
+var _IntType = reflect.Typeof(int(0))
+var _FloatType = reflect.Typeof(float64(0))
+var _BoolType = reflect.Typeof(false)
+var _AnyType = reflect.Typeof((*interface{})(nil)).(*reflect.PtrType).Elem()
+
+// A set of named matcher factories, used to build matchers from
+// expressions (see Parse).
+type Registry struct {
+	factories map[string]*_Factory
+}
+
+// A registered function that returns a *base.Matcher.
+type _Factory struct {
+	name string
+	function *reflect.FuncValue
+	params []reflect.Type // for a variadic function, the last is the element type
+	variadic bool
+}
+
+// Returns a new Registry with no factories.  (See NewStandardRegistry
+// for one with the matchers of the hamcrest packages.)
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]*_Factory)}
+}
+
+// Registers a factory under the given name, replacing any factory
+// already registered under that name.  The factory must be a function
+// that returns a *base.Matcher, whose parameters (including a final
+// variadic parameter) are each of type string, int, float64, bool,
+// interface{} or *base.Matcher.  For example:
+//    registry.Register("between", func(low, high int) *base.Matcher {
+//        return AllOf(GreaterThanOrEqualTo(low), LessThanOrEqualTo(high))
+//    })
+// Names may contain letters, digits, underscores and dots.
+//
+// Panics if the name or the factory is not valid.
+func (self *Registry) Register(name string, function interface{}) {
+	if !_IsName(name) {
+		panic(fmt.Sprintf("invalid factory name %q", name))
+	}
+	funcValue, ok := reflect.NewValue(function).(*reflect.FuncValue)
+	if !ok {
+		panic(fmt.Sprintf("factory %v must be a function, was %T", name, function))
+	}
+	funcType := funcValue.Type().(*reflect.FuncType)
+	if funcType.NumOut() != 1 || funcType.Out(0) != _MatcherType {
+		panic(fmt.Sprintf("factory %v must return only a *base.Matcher, was %v", name, funcType))
+	}
+	factory := &_Factory{name: name, function: funcValue, variadic: funcType.DotDotDot()}
+	for i := 0; i < funcType.NumIn(); i++ {
+		param := funcType.In(i)
+		if factory.variadic && i == funcType.NumIn() - 1 {
+			param = param.(*reflect.SliceType).Elem()
+		}
+		switch param {
+		case _MatcherType, _StringType, _IntType, _FloatType, _BoolType, _AnyType:
+		default:
+			panic(fmt.Sprintf("factory %v has a parameter of unsupported type %v", name, param))
+		}
+		factory.params = append(factory.params, param)
+	}
+	self.factories[name] = factory
+}
+
+// Returns true if a factory is registered under the given name.
+func (self *Registry) Has(name string) bool {
+	_, found := self.factories[name]
+	return found
+}
+
+// Returns the names of the registered factories, in sorted order.
+func (self *Registry) Names() []string {
+	names := make([]string, 0, len(self.factories))
+	for name := range self.factories {
+		names = append(names, name)
+	}
+	sort.SortStrings(names)
+	return names
+}
+
+// Parses an expression (see Parse), building matchers with the
+// factories of this registry.
+func (self *Registry) Parse(text string) (*base.Matcher, os.Error) {
+	node, err := _ParseExpression(text)
+	if err != nil {
+		return nil, err
+	}
+	if node.kind != _Call {
+		return nil, _ParseErrorAt(text, node.offset,
+			fmt.Sprintf("expected a matcher, found %v", node.describe()))
+	}
+	return self.build(text, node)
+}
+
+// Builds the matcher for a call node, whose description is the
+// canonical text of the call, and whose structured description has the
+// name of the factory, the literal arguments as parameters, and the
+// matchers built for the other arguments as children.  The built
+// matcher keeps the negated description (if any) of the factory's.
+func (self *Registry) build(text string, node *_Node) (*base.Matcher, os.Error) {
+	factory, found := self.factories[node.name]
+	if !found {
+		return nil, _ParseErrorAt(text, node.offset,
+			fmt.Sprintf("unknown matcher %v", node.name))
+	}
+	numFixed := len(factory.params)
+	if factory.variadic {
+		numFixed--
+	}
+	if len(node.args) < numFixed || (!factory.variadic && len(node.args) > numFixed) {
+		expected := fmt.Sprint(numFixed)
+		if factory.variadic {
+			expected = fmt.Sprintf("at least %v", numFixed)
+		}
+		return nil, _ParseErrorAt(text, node.offset, fmt.Sprintf(
+			"wrong number of arguments to %v: expected %v, found %v",
+			node.name, expected, len(node.args)))
+	}
+	args := make([]reflect.Value, 0, len(node.args))
+	var variadicArgs []reflect.Value
+	var params []interface{}
+	var children []*base.Matcher
+	for i, arg := range node.args {
+		param := factory.params[len(factory.params) - 1]
+		if i < numFixed {
+			param = factory.params[i]
+		}
+		value, err := self.convert(text, arg, param)
+		if err != nil {
+			return nil, err
+		}
+		if arg.kind == _Call {
+			children = append(children, value.Interface().(*base.Matcher))
+		} else {
+			params = append(params, value.Interface())
+		}
+		if i < numFixed {
+			args = append(args, value)
+		} else {
+			variadicArgs = append(variadicArgs, value)
+		}
+	}
+	if factory.variadic {
+		sliceType := factory.function.Type().(*reflect.FuncType).In(numFixed).(*reflect.SliceType)
+		slice := reflect.MakeSlice(sliceType, len(variadicArgs), len(variadicArgs))
+		for i, value := range variadicArgs {
+			slice.Elem(i).SetValue(value)
+		}
+		args = append(args, slice)
+	}
+	matcher, err := _Invoke(factory, args)
+	if err != nil {
+		return nil, _ParseErrorAt(text, node.offset, err.String())
+	}
+	described := _Described(matcher, node.String()).
+		WithStructure(node.name, params, children...)
+	if matcher.HasNegation() {
+		described = described.WithNegationf("%v", matcher.Negation())
+	}
+	return described, nil
+}
+
+// Converts an argument node to a value for a parameter of the given
+// type, building matchers for call nodes.
+func (self *Registry) convert(text string, arg *_Node, param reflect.Type) (reflect.Value, os.Error) {
+	mismatch := func() (reflect.Value, os.Error) {
+		return nil, _ParseErrorAt(text, arg.offset, fmt.Sprintf(
+			"expected %v, found %v", _DescribeType(param), arg.describe()))
+	}
+	if arg.kind == _Call {
+		if param != _MatcherType && param != _AnyType {
+			return mismatch()
+		}
+		matcher, err := self.build(text, arg)
+		if err != nil {
+			return nil, err
+		}
+		return _Set(param, reflect.NewValue(matcher)), nil
+	}
+	switch param {
+	case _StringType:
+		if arg.kind == _String {
+			return reflect.NewValue(arg.value), nil
+		}
+	case _IntType:
+		if arg.kind == _Int {
+			return reflect.NewValue(arg.value), nil
+		}
+	case _FloatType:
+		switch arg.kind {
+		case _Float:
+			return reflect.NewValue(arg.value), nil
+		case _Int:
+			return reflect.NewValue(float64(arg.value.(int))), nil
+		}
+	case _BoolType:
+		if arg.kind == _Bool {
+			return reflect.NewValue(arg.value), nil
+		}
+	case _AnyType:
+		if arg.kind == _Nil {
+			return reflect.MakeZero(_AnyType), nil
+		}
+		return _Set(_AnyType, reflect.NewValue(arg.value)), nil
+	}
+	return mismatch()
+}
+
+// Returns a new value of the given type, set to the given value.
+func _Set(t reflect.Type, value reflect.Value) reflect.Value {
+	result := reflect.MakeZero(t)
+	result.SetValue(value)
+	return result
+}
+
+// Calls the factory (passing any variadic arguments as a final slice,
+// as reflect requires), converting a panic into an error.
+func _Invoke(factory *_Factory, args []reflect.Value) (matcher *base.Matcher, err os.Error) {
+	defer func() {
+		if x := recover(); x != nil {
+			err = os.NewError(fmt.Sprintf("%v panicked: %v", factory.name, x))
+		}
+	}()
+	matcher = factory.function.Call(args)[0].Interface().(*base.Matcher)
+	return matcher, nil
+}
+
+func _DescribeType(t reflect.Type) string {
+	switch t {
+	case _MatcherType:
+		return "a matcher"
+	case _StringType:
+		return "a string"
+	case _IntType:
+		return "an integer"
+	case _FloatType:
+		return "a number"
+	case _BoolType:
+		return "true or false"
+	}
+	return "a value"
+}
+
+// Returns a matcher that behaves exactly like the given matcher, but
+// is described by the given text.
+func _Described(matcher *base.Matcher, text string) *base.Matcher {
+	match := func(actual interface{}) *base.Result {
+		result := matcher.Match(actual)
+		return base.NewResult(result.Matched(), result).
+			WithError(result.Err()).
+			WithCauses(result.Causes()...)
+	}
+	return base.NewMatcherf(match, "%v", text)
+}
@@ -0,0 +1,171 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dsl
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	. "github.com/rdrdr/hamcrest/core"
+	"github.com/rdrdr/hamcrest/slices"
+	"testing"
+)
+
+func newTestRegistry() *Registry {
+	registry := NewRegistry()
+	registry.Register("anything", Anything)
+	registry.Register("not", Not)
+	registry.Register("equalTo", EqualTo)
+	registry.Register("allOf", AllOf)
+	registry.Register("between", func(low, high int) *base.Matcher {
+		return AllOf(GreaterThanOrEqualTo(low), LessThanOrEqualTo(high))
+	})
+	registry.Register("near", func(expected float64) *base.Matcher {
+		return AllOf(GreaterThan(expected - 0.5), LessThan(expected + 0.5))
+	})
+	registry.Register("prefixed", func(prefix string, required bool) *base.Matcher {
+		return Anything()
+	})
+	registry.Register("explode", func() *base.Matcher {
+		panic("boom")
+	})
+	return registry
+}
+
+// Checks that parsing the text with the registry fails with the given
+// message at the given offset.
+func checkBuildError(t *testing.T, registry *Registry, text string, offset int, message string) {
+	matcher, err := registry.Parse(text)
+	parseError, ok := err.(*ParseError)
+	if !ok {
+		t.Errorf("Parsing %q: expected a *ParseError, was %#v (and %v)", text, err, matcher)
+		return
+	}
+	if parseError.Offset != offset || parseError.Message != message {
+		t.Errorf("Parsing %q: expected %q at offset %v, was %q at offset %v",
+			text, message, offset, parseError.Message, parseError.Offset)
+	}
+}
+
+func Test_Registry_Parse(t *testing.T) {
+	we := asserter.Using(t)
+	registry := newTestRegistry()
+
+	between, err := registry.Parse("between(1, 5)")
+	we.CheckThat(err, Is(Nil()))
+	we.CheckThat(between.Match(1), Matched)
+	we.CheckThat(between.Match(5), Matched)
+	we.CheckThat(between.Match(6), DidNotMatch)
+
+	near, err := registry.Parse("not(near(2))")
+	we.CheckThat(err, Is(Nil()))
+	we.CheckThat(near.Match(2.25), DidNotMatch)
+	we.CheckThat(near.Match(3.0), Matched)
+
+	equal, err := registry.Parse(`allOf(not(equalTo("y")), equalTo("x"))`)
+	we.CheckThat(err, Is(Nil()))
+	we.CheckThat(equal.Match("x"), Matched)
+	we.CheckThat(equal.Match("y"), DidNotMatch)
+}
+
+func Test_Registry_describesMatchersByCanonicalText(t *testing.T) {
+	we := asserter.Using(t)
+	matcher, err := newTestRegistry().Parse(" allOf(between(1,5),not( anything ) ) ")
+	we.CheckThat(err, Is(Nil()))
+	we.CheckThat(matcher.String(), EqualTo("allOf(between(1, 5), not(anything()))"))
+	we.CheckThat(matcher.Match(3), DidNotMatch)
+}
+
+func Test_Registry_errors(t *testing.T) {
+	registry := newTestRegistry()
+	checkBuildError(t, registry, "3", 0, "expected a matcher, found number 3")
+	checkBuildError(t, registry, `"text"`, 0, `expected a matcher, found string "text"`)
+	checkBuildError(t, registry, "allOf(grater(3))", 6, "unknown matcher grater")
+	checkBuildError(t, registry, "between(1)", 0,
+		"wrong number of arguments to between: expected 2, found 1")
+	checkBuildError(t, registry, "anything(1)", 0,
+		"wrong number of arguments to anything: expected 0, found 1")
+	checkBuildError(t, registry, "not()", 0,
+		"wrong number of arguments to not: expected 1, found 0")
+	checkBuildError(t, registry, "between(1, 2.5)", 11, "expected an integer, found number 2.5")
+	checkBuildError(t, registry, `between("1", 2)`, 8, `expected an integer, found string "1"`)
+	checkBuildError(t, registry, "near(true)", 5, "expected a number, found true")
+	checkBuildError(t, registry, "prefixed(1, true)", 9, "expected a string, found number 1")
+	checkBuildError(t, registry, `prefixed("x", nil)`, 14, "expected true or false, found nil")
+	checkBuildError(t, registry, "not(3)", 4, "expected a matcher, found number 3")
+	checkBuildError(t, registry, "between(anything, 2)", 8,
+		"expected an integer, found matcher anything()")
+	checkBuildError(t, registry, "allOf(anything, explode)", 16, "explode panicked: boom")
+}
+
+func Test_Registry_variadicFactoryAcceptsNoArguments(t *testing.T) {
+	we := asserter.Using(t)
+	matcher, err := newTestRegistry().Parse("allOf()")
+	we.CheckThat(err, Is(Nil()))
+	we.CheckThat(matcher.Match(0), Matched)
+	we.CheckThat(matcher.String(), EqualTo("allOf()"))
+}
+
+func Test_Registry_Has_Names(t *testing.T) {
+	we := asserter.Using(t)
+	registry := newTestRegistry()
+	we.CheckThat(registry.Has("between"), Is(True()))
+	we.CheckThat(registry.Has("grater"), Is(False()))
+	we.CheckThat(registry.Names(), DeepEqualTo([]string{
+		"allOf", "anything", "between", "equalTo", "explode", "near", "not", "prefixed"}))
+	we.CheckThat(NewRegistry().Names(), slices.Empty())
+}
+
+func Test_Registry_Register_replaces(t *testing.T) {
+	we := asserter.Using(t)
+	registry := newTestRegistry()
+	registry.Register("anything", func() *base.Matcher { return Not(Anything()) })
+	matcher, _ := registry.Parse("anything")
+	we.CheckThat(matcher.Match(0), DidNotMatch)
+}
+
+func Test_Registry_Register_invalidFactories(t *testing.T) {
+	we := asserter.Using(t)
+	registering := func(name string, factory interface{}) *base.Matcher {
+		return PanicWhenApplying(func(registry *Registry) {
+			registry.Register(name, factory)
+		}, "Register")
+	}
+	registry := NewRegistry()
+	we.CheckThat(registry, registering("", Anything))
+	we.CheckThat(registry, registering("9lives", Anything))
+	we.CheckThat(registry, registering("a-b", Anything))
+	we.CheckThat(registry, registering("anything", Anything()))
+	we.CheckThat(registry, registering("count", func() int { return 0 }))
+	we.CheckThat(registry, registering("pair", func() (*base.Matcher, *base.Matcher) {
+		return nil, nil
+	}))
+	we.CheckThat(registry, registering("small", func(n int8) *base.Matcher { return nil }))
+	we.CheckThat(registry, registering("all", func(ns...uint) *base.Matcher { return nil }))
+	we.CheckThat(registry, Not(registering("ok.name_2", Anything)))
+	we.CheckThat(registry.Names(), DeepEqualTo([]string{"ok.name_2"}))
+}
+
+func Test_Registry_structure(t *testing.T) {
+	we := asserter.Using(t)
+	matcher, err := newTestRegistry().Parse(`allOf(between(1, 5), not(equalTo("x")))`)
+	we.CheckThat(err, Is(Nil()))
+	we.CheckThat(matcher.Name(), EqualTo("allOf"))
+	children := matcher.Children()
+	we.CheckThat(len(children), EqualTo(2))
+	we.CheckThat(children[0].Name(), EqualTo("between"))
+	we.CheckThat(children[0].Params(), DeepEqualTo([]interface{}{1, 5}))
+	we.CheckThat(children[1].Children()[0].Params(), DeepEqualTo([]interface{}{"x"}))
+	var names []string
+	matcher.Inspect(func(m *base.Matcher) bool {
+		names = append(names, m.String())
+		return true
+	})
+	we.CheckThat(names, DeepEqualTo([]string{
+		`allOf(between(1, 5), not(equalTo("x")))`,
+		"between(1, 5)",
+		`not(equalTo("x"))`,
+		`equalTo("x")`,
+	}))
+}
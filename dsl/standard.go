@@ -0,0 +1,180 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dsl
+
+import (
+	"github.com/rdrdr/hamcrest/base"
+	"github.com/rdrdr/hamcrest/collections"
+	"github.com/rdrdr/hamcrest/core"
+	"github.com/rdrdr/hamcrest/reflect"
+	"github.com/rdrdr/hamcrest/strings"
+)
+
+// The registry used by Parse.
+var _Standard = NewStandardRegistry()
+
+// Returns a new Registry with the matchers of the core, strings,
+// collections and reflect packages, named by their Go names with a
+// lowercase first letter.  The core matchers are registered by their
+// names alone:
+//    anything, true, false, nil, nonNil, not, is, equalTo, notEqualTo,
+//    deepEqualTo, greaterThan, greaterThanOrEqualTo, lessThan,
+//    lessThanOrEqualTo, allOf, anyOf
+// The matchers of the other packages are registered by their qualified
+// names (such as strings.hasPrefix and collections.toLen) and also by
+// their names alone, unless a matcher registered before them (in the
+// order core, strings, collections, reflect) has already taken the
+// name:  toLen is strings.toLen, not collections.toLen.  As in
+// Hamcrest for other languages, containsString is strings.contains.
+//
+// Matchers that accept functions, regular expressions or other values
+// that cannot be written in an expression are not registered.  The
+// returned Registry is new, so more factories can be registered with
+// it without affecting Parse.
+func NewStandardRegistry() *Registry {
+	registry := NewRegistry()
+	_RegisterAll(registry, "", map[string]interface{}{
+		"anything": core.Anything,
+		"true": core.True,
+		"false": core.False,
+		"nil": core.Nil,
+		"nonNil": core.NonNil,
+		"not": core.Not,
+		"is": core.Is,
+		"equalTo": core.EqualTo,
+		"notEqualTo": core.NotEqualTo,
+		"deepEqualTo": core.DeepEqualTo,
+		"greaterThan": core.GreaterThan,
+		"greaterThanOrEqualTo": core.GreaterThanOrEqualTo,
+		"lessThan": core.LessThan,
+		"lessThanOrEqualTo": core.LessThanOrEqualTo,
+		"allOf": core.AllOf,
+		"anyOf": core.AnyOf,
+	})
+	_RegisterAll(registry, "strings", map[string]interface{}{
+		"toString": strings.ToString,
+		"toGoString": strings.ToGoString,
+		"toLower": strings.ToLower,
+		"toUpper": strings.ToUpper,
+		"toLen": strings.ToLen,
+		"toRuneCount": strings.ToRuneCount,
+		"toGraphemeCount": strings.ToGraphemeCount,
+		"toLines": strings.ToLines,
+		"toFields": strings.ToFields,
+		"isValidUTF8": strings.IsValidUTF8,
+		"hasNoControlChars": strings.HasNoControlChars,
+		"equalToIgnoringCase": strings.EqualToIgnoringCase,
+		"equalToText": strings.EqualToText,
+		"equalToIgnoringWhitespace": strings.EqualToIgnoringWhitespace,
+		"equalToCollapsingWhitespace": strings.EqualToCollapsingWhitespace,
+		"equalToIgnoringLineEndings": strings.EqualToIgnoringLineEndings,
+		"equalToNFC": strings.EqualToNFC,
+		"equalToNFD": strings.EqualToNFD,
+		"equalToFoldingCase": strings.EqualToFoldingCase,
+		"hasPrefix": strings.HasPrefix,
+		"hasSuffix": strings.HasSuffix,
+		"contains": strings.Contains,
+		"containsInOrder": strings.ContainsInOrder,
+		"containsCount": strings.ContainsCount,
+		"containsAll": strings.ContainsAll,
+		"containsAny": strings.ContainsAny,
+		"hasPattern": strings.HasPattern,
+		"matchesPattern": strings.MatchesPattern,
+		"parsedAsInt": strings.ParsedAsInt,
+		"parsedAsFloat": strings.ParsedAsFloat,
+		"parsedAsBool": strings.ParsedAsBool,
+		"parsedAsDuration": strings.ParsedAsDuration,
+		"parsedAsTime": strings.ParsedAsTime,
+		"parsedAsURL": strings.ParsedAsURL,
+		"eachPattern": func(pattern string, matcher *base.Matcher) *base.Matcher {
+			return strings.EachPattern(pattern)(matcher)
+		},
+		"anyPattern": func(pattern string, matcher *base.Matcher) *base.Matcher {
+			return strings.AnyPattern(pattern)(matcher)
+		},
+		"onPattern": func(pattern string, matcher *base.Matcher) *base.Matcher {
+			return strings.OnPattern(pattern)(matcher)
+		},
+		"onPatternGroup": func(pattern string, group int, matcher *base.Matcher) *base.Matcher {
+			return strings.OnPatternGroup(pattern, group)(matcher)
+		},
+		"onPatternNamed": func(pattern string, name string, matcher *base.Matcher) *base.Matcher {
+			return strings.OnPatternNamed(pattern, name)(matcher)
+		},
+	})
+	registry.Register("containsString", strings.Contains)
+	_RegisterAll(registry, "collections", map[string]interface{}{
+		"anyElement": collections.AnyElement,
+		"everyElement": collections.EveryElement,
+		"anyMapElement": collections.AnyMapElement,
+		"everyMapElement": collections.EveryMapElement,
+		"toLen": collections.ToLen,
+		"empty": collections.Empty,
+	})
+	_RegisterAll(registry, "reflect", map[string]interface{}{
+		"toType": reflect.ToType,
+		"sameTypeAs": reflect.SameTypeAs,
+		"hasField": reflect.HasField,
+		"bool": reflect.Bool,
+		"int": reflect.Int,
+		"int8": reflect.Int8,
+		"int16": reflect.Int16,
+		"int32": reflect.Int32,
+		"int64": reflect.Int64,
+		"uint": reflect.Uint,
+		"uint8": reflect.Uint8,
+		"uint16": reflect.Uint16,
+		"uint32": reflect.Uint32,
+		"uint64": reflect.Uint64,
+		"float32": reflect.Float32,
+		"float64": reflect.Float64,
+		"complex": reflect.Complex,
+		"complex64": reflect.Complex64,
+		"complex128": reflect.Complex128,
+		"string": reflect.String,
+		"boolType": reflect.BoolType,
+		"intType": reflect.IntType,
+		"int8Type": reflect.Int8Type,
+		"int16Type": reflect.Int16Type,
+		"int32Type": reflect.Int32Type,
+		"int64Type": reflect.Int64Type,
+		"uintType": reflect.UintType,
+		"uint8Type": reflect.Uint8Type,
+		"uint16Type": reflect.Uint16Type,
+		"uint32Type": reflect.Uint32Type,
+		"uint64Type": reflect.Uint64Type,
+		"float32Type": reflect.Float32Type,
+		"float64Type": reflect.Float64Type,
+		"complexType": reflect.ComplexType,
+		"complex64Type": reflect.Complex64Type,
+		"complex128Type": reflect.Complex128Type,
+		"stringType": reflect.StringType,
+		"arrayOf": reflect.ArrayOf,
+		"channelOf": reflect.ChannelOf,
+		"sliceOf": reflect.SliceOf,
+		"mapOf": reflect.MapOf,
+		"ptrTo": reflect.PtrTo,
+		"arrayTypeOf": reflect.ArrayTypeOf,
+		"channelTypeOf": reflect.ChannelTypeOf,
+		"sliceTypeOf": reflect.SliceTypeOf,
+		"mapTypeOf": reflect.MapTypeOf,
+		"ptrTypeTo": reflect.PtrTypeTo,
+	})
+	return registry
+}
+
+// Registers each factory by its name qualified by the package name
+// (unless the package name is empty), and by its name alone if that is
+// not yet taken.
+func _RegisterAll(registry *Registry, pkg string, factories map[string]interface{}) {
+	for name, factory := range factories {
+		if pkg != "" {
+			registry.Register(pkg + "." + name, factory)
+		}
+		if !registry.Has(name) {
+			registry.Register(name, factory)
+		}
+	}
+}
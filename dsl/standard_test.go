@@ -0,0 +1,133 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dsl
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	. "github.com/rdrdr/hamcrest/core"
+	"github.com/rdrdr/hamcrest/slices"
+	"testing"
+)
+
+type User struct {
+	Name string
+	Tags []string
+}
+
+// Parses the text with the standard registry, failing the test if the
+// text is not valid.
+func mustParse(t *testing.T, text string) *base.Matcher {
+	matcher, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Parsing %q: unexpected error %v", text, err)
+	}
+	return matcher
+}
+
+func Test_Standard_core(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(mustParse(t, "anything").Match(nil), Matched)
+	we.CheckThat(mustParse(t, "true()").Match(true), Matched)
+	we.CheckThat(mustParse(t, "false()").Match(true), DidNotMatch)
+	we.CheckThat(mustParse(t, "nil()").Match(nil), Matched)
+	we.CheckThat(mustParse(t, "nonNil").Match(nil), DidNotMatch)
+	we.CheckThat(mustParse(t, "is(equalTo(3))").Match(3), Matched)
+	we.CheckThat(mustParse(t, "not(equalTo(3))").Match(3), DidNotMatch)
+	we.CheckThat(mustParse(t, `notEqualTo("a")`).Match("b"), Matched)
+	we.CheckThat(mustParse(t, "greaterThanOrEqualTo(2.5)").Match(2.5), Matched)
+	we.CheckThat(mustParse(t, "lessThanOrEqualTo(-1)").Match(0), DidNotMatch)
+	we.CheckThat(mustParse(t, "anyOf(lessThan(0), greaterThan(10))").Match(11), Matched)
+	we.CheckThat(mustParse(t, "allOf(greaterThan(3), lessThan(10))").Match(10), DidNotMatch)
+}
+
+func Test_Standard_strings(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(mustParse(t, `containsString("ell")`).Match("hello"), Matched)
+	we.CheckThat(mustParse(t, `strings.contains("ell")`).Match("help"), DidNotMatch)
+	we.CheckThat(mustParse(t, `hasPrefix("he")`).Match("hello"), Matched)
+	we.CheckThat(mustParse(t, `containsInOrder("h", "l", "o")`).Match("hello"), Matched)
+	we.CheckThat(mustParse(t, `containsCount("l", equalTo(2))`).Match("hello"), Matched)
+	we.CheckThat(mustParse(t, `toLower(equalTo("hello"))`).Match("HeLLo"), Matched)
+	we.CheckThat(mustParse(t, `matchesPattern("\\d+")`).Match("2011"), Matched)
+	we.CheckThat(mustParse(t, "matchesPattern(`\\d+`)").Match("in 2011"), DidNotMatch)
+	we.CheckThat(mustParse(t, `onPatternGroup("(\\d+)-", 1, parsedAsInt(greaterThan(5)))`).
+		Match("7-"), Matched)
+	we.CheckThat(mustParse(t, `parsedAsInt(greaterThan(5))`).Match("3"), DidNotMatch)
+}
+
+func Test_Standard_collections(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(mustParse(t, "anyElement(greaterThan(2))").Match([]int{1, 2, 3}), Matched)
+	we.CheckThat(mustParse(t, "everyElement(greaterThan(2))").Match([]int{1, 2, 3}), DidNotMatch)
+	we.CheckThat(mustParse(t, "empty").Match(map[string]int{}), Matched)
+	we.CheckThat(mustParse(t, "collections.toLen(equalTo(3))").Match([]int{1, 2, 3}), Matched)
+}
+
+func Test_Standard_reflect(t *testing.T) {
+	we := asserter.Using(t)
+	user := User{Name: "xavier", Tags: []string{"admin"}}
+	we.CheckThat(mustParse(t, `hasField("Name", containsString("x"))`).Match(user), Matched)
+	we.CheckThat(mustParse(t, `hasField("Name", containsString("y"))`).Match(user), DidNotMatch)
+	we.CheckThat(mustParse(t, `hasField("Tags", anyElement(equalTo("admin")))`).Match(user), Matched)
+	we.CheckThat(mustParse(t, "int").Match(3), Matched)
+	we.CheckThat(mustParse(t, "string").Match(3), DidNotMatch)
+	we.CheckThat(mustParse(t, "sliceOf(stringType)").Match(user.Tags), Matched)
+	we.CheckThat(mustParse(t, `sameTypeAs("")`).Match("x"), Matched)
+	we.CheckThat(mustParse(t, "toType(mapTypeOf(stringType, intType))").
+		Match(map[string]int{}), Matched)
+}
+
+func Test_Standard_qualifiedAndUnqualifiedNames(t *testing.T) {
+	we := asserter.Using(t)
+	// toLen is strings.toLen, which measures strings (not slices).
+	we.CheckThat(mustParse(t, "toLen(equalTo(2))").Match("ab"), Matched)
+	we.CheckThat(mustParse(t, "strings.toLen(equalTo(2))").Match("ab"), Matched)
+	we.CheckThat(mustParse(t, "collections.toLen(equalTo(2))").Match([]int{1, 2}), Matched)
+
+	names := NewStandardRegistry().Names()
+	for _, name := range []string{
+			"allOf", "containsString", "strings.contains", "contains",
+			"collections.empty", "empty", "reflect.hasField", "hasField", "int"} {
+		we.CheckThat(names, slices.AnyElement(EqualTo(name)))
+	}
+	we.CheckThat(names, Not(slices.AnyElement(EqualTo("core.allOf"))))
+}
+
+func Test_Standard_isIndependentOfParse(t *testing.T) {
+	we := asserter.Using(t)
+	registry := NewStandardRegistry()
+	registry.Register("positive", func() *base.Matcher { return GreaterThan(0) })
+	matcher, err := registry.Parse("allOf(positive, lessThan(10))")
+	we.CheckThat(err, Is(Nil()))
+	we.CheckThat(matcher.Match(5), Matched)
+	_, err = Parse("positive")
+	we.CheckThat(err, Is(NonNil()))
+}
+
+func Test_Standard_roundTrip(t *testing.T) {
+	we := asserter.Using(t)
+	for _, text := range []string{
+			"allOf(greaterThan(3), lessThan(10))",
+			"anyOf(nil(), strings.toLen(equalTo(0)))",
+			`hasField("Name", containsString("x"))`,
+			`hasField("Tags", everyElement(matchesPattern("^[a-z]+\\d*$")))`,
+			"not(is(deepEqualTo(nil)))",
+			"allOf(greaterThan(-2.5), lessThan(1e+21), notEqualTo(true))",
+			"anything()",
+			"allOf()",
+	} {
+		matcher := mustParse(t, text)
+		reparsed := mustParse(t, matcher.String())
+		we.CheckThat(reparsed.String(), EqualTo(matcher.String()))
+		we.CheckThat(text, EqualTo(matcher.String()).Comment("already canonical"))
+	}
+}
+
+func Test_Standard_normalizes(t *testing.T) {
+	we := asserter.Using(t)
+	matcher := mustParse(t, " allOf( greaterThan(3),lessThan(1e1), hasPrefix(`a\\b`) ) ")
+	we.CheckThat(matcher.String(), EqualTo(`allOf(greaterThan(3), lessThan(10.0), hasPrefix("a\\b"))`))
+}
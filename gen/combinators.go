@@ -0,0 +1,233 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/property"
+	"rand"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Generates slices (of type []interface{}) of up to MaxLen elements,
+// each produced by the given generator.  Slices shrink by removing
+// elements (see property.Shrink), and then by shrinking one element.
+// (For slices of a specific type, use ForType.)
+func SliceOf(elem property.Generator) *Generator {
+	generate := func(random *rand.Rand) interface{} {
+		slice := make([]interface{}, random.Intn(MaxLen + 1))
+		for i := range slice {
+			slice[i] = elem.Generate(random)
+		}
+		return slice
+	}
+	shrink := func(value interface{}) []interface{} {
+		return _ShrinkSlice(elem, reflect.NewValue(value).(*reflect.SliceValue))
+	}
+	return NewGeneratorf(generate, shrink, "SliceOf(%v)", elem).
+		generating(reflect.Typeof([]interface{}{}))
+}
+
+// Returns the candidates of property.Shrink for a slice, followed by
+// copies of the slice with a single element shrunk.
+func _ShrinkSlice(elem property.Generator, slice *reflect.SliceValue) []interface{} {
+	candidates := property.Shrink(slice.Interface())
+	for i := 0; i < slice.Len(); i++ {
+		for _, shrunk := range _ShrinkWith(elem, slice.Elem(i).Interface()) {
+			copied := reflect.MakeSlice(slice.Type().(*reflect.SliceType), slice.Len(), slice.Len())
+			for j := 0; j < slice.Len(); j++ {
+				copied.Elem(j).SetValue(slice.Elem(j))
+			}
+			_Set(copied.Elem(i), shrunk)
+			candidates = append(candidates, copied.Interface())
+		}
+	}
+	return candidates
+}
+
+// Generates maps (of type map[interface{}]interface{}) of up to MaxLen
+// entries, with keys and values produced by the given generators.
+// (Duplicate keys are generated only once, so a map may have fewer
+// entries than intended.)  Maps shrink by removing entries, and then
+// by shrinking one value.  (For maps of a specific type, use ForType.)
+func MapOf(key, value property.Generator) *Generator {
+	generate := func(random *rand.Rand) interface{} {
+		m := make(map[interface{}]interface{})
+		for n := random.Intn(MaxLen + 1); n > 0; n-- {
+			m[key.Generate(random)] = value.Generate(random)
+		}
+		return m
+	}
+	shrink := func(actual interface{}) []interface{} {
+		return _ShrinkMap(value, reflect.NewValue(actual).(*reflect.MapValue))
+	}
+	return NewGeneratorf(generate, shrink, "MapOf(%v, %v)", key, value).
+		generating(reflect.Typeof(map[interface{}]interface{}{}))
+}
+
+// Returns an empty copy of the map, copies with a single entry
+// removed, and copies with a single value shrunk.
+func _ShrinkMap(value property.Generator, m *reflect.MapValue) []interface{} {
+	if m.IsNil() || m.Len() == 0 {
+		return nil
+	}
+	mapType := m.Type().(*reflect.MapType)
+	copyExcept := func(skip reflect.Value) *reflect.MapValue {
+		copied := reflect.MakeMap(mapType)
+		for _, k := range m.Keys() {
+			if skip == nil || k.Interface() != skip.Interface() {
+				copied.SetElem(k, m.Elem(k))
+			}
+		}
+		return copied
+	}
+	candidates := []interface{}{reflect.MakeMap(mapType).Interface()}
+	keys := m.Keys()
+	if len(keys) > 1 {
+		for _, k := range keys {
+			candidates = append(candidates, copyExcept(k).Interface())
+		}
+	}
+	for _, k := range keys {
+		for _, shrunk := range _ShrinkWith(value, m.Elem(k).Interface()) {
+			copied := copyExcept(nil)
+			copied.SetElem(k, _ValueOf(shrunk, mapType.Elem()))
+			candidates = append(candidates, copied.Interface())
+		}
+	}
+	return candidates
+}
+
+// Generates values from one of the given generators, chosen at random
+// with equal probability.  A value is shrunk by the first of the
+// generators that can shrink it (see Frequency).
+func OneOf(generators...property.Generator) *Generator {
+	weighted := make([]*Weighted, len(generators))
+	names := make([]string, len(generators))
+	for i, generator := range generators {
+		weighted[i] = Weight(1, generator)
+		names[i] = fmt.Sprint(generator)
+	}
+	return _Choose(weighted, "OneOf(" + strings.Join(names, ", ") + ")")
+}
+
+// A generator with a weight, for use with Frequency.
+type Weighted struct {
+	weight int
+	generator property.Generator
+}
+
+// Returns the given generator with the given (positive) weight.
+func Weight(weight int, generator property.Generator) *Weighted {
+	if weight <= 0 {
+		panic(fmt.Sprintf("weight must be positive, was %v", weight))
+	}
+	return &Weighted{weight, generator}
+}
+
+// Implements fmt.Stringer.
+func (self *Weighted) String() string {
+	return fmt.Sprintf("%v:%v", self.weight, self.generator)
+}
+
+// Generates values from one of the given generators, chosen at random
+// in proportion to their weights.  For example, to generate mostly
+// small numbers and sometimes any int:
+//    Frequency(Weight(9, Int8()), Weight(1, Int()))
+// A value is shrunk by the first of the generators that can shrink it
+// to simpler values of the same type.  The generators of this package
+// are only asked to shrink values of the types they generate; any
+// other generator is also asked to shrink values of other types, and
+// is taken not to be able to if it fails a type assertion doing so.
+func Frequency(choices...*Weighted) *Generator {
+	names := make([]string, len(choices))
+	for i, choice := range choices {
+		names[i] = choice.String()
+	}
+	return _Choose(choices, "Frequency(" + strings.Join(names, ", ") + ")")
+}
+
+// Returns a Generator that chooses among the given generators in
+// proportion to their weights.
+func _Choose(choices []*Weighted, description string) *Generator {
+	if len(choices) == 0 {
+		panic("at least one generator is required")
+	}
+	total := 0
+	types := []reflect.Type{}
+	for _, choice := range choices {
+		total += choice.weight
+		types = _TypesOf(choice.generator, types)
+	}
+	generate := func(random *rand.Rand) interface{} {
+		n := random.Intn(total)
+		for _, choice := range choices {
+			if n < choice.weight {
+				return choice.generator.Generate(random)
+			}
+			n -= choice.weight
+		}
+		panic("unreachable")
+	}
+	shrink := func(value interface{}) []interface{} {
+		shrinkable := false
+		for _, choice := range choices {
+			candidates, ok := _TryShrinkWith(choice.generator, value)
+			if ok && len(candidates) > 0 {
+				return candidates
+			}
+			shrinkable = shrinkable || ok
+		}
+		if shrinkable {
+			return nil // the value is as simple as it gets
+		}
+		return property.Shrink(value)
+	}
+	return NewGeneratorf(generate, shrink, "%v", description).generating(types...)
+}
+
+// Appends the types of the values that the given generator generates
+// to types, or returns nil if they are not known (or types is nil).
+func _TypesOf(generator property.Generator, types []reflect.Type) []reflect.Type {
+	if g, ok := generator.(*Generator); ok && g.types != nil && types != nil {
+		return append(types, g.types...)
+	}
+	return nil
+}
+
+// Shrinks the value with the given generator (see _ShrinkWith), if it
+// can:  returns ok=false if the generator does not generate values of
+// the value's type, or shrinks the value to values of another type.
+// If the types that the generator generates are not known, it is also
+// taken not to generate the value if it fails a type assertion while
+// shrinking it; any other panic is a bug, and is passed on.
+func _TryShrinkWith(generator property.Generator, value interface{}) (candidates []interface{}, ok bool) {
+	valueType := reflect.Typeof(value)
+	if g, known := generator.(*Generator); known && g.types != nil && value != nil {
+		for _, t := range g.types {
+			if t == valueType {
+				return _ShrinkWith(generator, value), true
+			}
+		}
+		return nil, false
+	}
+	defer func() {
+		if err := recover(); err != nil {
+			if _, isTypeError := err.(*runtime.TypeAssertionError); !isTypeError {
+				panic(err)
+			}
+			candidates, ok = nil, false
+		}
+	}()
+	candidates = _ShrinkWith(generator, value)
+	for _, candidate := range candidates {
+		if candidate != nil && reflect.Typeof(candidate) != valueType {
+			return nil, false
+		}
+	}
+	return candidates, true
+}
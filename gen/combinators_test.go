@@ -0,0 +1,134 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	. "github.com/rdrdr/hamcrest/core"
+	"github.com/rdrdr/hamcrest/property"
+	"github.com/rdrdr/hamcrest/reflect"
+	"github.com/rdrdr/hamcrest/slices"
+	"rand"
+	"testing"
+)
+
+func Test_SliceOf(t *testing.T) {
+	we := asserter.Using(t)
+	bytes := SliceOf(Uint8())
+	checkGenerates(t, bytes, slices.EachElem(reflect.Uint8()))
+	checkGenerates(t, bytes, slices.ToLen(LessThanOrEqualTo(MaxLen)))
+	we.CheckThat(bytes.String(), EqualTo("SliceOf(Uint8)"))
+
+	shrunk := bytes.Shrink([]interface{}{uint8(4), uint8(1)})
+	we.AssertThat(shrunk, slices.ToLen(EqualTo(7)))
+	we.CheckThat(shrunk[0], slices.Empty())
+	we.CheckThat(shrunk[3].([]interface{})[0], EqualTo(uint8(0)).Comment("first element shrunk"))
+	we.CheckThat(shrunk[6].([]interface{})[1], EqualTo(uint8(0)).Comment("second element shrunk"))
+}
+
+func Test_SliceOf_shrinksToMinimalCounterexample(t *testing.T) {
+	we := asserter.Using(t)
+	noLargeElements := slices.EachElem(LessThan(10))
+	result := property.Holds().Match(
+		property.ForAll(SliceOf(Int()), noLargeElements).WithSeed(1))
+	we.AssertThat(result, DidNotMatch)
+	minimal := result.Causes()[0].Value().([]interface{})
+	we.AssertThat(len(minimal), EqualTo(1))
+	we.CheckThat(minimal[0], EqualTo(10))
+}
+
+func Test_MapOf(t *testing.T) {
+	we := asserter.Using(t)
+	m := MapOf(String(), Bool())
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		for key, value := range m.Generate(random).(map[interface{}]interface{}) {
+			we.CheckThat(key, reflect.String())
+			we.CheckThat(value, reflect.Bool())
+		}
+	}
+	we.CheckThat(m.String(), EqualTo("MapOf(String, Bool)"))
+
+	shrunk := m.Shrink(map[interface{}]interface{}{"a": true, "b": false})
+	we.AssertThat(shrunk, slices.ToLen(EqualTo(4)))
+	we.CheckThat(len(shrunk[0].(map[interface{}]interface{})), EqualTo(0))
+	we.CheckThat(len(shrunk[1].(map[interface{}]interface{})), EqualTo(1))
+	we.CheckThat(shrunk[3].(map[interface{}]interface{})["a"], EqualTo(false))
+	we.CheckThat(m.Shrink(map[interface{}]interface{}{}), slices.Empty())
+}
+
+func Test_OneOf(t *testing.T) {
+	we := asserter.Using(t)
+	mixed := OneOf(Bool(), String())
+	checkGenerates(t, mixed, AnyOf(reflect.Bool(), reflect.String()))
+	we.CheckThat(mixed.String(), EqualTo("OneOf(Bool, String)"))
+	we.CheckThat(mixed.Shrink(true)[0], EqualTo(false).Comment("shrunk by Bool"))
+	we.CheckThat(mixed.Shrink("ab")[0], EqualTo("").Comment("shrunk by String"))
+
+	random := rand.New(rand.NewSource(1))
+	bools := 0
+	for i := 0; i < 1000; i++ {
+		if _, ok := mixed.Generate(random).(bool); ok {
+			bools++
+		}
+	}
+	we.CheckThat(bools, AllOf(GreaterThan(400), LessThan(600)))
+}
+
+func Test_OneOf_doesNotGenerateUntilAsked(t *testing.T) {
+	we := asserter.Using(t)
+	calls := 0
+	counted := NewGeneratorf(func(random *rand.Rand) interface{} {
+		calls++
+		return calls
+	}, nil, "Counted")
+	impossible := Bool().Filter(EqualTo("never"))
+	mixed := OneOf(impossible, Bool(), counted)
+	we.CheckThat(calls, EqualTo(0).Comment("nothing generated when constructed"))
+	we.CheckThat(mixed.Shrink(true), slices.ToLen(EqualTo(1)))
+	we.CheckThat(mixed.Shrink(true)[0], EqualTo(false).Comment("shrunk by Bool"))
+	we.CheckThat(mixed.Shrink(false), slices.Empty())
+	we.CheckThat(mixed.Shrink(4)[0], EqualTo(0).Comment("shrunk by Counted"))
+	we.CheckThat(calls, EqualTo(0).Comment("nothing generated when shrinking"))
+}
+
+func Test_OneOf_passesOnPanicsFromShrinking(t *testing.T) {
+	we := asserter.Using(t)
+	generate := func(random *rand.Rand) interface{} { return 1 }
+	ints := NewGeneratorf(generate, func(value interface{}) []interface{} {
+		return property.Shrink(value.(int))
+	}, "Ints")
+	buggy := NewGeneratorf(generate, func(value interface{}) []interface{} {
+		panic("bug")
+	}, "Buggy")
+	we.CheckThat(OneOf(ints, String()).Shrink("ab")[0], EqualTo("").
+		Comment("a failed type assertion means Ints cannot shrink strings"))
+	we.CheckThat("ab", PanicWhenApplying(func(s string) {
+		OneOf(buggy, String()).Shrink(s)
+	}, "Shrink"))
+	buggyFilter := base.NewMatcherf(func(value interface{}) *base.Result {
+		panic("bug")
+	}, "Buggy")
+	we.CheckThat(true, PanicWhenApplying(func(b bool) {
+		OneOf(Bool().Filter(buggyFilter), String()).Shrink(b)
+	}, "Shrink with a buggy filter"))
+}
+
+func Test_Frequency(t *testing.T) {
+	we := asserter.Using(t)
+	mostlyBools := Frequency(Weight(9, Bool()), Weight(1, String()))
+	we.CheckThat(mostlyBools.String(), EqualTo("Frequency(9:Bool, 1:String)"))
+	random := rand.New(rand.NewSource(1))
+	bools := 0
+	for i := 0; i < 1000; i++ {
+		if _, ok := mostlyBools.Generate(random).(bool); ok {
+			bools++
+		}
+	}
+	we.CheckThat(bools, AllOf(GreaterThan(850), LessThan(950)))
+	we.CheckThat(0, PanicWhenApplying(func(weight int) { Weight(weight, Bool()) }, "Weight"))
+	we.CheckThat(0, PanicWhenApplying(func(ignored int) { Frequency() }, "Frequency"))
+}
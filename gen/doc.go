@@ -0,0 +1,23 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+	Provides Generators of random values for property-based testing
+	with the hamcrest property package.  There are generators for each
+	kind of basic value (Bool, Int, Uint8, Float64, String and so on),
+	generators built from others (SliceOf, MapOf, OneOf, Frequency), and
+	generators for any type, built by reflection (ForType, StructOf):
+	
+		users := gen.StructOf(User{})
+		property.ForAll(users, HasValidEmail()).Check(we)
+	
+		smallInts := gen.Int().Filter(LessThan(100))
+		property.ForAll(gen.SliceOf(smallInts), SumsToLessThan(2000)).Check(we)
+	
+	Every Generator also implements property.Shrinker, so that failing
+	values are shrunk to minimal counterexamples:  numbers toward zero,
+	strings, slices and maps by removing elements, and composite values
+	by shrinking one part at a time.
+*/
+package gen
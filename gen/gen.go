@@ -0,0 +1,334 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/base"
+	"github.com/rdrdr/hamcrest/property"
+	"math"
+	"rand"
+	"reflect"
+)
+
+// The most elements generated for a string, slice or map.
+const MaxLen = 20
+
+// The most values that Filter generates before giving up.
+const MaxFilterTries = 1000
+
+// A self-describing generator of random values, which also knows how
+// to shrink the values it generates.  Implements both
+// property.Generator and property.Shrinker.
+type Generator struct {
+	description base.SelfDescribing
+	generate func(random *rand.Rand) interface{}
+	shrink func(value interface{}) []interface{}
+	types []reflect.Type // of the generated values, or nil if not known
+}
+
+// Creates a new Generator with the given generate function and the
+// given description.  If shrink is nil, generated values are shrunk
+// by property.Shrink.
+func NewGeneratorf(generate func(random *rand.Rand) interface{},
+		shrink func(value interface{}) []interface{},
+		format string, args...interface{}) *Generator {
+	if shrink == nil {
+		shrink = property.Shrink
+	}
+	return &Generator{
+		description: base.Description(format, args...),
+		generate: generate,
+		shrink: shrink,
+	}
+}
+
+// Records that this generator generates only values of the given types
+// (so that OneOf and Frequency can tell which values it can shrink).
+func (self *Generator) generating(types...reflect.Type) *Generator {
+	self.types = types
+	return self
+}
+
+// Implements property.Generator.
+func (self *Generator) Generate(random *rand.Rand) interface{} {
+	return self.generate(random)
+}
+
+// Implements property.Shrinker.
+func (self *Generator) Shrink(value interface{}) []interface{} {
+	return self.shrink(value)
+}
+
+// Implements fmt.Stringer.
+func (self *Generator) String() string {
+	return self.description.String()
+}
+
+// Returns a Generator of the values of this generator that the given
+// matcher matches.  (Shrunk values are also restricted to those that
+// the matcher matches.)  Generate panics if MaxFilterTries values in a
+// row are rejected, so the matcher should not be too strict.
+func (self *Generator) Filter(matcher *base.Matcher) *Generator {
+	generate := func(random *rand.Rand) interface{} {
+		var result *base.Result
+		for i := 0; i < MaxFilterTries; i++ {
+			value := self.Generate(random)
+			if result = matcher.Match(value); result.Matched() {
+				return value
+			}
+		}
+		panic(fmt.Sprintf("%v rejected %v values in a row; the last because %v",
+			matcher, MaxFilterTries, result))
+	}
+	shrink := func(value interface{}) []interface{} {
+		var candidates []interface{}
+		for _, candidate := range self.Shrink(value) {
+			if matcher.Match(candidate).Matched() {
+				candidates = append(candidates, candidate)
+			}
+		}
+		return candidates
+	}
+	return NewGeneratorf(generate, shrink, "%v.Filter(%v)", self, matcher).
+		generating(self.types...)
+}
+
+// Returns the values that a value generated by the given generator
+// shrinks to:  its own Shrink if it implements property.Shrinker, and
+// otherwise property.Shrink.
+func _ShrinkWith(generator property.Generator, value interface{}) []interface{} {
+	if shrinker, ok := generator.(property.Shrinker); ok {
+		return shrinker.Shrink(value)
+	}
+	return property.Shrink(value)
+}
+
+// Returns a Value holding the given value, or the zero value of the
+// given type if the value is nil (as generated values of interface,
+// pointer, slice or map type may be).
+func _ValueOf(value interface{}, t reflect.Type) reflect.Value {
+	if value == nil {
+		return reflect.MakeZero(t)
+	}
+	return reflect.NewValue(value)
+}
+
+// Sets dst to the given value (see _ValueOf).
+func _Set(dst reflect.Value, value interface{}) {
+	dst.SetValue(_ValueOf(value, dst.Type()))
+}
+
+// Generates booleans; true shrinks to false.
+func Bool() *Generator {
+	return _BoolOf(reflect.Typeof(false), "Bool")
+}
+
+// Generates ints of all magnitudes (biased toward small ones), which
+// shrink toward zero.
+func Int() *Generator {
+	return _IntOf(reflect.Typeof(int(0)), "Int")
+}
+
+// Like Int, for int8.
+func Int8() *Generator {
+	return _IntOf(reflect.Typeof(int8(0)), "Int8")
+}
+
+// Like Int, for int16.
+func Int16() *Generator {
+	return _IntOf(reflect.Typeof(int16(0)), "Int16")
+}
+
+// Like Int, for int32.
+func Int32() *Generator {
+	return _IntOf(reflect.Typeof(int32(0)), "Int32")
+}
+
+// Like Int, for int64.
+func Int64() *Generator {
+	return _IntOf(reflect.Typeof(int64(0)), "Int64")
+}
+
+// Generates uints of all magnitudes (biased toward small ones), which
+// shrink toward zero.
+func Uint() *Generator {
+	return _UintOf(reflect.Typeof(uint(0)), "Uint")
+}
+
+// Like Uint, for uint8.
+func Uint8() *Generator {
+	return _UintOf(reflect.Typeof(uint8(0)), "Uint8")
+}
+
+// Like Uint, for uint16.
+func Uint16() *Generator {
+	return _UintOf(reflect.Typeof(uint16(0)), "Uint16")
+}
+
+// Like Uint, for uint32.
+func Uint32() *Generator {
+	return _UintOf(reflect.Typeof(uint32(0)), "Uint32")
+}
+
+// Like Uint, for uint64.
+func Uint64() *Generator {
+	return _UintOf(reflect.Typeof(uint64(0)), "Uint64")
+}
+
+// Generates finite float32s of all magnitudes, which shrink toward
+// zero (and toward whole numbers).
+func Float32() *Generator {
+	return _FloatOf(reflect.Typeof(float32(0)), "Float32")
+}
+
+// Like Float32, for float64.
+func Float64() *Generator {
+	return _FloatOf(reflect.Typeof(float64(0)), "Float64")
+}
+
+// Generates complex values whose parts are generated as by Float64,
+// which shrink toward zero (and toward real numbers).
+func Complex() *Generator {
+	return _ComplexOf(reflect.Typeof(complex(0, 0i)), "Complex")
+}
+
+// Like Complex, for complex64.
+func Complex64() *Generator {
+	return _ComplexOf(reflect.Typeof(complex64(0i)), "Complex64")
+}
+
+// Like Complex, for complex128.
+func Complex128() *Generator {
+	return _ComplexOf(reflect.Typeof(complex128(0i)), "Complex128")
+}
+
+// Generates strings of up to MaxLen runes (mostly printable ASCII,
+// with the occasional non-ASCII rune), which shrink by truncation.
+func String() *Generator {
+	return _StringOf(reflect.Typeof(""), "String")
+}
+
+func _BoolOf(t reflect.Type, name string) *Generator {
+	generate := func(random *rand.Rand) interface{} {
+		value := reflect.MakeZero(t).(*reflect.BoolValue)
+		value.Set(random.Intn(2) == 1)
+		return value.Interface()
+	}
+	shrink := func(value interface{}) []interface{} {
+		if reflect.NewValue(value).(*reflect.BoolValue).Get() {
+			return []interface{}{reflect.MakeZero(t).Interface()}
+		}
+		return nil
+	}
+	return NewGeneratorf(generate, shrink, "%v", name).generating(t)
+}
+
+func _IntOf(t reflect.Type, name string) *Generator {
+	bits := 8 * t.Size()
+	generate := func(random *rand.Rand) interface{} {
+		magnitude := uint(random.Intn(int(bits)))
+		n := random.Int63() >> (63 - magnitude)
+		if random.Intn(2) == 1 {
+			n = -n
+		}
+		value := reflect.MakeZero(t).(*reflect.IntValue)
+		value.Set(n)
+		return value.Interface()
+	}
+	return NewGeneratorf(generate, nil, "%v", name).generating(t)
+}
+
+func _UintOf(t reflect.Type, name string) *Generator {
+	bits := 8 * t.Size()
+	generate := func(random *rand.Rand) interface{} {
+		magnitude := uint(random.Intn(int(bits) + 1))
+		n := uint64(random.Int63()) | uint64(random.Intn(2)) << 63
+		value := reflect.MakeZero(t).(*reflect.UintValue)
+		value.Set(n >> (64 - magnitude))
+		return value.Interface()
+	}
+	return NewGeneratorf(generate, nil, "%v", name).generating(t)
+}
+
+// Returns a finite float of random sign, roughly uniform in magnitude
+// between 10^-3 and 10^6 (and occasionally zero).
+func _RandomFloat(random *rand.Rand) float64 {
+	if random.Intn(10) == 0 {
+		return 0
+	}
+	return (2 * random.Float64() - 1) * math.Pow(10, float64(random.Intn(10) - 3))
+}
+
+func _FloatOf(t reflect.Type, name string) *Generator {
+	generate := func(random *rand.Rand) interface{} {
+		value := reflect.MakeZero(t).(*reflect.FloatValue)
+		value.Set(_RandomFloat(random))
+		return value.Interface()
+	}
+	shrink := func(value interface{}) []interface{} {
+		f := reflect.NewValue(value).(*reflect.FloatValue).Get()
+		var candidates []interface{}
+		for _, x := range []float64{0, math.Trunc(f), f / 2} {
+			if x != f && math.Fabs(x) < math.Fabs(f) {
+				shrunk := reflect.MakeZero(t).(*reflect.FloatValue)
+				shrunk.Set(x)
+				candidates = append(candidates, shrunk.Interface())
+			}
+		}
+		return candidates
+	}
+	return NewGeneratorf(generate, shrink, "%v", name).generating(t)
+}
+
+func _ComplexOf(t reflect.Type, name string) *Generator {
+	generate := func(random *rand.Rand) interface{} {
+		value := reflect.MakeZero(t).(*reflect.ComplexValue)
+		value.Set(complex(_RandomFloat(random), _RandomFloat(random)))
+		return value.Interface()
+	}
+	shrink := func(value interface{}) []interface{} {
+		c := reflect.NewValue(value).(*reflect.ComplexValue).Get()
+		var candidates []interface{}
+		for _, x := range []complex128{0, complex(real(c), 0),
+				complex(math.Trunc(real(c)), math.Trunc(imag(c)))} {
+			if x != c {
+				shrunk := reflect.MakeZero(t).(*reflect.ComplexValue)
+				shrunk.Set(x)
+				candidates = append(candidates, shrunk.Interface())
+			}
+		}
+		return candidates
+	}
+	return NewGeneratorf(generate, shrink, "%v", name).generating(t)
+}
+
+// Returns a random rune:  usually printable ASCII, and otherwise any
+// valid code point beyond ASCII.
+func _RandomRune(random *rand.Rand) int {
+	if random.Intn(10) > 0 {
+		return ' ' + random.Intn('~' - ' ' + 1)
+	}
+	for {
+		rune := 0x80 + random.Intn(0x10FFFF - 0x80 + 1)
+		if rune < 0xD800 || 0xDFFF < rune {
+			return rune
+		}
+	}
+	panic("unreachable")
+}
+
+func _StringOf(t reflect.Type, name string) *Generator {
+	generate := func(random *rand.Rand) interface{} {
+		runes := make([]int, random.Intn(MaxLen + 1))
+		for i := range runes {
+			runes[i] = _RandomRune(random)
+		}
+		value := reflect.MakeZero(t).(*reflect.StringValue)
+		value.Set(string(runes))
+		return value.Interface()
+	}
+	return NewGeneratorf(generate, nil, "%v", name).generating(t)
+}
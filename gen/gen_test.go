@@ -0,0 +1,117 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	. "github.com/rdrdr/hamcrest/core"
+	"github.com/rdrdr/hamcrest/property"
+	"github.com/rdrdr/hamcrest/reflect"
+	"github.com/rdrdr/hamcrest/slices"
+	"github.com/rdrdr/hamcrest/strings"
+	"rand"
+	"testing"
+	"utf8"
+)
+
+var Matched = base.Matched()
+var DidNotMatch = base.DidNotMatch()
+
+// Checks that every value of a sample from the generator is matched.
+func checkGenerates(t *testing.T, generator property.Generator, matcher *base.Matcher) {
+	property.ForAll(generator, matcher).WithSeed(1).WithMaxShrinks(0).Check(asserter.Using(t))
+}
+
+func Test_Kinds(t *testing.T) {
+	checkGenerates(t, Bool(), reflect.Bool())
+	checkGenerates(t, Int(), reflect.Int())
+	checkGenerates(t, Int8(), reflect.Int8())
+	checkGenerates(t, Int16(), reflect.Int16())
+	checkGenerates(t, Int32(), reflect.Int32())
+	checkGenerates(t, Int64(), reflect.Int64())
+	checkGenerates(t, Uint(), reflect.Uint())
+	checkGenerates(t, Uint8(), reflect.Uint8())
+	checkGenerates(t, Uint16(), reflect.Uint16())
+	checkGenerates(t, Uint32(), reflect.Uint32())
+	checkGenerates(t, Uint64(), reflect.Uint64())
+	checkGenerates(t, Float32(), reflect.Float32())
+	checkGenerates(t, Float64(), reflect.Float64())
+	checkGenerates(t, Complex(), reflect.Complex())
+	checkGenerates(t, Complex64(), reflect.Complex64())
+	checkGenerates(t, Complex128(), reflect.Complex128())
+	checkGenerates(t, String(), reflect.String())
+}
+
+func Test_String(t *testing.T) {
+	we := asserter.Using(t)
+	checkGenerates(t, String(), Applying(utf8.RuneCountInString, "RuneCount")(LessThanOrEqualTo(MaxLen)))
+	checkGenerates(t, String(), strings.IsValidUTF8())
+	we.CheckThat(String().String(), EqualTo("String"))
+}
+
+func Test_Int_variety(t *testing.T) {
+	we := asserter.Using(t)
+	random := rand.New(rand.NewSource(1))
+	small, large, negative := 0, 0, 0
+	for i := 0; i < 1000; i++ {
+		n := Int64().Generate(random).(int64)
+		switch {
+		case -100 < n && n < 100:
+			small++
+		case n > 1 << 40 || n < -1 << 40:
+			large++
+		}
+		if n < 0 {
+			negative++
+		}
+	}
+	we.CheckThat(small, GreaterThan(50))
+	we.CheckThat(large, GreaterThan(100))
+	we.CheckThat(negative, GreaterThan(300))
+}
+
+func Test_Shrink(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(Bool().Shrink(true), slices.ToLen(EqualTo(1)))
+	we.CheckThat(Bool().Shrink(true)[0], EqualTo(false))
+	we.CheckThat(Bool().Shrink(false), slices.Empty())
+	we.CheckThat(Int8().Shrink(int8(10))[1], EqualTo(int8(5)))
+	we.CheckThat(Float64().Shrink(2.5), slices.ToLen(EqualTo(3)))
+	we.CheckThat(Float64().Shrink(2.5)[1], EqualTo(2.0))
+	we.CheckThat(Float64().Shrink(2.0), slices.ToLen(EqualTo(2)))
+	we.CheckThat(Float64().Shrink(0.0), slices.Empty())
+	we.CheckThat(Float32().Shrink(float32(3))[0], EqualTo(float32(0)))
+	we.CheckThat(Complex128().Shrink(complex128(1.5+2i))[1], EqualTo(complex128(1.5)))
+	we.CheckThat(String().Shrink("abc")[2], EqualTo("ab"))
+}
+
+func Test_Filter(t *testing.T) {
+	we := asserter.Using(t)
+	isEven := Applying(func(n int) int { return n % 2 }, "Mod2")(EqualTo(0))
+	evens := Int().Filter(isEven)
+	checkGenerates(t, evens, isEven)
+	we.CheckThat(evens.String(), strings.HasPrefix("Int.Filter("))
+
+	result := property.Holds().Match(property.ForAll(evens, LessThan(100)).WithSeed(1))
+	we.AssertThat(result, DidNotMatch)
+	we.CheckThat(result.Causes()[0].Value(),
+		AllOf(isEven, GreaterThanOrEqualTo(100)).Comment("shrunk only to even values"))
+
+	impossible := Bool().Filter(EqualTo("never"))
+	we.CheckThat(rand.New(rand.NewSource(1)), PanicWhenApplying(func(random *rand.Rand) {
+		impossible.Generate(random)
+	}, "Generate"))
+}
+
+func Test_NewGeneratorf(t *testing.T) {
+	we := asserter.Using(t)
+	digits := NewGeneratorf(func(random *rand.Rand) interface{} {
+		return random.Intn(10)
+	}, nil, "Digits[%v]", 10)
+	we.CheckThat(digits.String(), EqualTo("Digits[10]"))
+	checkGenerates(t, digits, AllOf(GreaterThanOrEqualTo(0), LessThan(10)))
+	we.CheckThat(digits.Shrink(8)[0], EqualTo(0).Comment("uses property.Shrink"))
+}
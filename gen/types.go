@@ -0,0 +1,266 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+	"rand"
+	"reflect"
+)
+
+// Generates values of the same type as the given prototype (which is
+// used only for its type), choosing a generator for each part of the
+// type by its kind:  booleans, numbers and strings as by Bool, Int,
+// Float64, String and so on; slices, arrays and maps element by
+// element; structs field by field (exported fields only); and pointers
+// to generated values (or occasionally nil).  Channels, functions and
+// interfaces are left as their zero values, as are pointers, slices
+// and maps of a type that (recursively) contains them.
+//
+// Panics if the prototype is nil or of a kind that cannot be generated.
+func ForType(prototype interface{}) *Generator {
+	t := reflect.Typeof(prototype)
+	if t == nil {
+		panic("ForType requires a non-nil prototype")
+	}
+	generator, ok := _ForType(t, make(map[reflect.Type]bool))
+	if !ok {
+		panic(fmt.Sprintf("ForType cannot generate values of type %v", t))
+	}
+	return NewGeneratorf(generator.generate, generator.shrink, "ForType(%v)", t).
+		generating(t)
+}
+
+// Generates structs (or, if the prototype is a pointer to a struct,
+// non-nil pointers to structs) of the same type as the given prototype,
+// whose exported fields are generated as by ForType.  Structs shrink
+// by shrinking one field at a time.  For example:
+//    property.ForAll(gen.StructOf(User{}), IsValidUser()).Check(we)
+//
+// Panics if the prototype is not a struct or a pointer to a struct.
+func StructOf(prototype interface{}) *Generator {
+	t := reflect.Typeof(prototype)
+	structType, isStruct := t.(*reflect.StructType)
+	ptrType, isPtr := t.(*reflect.PtrType)
+	if isPtr {
+		structType, isStruct = ptrType.Elem().(*reflect.StructType)
+	}
+	if !isStruct {
+		panic(fmt.Sprintf("StructOf requires a struct or pointer to a struct, was %T", prototype))
+	}
+	visiting := map[reflect.Type]bool{t: true, structType: true}
+	generator := _StructOf(structType, visiting)
+	if isPtr {
+		generator = _PtrTo(ptrType, generator, false)
+	}
+	return NewGeneratorf(generator.generate, generator.shrink, "StructOf(%v)", t).
+		generating(t)
+}
+
+// Returns a generator for values of the given type (see ForType), and
+// false if the type cannot be generated.  Types in visiting are being
+// generated by an enclosing call, so values of those types are zero.
+func _ForType(t reflect.Type, visiting map[reflect.Type]bool) (*Generator, bool) {
+	if visiting[t] {
+		return _Zero(t), true
+	}
+	visiting[t] = true
+	defer func() { visiting[t] = false }()
+	name := t.String()
+	switch typ := t.(type) {
+	case *reflect.BoolType:
+		return _BoolOf(t, name), true
+	case *reflect.IntType:
+		return _IntOf(t, name), true
+	case *reflect.UintType:
+		return _UintOf(t, name), true
+	case *reflect.FloatType:
+		return _FloatOf(t, name), true
+	case *reflect.ComplexType:
+		return _ComplexOf(t, name), true
+	case *reflect.StringType:
+		return _StringOf(t, name), true
+	case *reflect.SliceType:
+		if visiting[typ.Elem()] {
+			return _Zero(t), true
+		}
+		elem, ok := _ForType(typ.Elem(), visiting)
+		if !ok {
+			return nil, false
+		}
+		return _TypedSliceOf(typ, elem), true
+	case *reflect.ArrayType:
+		elem, ok := _ForType(typ.Elem(), visiting)
+		if !ok {
+			return nil, false
+		}
+		return _ArrayOf(typ, elem), true
+	case *reflect.MapType:
+		if visiting[typ.Key()] || visiting[typ.Elem()] {
+			return _Zero(t), true
+		}
+		key, ok := _ForType(typ.Key(), visiting)
+		if !ok {
+			return nil, false
+		}
+		value, ok := _ForType(typ.Elem(), visiting)
+		if !ok {
+			return nil, false
+		}
+		return _TypedMapOf(typ, key, value), true
+	case *reflect.StructType:
+		return _StructOf(typ, visiting), true
+	case *reflect.PtrType:
+		if visiting[typ.Elem()] {
+			return _Zero(t), true
+		}
+		elem, ok := _ForType(typ.Elem(), visiting)
+		if !ok {
+			return nil, false
+		}
+		return _PtrTo(typ, elem, true), true
+	case *reflect.ChanType, *reflect.FuncType, *reflect.InterfaceType:
+		return _Zero(t), true
+	}
+	return nil, false
+}
+
+// Generates only the zero value of the given type.
+func _Zero(t reflect.Type) *Generator {
+	generate := func(random *rand.Rand) interface{} {
+		return reflect.MakeZero(t).Interface()
+	}
+	shrink := func(value interface{}) []interface{} {
+		return nil
+	}
+	return NewGeneratorf(generate, shrink, "Zero(%v)", t).generating(t)
+}
+
+func _TypedSliceOf(t *reflect.SliceType, elem *Generator) *Generator {
+	generate := func(random *rand.Rand) interface{} {
+		n := random.Intn(MaxLen + 1)
+		slice := reflect.MakeSlice(t, n, n)
+		for i := 0; i < n; i++ {
+			_Set(slice.Elem(i), elem.Generate(random))
+		}
+		return slice.Interface()
+	}
+	shrink := func(value interface{}) []interface{} {
+		return _ShrinkSlice(elem, reflect.NewValue(value).(*reflect.SliceValue))
+	}
+	return NewGeneratorf(generate, shrink, "%v", t).generating(t)
+}
+
+func _ArrayOf(t *reflect.ArrayType, elem *Generator) *Generator {
+	generate := func(random *rand.Rand) interface{} {
+		array := reflect.MakeZero(t).(*reflect.ArrayValue)
+		for i := 0; i < array.Len(); i++ {
+			_Set(array.Elem(i), elem.Generate(random))
+		}
+		return array.Interface()
+	}
+	shrink := func(value interface{}) []interface{} {
+		array := reflect.NewValue(value).(*reflect.ArrayValue)
+		var candidates []interface{}
+		for i := 0; i < array.Len(); i++ {
+			for _, shrunk := range elem.Shrink(array.Elem(i).Interface()) {
+				copied := reflect.MakeZero(t).(*reflect.ArrayValue)
+				copied.SetValue(array)
+				_Set(copied.Elem(i), shrunk)
+				candidates = append(candidates, copied.Interface())
+			}
+		}
+		return candidates
+	}
+	return NewGeneratorf(generate, shrink, "%v", t).generating(t)
+}
+
+func _TypedMapOf(t *reflect.MapType, key, value *Generator) *Generator {
+	generate := func(random *rand.Rand) interface{} {
+		m := reflect.MakeMap(t)
+		for n := random.Intn(MaxLen + 1); n > 0; n-- {
+			m.SetElem(_ValueOf(key.Generate(random), t.Key()),
+				_ValueOf(value.Generate(random), t.Elem()))
+		}
+		return m.Interface()
+	}
+	shrink := func(actual interface{}) []interface{} {
+		return _ShrinkMap(value, reflect.NewValue(actual).(*reflect.MapValue))
+	}
+	return NewGeneratorf(generate, shrink, "%v", t).generating(t)
+}
+
+func _StructOf(t *reflect.StructType, visiting map[reflect.Type]bool) *Generator {
+	fields := make([]*Generator, t.NumField())
+	for i := range fields {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		if generator, ok := _ForType(field.Type, visiting); ok {
+			fields[i] = generator
+		}
+	}
+	generate := func(random *rand.Rand) interface{} {
+		value := reflect.MakeZero(t).(*reflect.StructValue)
+		for i, field := range fields {
+			if field != nil {
+				_Set(value.Field(i), field.Generate(random))
+			}
+		}
+		return value.Interface()
+	}
+	shrink := func(actual interface{}) []interface{} {
+		value := reflect.NewValue(actual).(*reflect.StructValue)
+		var candidates []interface{}
+		for i, field := range fields {
+			if field == nil {
+				continue
+			}
+			for _, shrunk := range field.Shrink(value.Field(i).Interface()) {
+				copied := reflect.MakeZero(t).(*reflect.StructValue)
+				copied.SetValue(value)
+				_Set(copied.Field(i), shrunk)
+				candidates = append(candidates, copied.Interface())
+			}
+		}
+		return candidates
+	}
+	return NewGeneratorf(generate, shrink, "%v", t).generating(t)
+}
+
+// Generates pointers to values generated by elem, and (if allowNil)
+// occasionally nil.  Pointers shrink to nil (if allowed), and then to
+// pointers to shrunk values.
+func _PtrTo(t *reflect.PtrType, elem *Generator, allowNil bool) *Generator {
+	pointTo := func(value interface{}) interface{} {
+		target := reflect.MakeZero(t.Elem())
+		_Set(target, value)
+		ptr := reflect.MakeZero(t).(*reflect.PtrValue)
+		ptr.PointTo(target)
+		return ptr.Interface()
+	}
+	generate := func(random *rand.Rand) interface{} {
+		if allowNil && random.Intn(10) == 0 {
+			return reflect.MakeZero(t).Interface()
+		}
+		return pointTo(elem.Generate(random))
+	}
+	shrink := func(actual interface{}) []interface{} {
+		ptr := reflect.NewValue(actual).(*reflect.PtrValue)
+		if ptr.IsNil() {
+			return nil
+		}
+		var candidates []interface{}
+		if allowNil {
+			candidates = append(candidates, reflect.MakeZero(t).Interface())
+		}
+		for _, shrunk := range elem.Shrink(ptr.Elem().Interface()) {
+			candidates = append(candidates, pointTo(shrunk))
+		}
+		return candidates
+	}
+	return NewGeneratorf(generate, shrink, "%v", t).generating(t)
+}
@@ -0,0 +1,123 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	. "github.com/rdrdr/hamcrest/core"
+	"github.com/rdrdr/hamcrest/property"
+	"github.com/rdrdr/hamcrest/reflect"
+	"github.com/rdrdr/hamcrest/slices"
+	"rand"
+	"testing"
+)
+
+type _Celsius float64
+
+type _Address struct {
+	Street string
+	Zip [5]uint8
+}
+
+type _User struct {
+	ID int
+	Name string
+	Tags []string
+	Scores map[string]int
+	Home *_Address
+	Admin bool
+	Temperature _Celsius
+	Callback func()
+	secret string
+}
+
+type _Node struct {
+	Value int
+	Next *_Node
+	Children []_Node
+}
+
+func Test_ForType_basics(t *testing.T) {
+	we := asserter.Using(t)
+	checkGenerates(t, ForType(int16(0)), reflect.Int16())
+	checkGenerates(t, ForType([]string{}), reflect.SliceOf(reflect.StringType()))
+	checkGenerates(t, ForType(map[int]bool{}), reflect.MapOf(reflect.IntType(), reflect.BoolType()))
+	we.CheckThat(ForType([]string{}).String(), EqualTo("ForType([]string)"))
+	random := rand.New(rand.NewSource(1))
+	_, ok := ForType(_Celsius(0)).Generate(random).(_Celsius)
+	we.CheckTrue(ok, "generates values of named types")
+	we.CheckThat(ForType(0).Shrink(6)[1], EqualTo(3))
+
+	shrunk := ForType([]int{}).Shrink([]int{5, 0})
+	we.AssertThat(shrunk, slices.ToLen(EqualTo(6)))
+	we.CheckThat(shrunk[3], DeepEqualTo([]int{0, 0}))
+
+	we.CheckThat(0, PanicWhenApplying(func(ignored int) { ForType(nil) }, "ForType"))
+	we.CheckThat(make(chan int), Not(PanicWhenApplying(func(c chan int) { ForType(c) }, "ForType")))
+}
+
+func Test_StructOf(t *testing.T) {
+	we := asserter.Using(t)
+	users := StructOf(_User{})
+	we.CheckThat(users.String(), EqualTo("StructOf(gen._User)"))
+	random := rand.New(rand.NewSource(1))
+	homes, names := 0, 0
+	for i := 0; i < 100; i++ {
+		user := users.Generate(random).(_User)
+		we.CheckThat(user.secret, EqualTo("").Comment("unexported fields are not generated"))
+		we.CheckNil(user.Callback)
+		if user.Home != nil {
+			homes++
+		}
+		if user.Name != "" {
+			names++
+		}
+	}
+	we.CheckThat(homes, GreaterThan(50))
+	we.CheckThat(names, GreaterThan(50))
+
+	shrunk := users.Shrink(_User{ID: 4, Admin: true})
+	we.AssertThat(shrunk, slices.ToLen(EqualTo(4)))
+	we.CheckThat(shrunk[0].(_User).ID, EqualTo(0))
+	we.CheckThat(shrunk[0].(_User).Admin, True().Comment("one field at a time"))
+	we.CheckThat(shrunk[3].(_User).ID, EqualTo(4))
+	we.CheckThat(shrunk[3].(_User).Admin, False())
+}
+
+func Test_StructOf_pointer(t *testing.T) {
+	we := asserter.Using(t)
+	addresses := StructOf(&_Address{})
+	checkGenerates(t, addresses, NonNil())
+	address := &_Address{Street: "Main"}
+	shrunk := addresses.Shrink(address)
+	we.AssertThat(shrunk, slices.ToLen(EqualTo(3)))
+	we.CheckThat(shrunk[0].(*_Address).Street, EqualTo(""))
+	we.CheckThat(address.Street, EqualTo("Main").Comment("original unchanged"))
+
+	we.CheckThat(0, PanicWhenApplying(func(ignored int) { StructOf(5) }, "StructOf"))
+}
+
+func Test_StructOf_recursive(t *testing.T) {
+	we := asserter.Using(t)
+	nodes := StructOf(_Node{})
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		node := nodes.Generate(random).(_Node)
+		we.CheckNil(node.Next, "recursive pointers are nil")
+		we.CheckThat(len(node.Children), EqualTo(0).Comment("recursive slices are empty"))
+	}
+}
+
+func Test_StructOf_shrinksToMinimalCounterexample(t *testing.T) {
+	we := asserter.Using(t)
+	hasSmallID := reflect.HasField("ID", LessThan(10))
+	result := property.Holds().Match(property.ForAll(StructOf(_User{}), hasSmallID).WithSeed(1))
+	we.AssertThat(result, DidNotMatch)
+	minimal := result.Causes()[0].Value().(_User)
+	we.CheckThat(minimal.ID, EqualTo(10))
+	we.CheckThat(minimal.Name, EqualTo(""))
+	we.CheckThat(minimal.Tags, slices.Empty())
+	we.CheckNil(minimal.Home)
+}
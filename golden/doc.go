@@ -0,0 +1,12 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+	Provides Matchers that compare values against "golden" snapshot
+	files stored under testdata/, reporting unified diffs on mismatch.
+	
+	Run tests with -update (or with HAMCREST_UPDATE_GOLDEN set) to
+	create or rewrite the snapshots.
+*/
+package golden
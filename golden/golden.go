@@ -0,0 +1,184 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golden
+
+import (
+	"flag"
+	"fmt"
+	"github.com/rdrdr/hamcrest/base"
+	"github.com/rdrdr/hamcrest/diff"
+	hamstrings "github.com/rdrdr/hamcrest/strings"
+	"io/ioutil"
+	"json"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// Name of the command-line flag that, when true, causes snapshot
+// matchers to rewrite their snapshot files instead of comparing.
+const UpdateFlag = "update"
+
+// Name of the environment variable that, when non-empty, causes
+// snapshot matchers to rewrite their snapshot files instead of
+// comparing.
+const UpdateEnv = "HAMCREST_UPDATE_GOLDEN"
+
+// Directory (relative to the package under test) that holds snapshots.
+const SnapshotDir = "testdata"
+
+// Number of unchanged lines shown around each change in a diff.
+const DiffContext = 3
+
+func init() {
+	// Don't clobber a flag that the test binary defined for itself.
+	if flag.Lookup(UpdateFlag) == nil {
+		flag.Bool(UpdateFlag, false, "rewrite golden snapshot files")
+	}
+}
+
+// Returns true if snapshots should be rewritten rather than compared.
+func Updating() bool {
+	if os.Getenv(UpdateEnv) != "" {
+		return true
+	}
+	if f := flag.Lookup(UpdateFlag); f != nil {
+		return f.Value.String() == "true"
+	}
+	return false
+}
+
+// Converts a value to the text stored in a snapshot file.
+type Renderer func(value interface{}) (string, os.Error)
+
+// Renders strings and byte slices as-is, and any other value as the
+// hamcrest strings.ToString matcher does (using its String() method,
+// if it has one, and otherwise fmt's %v).
+func String(value interface{}) (string, os.Error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	}
+	var rendered string
+	capture := base.NewMatcherf(func(s string) bool {
+		rendered = s
+		return true
+	}, "Rendered")
+	hamstrings.ToString(capture).Match(value)
+	return rendered, nil
+}
+
+// Renders values using fmt.Sprintf("%#v", value).
+func GoString(value interface{}) (string, os.Error) {
+	return fmt.Sprintf("%#v", value), nil
+}
+
+// Renders values as indented JSON (using the json package).
+func JSON(value interface{}) (string, os.Error) {
+	bytes, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(bytes) + "\n", nil
+}
+
+// Returns a matcher that compares the String rendering of its input
+// against the snapshot testdata/<test>/<name>.golden, where <test> is
+// the name of the Test function that called MatchesSnapshot.
+//
+// If the -update flag or the HAMCREST_UPDATE_GOLDEN environment
+// variable is set, the snapshot is (re)written and the matcher
+// matches.  Otherwise, on a mismatch, the Result's cause is a unified
+// diff from the snapshot to the actual rendering.
+func MatchesSnapshot(name string) *base.Matcher {
+	return MatchesSnapshotFile(SnapshotPath(_TestName(), name), String)
+}
+
+// Variant of MatchesSnapshot that renders its input as GoString does.
+func MatchesGoSnapshot(name string) *base.Matcher {
+	return MatchesSnapshotFile(SnapshotPath(_TestName(), name), GoString)
+}
+
+// Variant of MatchesSnapshot that renders its input as JSON does.
+func MatchesJSONSnapshot(name string) *base.Matcher {
+	return MatchesSnapshotFile(SnapshotPath(_TestName(), name), JSON)
+}
+
+// Returns the path of the snapshot with the given name for the
+// given test.
+func SnapshotPath(test, name string) string {
+	return path.Join(SnapshotDir, test, name + ".golden")
+}
+
+// Returns a matcher that renders its input using the given Renderer
+// and compares the rendering to the contents of the given file, as
+// described for MatchesSnapshot.
+func MatchesSnapshotFile(filename string, render Renderer) *base.Matcher {
+	match := func(actual interface{}) *base.Result {
+		rendered, err := render(actual)
+		if err != nil {
+			return base.NewErrorResultf(
+				"Could not render %T for snapshot %v: %v", actual, filename, err)
+		}
+		if Updating() {
+			if err := _Write(filename, rendered); err != nil {
+				return base.NewErrorResultf(
+					"Could not update snapshot %v: %v", filename, err)
+			}
+			return base.NewResultf(true, "updated snapshot %v", filename)
+		}
+		bytes, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return base.NewErrorResultf(
+				"Could not read snapshot %v (run with -%v to create it): %v",
+				filename, UpdateFlag, err)
+		}
+		expected := string(bytes)
+		if rendered == expected {
+			return base.NewResultf(true, "matched snapshot %v", filename)
+		}
+		unified := diff.Unified(filename, "actual", expected, rendered, DiffContext)
+		if unified == "" {
+			unified = "(snapshot and actual differ only in their final newline)"
+		}
+		return base.NewResultf(false,
+			"did not match snapshot %v (run with -%v to update it)",
+			filename, UpdateFlag).
+			WithCauses(base.NewResultf(false, "%v", unified))
+	}
+	return base.NewMatcherf(match, "MatchesSnapshot[%v]", filename).
+		WithStructure("MatchesSnapshotFile", []interface{}{filename, render})
+}
+
+func _Write(filename, contents string) os.Error {
+	if err := os.MkdirAll(path.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, []byte(contents), 0644)
+}
+
+// Returns the name of the innermost Test function on the stack of the
+// caller, or "default" if there is none.
+func _TestName() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	for _, pc := range pcs[:n] {
+		fn := runtime.FuncForPC(pc)
+		if fn == nil {
+			continue
+		}
+		name := fn.Name()
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			name = name[dot+1:]
+		}
+		if strings.HasPrefix(name, "Test") {
+			return name
+		}
+	}
+	return "default"
+}
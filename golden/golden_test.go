@@ -0,0 +1,100 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golden
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	. "github.com/rdrdr/hamcrest/core"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+var Matched = base.Matched()
+var DidNotMatch = base.DidNotMatch()
+var Errored = base.Errored()
+
+func Test_MatchesSnapshot(t *testing.T) {
+	if Updating() {
+		return
+	}
+	we := asserter.Using(t)
+	we.CheckThat(MatchesSnapshot("greeting").Match("hello\nworld\n"), Matched)
+	we.CheckThat(MatchesSnapshot("greeting").Match([]byte("hello\nworld\n")), Matched)
+	
+	result := MatchesSnapshot("greeting").Match("hello\nthere\n")
+	we.CheckThat(result, DidNotMatch)
+	we.CheckThat(len(result.Causes()), EqualTo(1).Comment("diff cause"))
+	we.CheckThat(MatchesSnapshot("no-such-snapshot").Match("hello"), Errored)
+}
+
+func Test_SnapshotPath(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(SnapshotPath("Test_X", "y"), EqualTo("testdata/Test_X/y.golden"))
+	we.CheckThat(_TestName(), EqualTo("Test_SnapshotPath"))
+}
+
+func Test_MatchesSnapshotFile_updates(t *testing.T) {
+	if Updating() {
+		return
+	}
+	defer os.Setenv(UpdateEnv, os.Getenv(UpdateEnv))
+	we := asserter.Using(t)
+	dir, err := ioutil.TempDir("", "golden")
+	we.AssertNil(err)
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "nested", "value.golden")
+	matcher := MatchesSnapshotFile(filename, GoString)
+	
+	we.CheckThat(matcher.Match([]int{1, 2}), Errored.Comment("no snapshot yet"))
+	os.Setenv(UpdateEnv, "true")
+	we.CheckThat(matcher.Match([]int{1, 2}), Matched.Comment("writes snapshot"))
+	os.Setenv(UpdateEnv, "")
+	we.CheckThat(matcher.Match([]int{1, 2}), Matched)
+	we.CheckThat(matcher.Match([]int{1, 3}), DidNotMatch)
+}
+
+type _Named struct {
+	name string
+}
+
+func (self *_Named) String() string {
+	return "named " + self.name
+}
+
+func Test_String(t *testing.T) {
+	we := asserter.Using(t)
+	for value, expected := range map[interface{}]string{
+			"text": "text", 42: "42", &_Named{"x"}: "named x"} {
+		rendered, err := String(value)
+		we.CheckNil(err)
+		we.CheckThat(rendered, EqualTo(expected))
+	}
+	rendered, _ := String([]byte("bytes"))
+	we.CheckThat(rendered, EqualTo("bytes"))
+}
+
+func Test_JSON(t *testing.T) {
+	we := asserter.Using(t)
+	rendered, err := JSON(map[string]int{"a": 1})
+	we.CheckNil(err)
+	we.CheckThat(rendered, EqualTo("{\n  \"a\": 1\n}\n"))
+}
+
+func Test_structure(t *testing.T) {
+	we := asserter.Using(t)
+	matcher := Not(MatchesSnapshotFile("testdata/x.golden", JSON))
+	var names []string
+	matcher.Inspect(func(m *base.Matcher) bool {
+		names = append(names, m.Name())
+		return true
+	})
+	we.CheckThat(names, DeepEqualTo([]string{"Not", "MatchesSnapshotFile"}))
+	we.CheckThat(MatchesSnapshotFile("testdata/x.golden", JSON).Params()[0],
+		EqualTo("testdata/x.golden"))
+	we.CheckThat(MatchesSnapshot("x").Name(), EqualTo("MatchesSnapshotFile"))
+}
@@ -0,0 +1,12 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+	Provides Matchers on HTTP responses, given as an *http.Response
+	or the *httptest.ResponseRecorder used to test a handler:
+	
+		we.CheckThat(recorder, HasStatus(EqualTo(http.StatusCreated)))
+		we.CheckThat(recorder, HasJSONBody(json.AtJSONPath("$.id", EqualTo(7.0))))
+*/
+package http
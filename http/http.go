@@ -0,0 +1,227 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"github.com/rdrdr/hamcrest/base"
+	"http"
+	"http/httptest"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// The parts of a response that matchers inspect, whether it came from
+// an *http.Response or an *httptest.ResponseRecorder.
+type _Response struct {
+	status int
+	header http.Header
+	body func() ([]byte, os.Error)
+}
+
+// Adapts an *http.Response or *httptest.ResponseRecorder, returning
+// ok=false for inputs of any other type.
+//
+// The body of an *http.Response is read (and closed) the first time it
+// is needed, and replaced with a buffered copy, so that any number of
+// matchers can read it.
+func _Adapt(actual interface{}) (response *_Response, ok bool) {
+	switch r := actual.(type) {
+	case *http.Response:
+		body := func() ([]byte, os.Error) {
+			if r.Body == nil {
+				return []byte{}, nil
+			}
+			data, err := ioutil.ReadAll(r.Body)
+			r.Body.Close()
+			r.Body = ioutil.NopCloser(bytes.NewBuffer(data))
+			return data, err
+		}
+		return &_Response{status: r.StatusCode, header: r.Header, body: body}, true
+	case *httptest.ResponseRecorder:
+		status := r.Code
+		if status == 0 {
+			status = http.StatusOK // as when a handler writes no header
+		}
+		body := func() ([]byte, os.Error) {
+			if r.Body == nil {
+				return []byte{}, nil
+			}
+			return r.Body.Bytes(), nil
+		}
+		return &_Response{status: status, header: r.HeaderMap, body: body}, true
+	}
+	return nil, false
+}
+
+// Returns a matcher whose match function adapts its input (see _Adapt)
+// and passes the response to the given function.  Inputs of other
+// types cannot be matched at all.  The matcher has the given structured
+// description (see base.Matcher.WithStructure), with the given child
+// matcher, if it is not nil.
+func _NewResponseMatcher(f func(response *_Response) *base.Result,
+		name string, params []interface{}, child *base.Matcher,
+		format string, args...interface{}) *base.Matcher {
+	match := func(actual interface{}) *base.Result {
+		response, ok := _Adapt(actual)
+		if !ok {
+			return base.NewErrorResultf(
+				"Could not apply to %T: expected *http.Response or *httptest.ResponseRecorder",
+				actual)
+		}
+		return f(response)
+	}
+	return base.NewMatcherf(match, format, args...).
+		WithStructure(name, params, _Children(child)...)
+}
+
+// Returns the given matcher as a list of children, or none if it is
+// nil.
+func _Children(child *base.Matcher) []*base.Matcher {
+	if child == nil {
+		return nil
+	}
+	return []*base.Matcher{child}
+}
+
+// Applies the given matcher to the status code (an int) of the input
+// response.  For example:
+//    HasStatus(EqualTo(http.StatusCreated))
+func HasStatus(matcher *base.Matcher) *base.Matcher {
+	return _NewResponseMatcher(func(response *_Response) *base.Result {
+		result := matcher.Match(response.status)
+		return base.NewResultf(result.Matched(),
+			"status was %v %v", response.status, http.StatusText(response.status)).
+			WithError(result.Err()).
+			WithCauses(result)
+	}, "HasStatus", nil, matcher, "HasStatus(%v)", matcher)
+}
+
+// Applies the given matcher to the (first) value of the named header of
+// the input response.  If the response has no such header, the matcher
+// fails to match.
+func HasHeader(name string, matcher *base.Matcher) *base.Matcher {
+	return _NewResponseMatcher(func(response *_Response) *base.Result {
+		values, ok := response.header[http.CanonicalHeaderKey(name)]
+		if !ok || len(values) == 0 {
+			return base.NewResultf(false, "no %v header", name)
+		}
+		result := matcher.Match(values[0])
+		return base.NewResultf(result.Matched(),
+			"%v header was %q", name, values[0]).
+			WithError(result.Err()).
+			WithCauses(result)
+	}, "HasHeader", []interface{}{name}, matcher, "HasHeader[%q](%v)", name, matcher)
+}
+
+// Matches responses whose Content-Type header has the given media
+// type, ignoring case and any parameters.  For example,
+// HasContentType("text/html") matches "text/html; charset=utf-8".
+func HasContentType(mediaType string) *base.Matcher {
+	return _NewResponseMatcher(func(response *_Response) *base.Result {
+		contentType := response.header.Get("Content-Type")
+		if contentType == "" {
+			return base.NewResultf(false, "no Content-Type header")
+		}
+		actual := _MediaType(contentType)
+		return base.NewResultf(actual == strings.ToLower(mediaType),
+			"Content-Type was %q", contentType)
+	}, "HasContentType", []interface{}{mediaType}, nil, "HasContentType[%q]", mediaType)
+}
+
+// Returns the media type of a Content-Type header value, in lower case
+// and without parameters.
+func _MediaType(contentType string) string {
+	if semicolon := strings.Index(contentType, ";"); semicolon >= 0 {
+		contentType = contentType[:semicolon]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// Applies the given matcher to the body (as a string) of the input
+// response.  The body of an *http.Response is buffered, so it can
+// still be read afterwards (by other matchers, for example).
+func HasBody(matcher *base.Matcher) *base.Matcher {
+	return _NewResponseMatcher(func(response *_Response) *base.Result {
+		body, err := response.body()
+		if err != nil {
+			return base.NewErrorResultf("Could not read body: %v", err)
+		}
+		result := matcher.Match(string(body))
+		return base.NewResultf(result.Matched(),
+			"body was %v bytes", len(body)).
+			WithError(result.Err()).
+			WithCauses(result)
+	}, "HasBody", nil, matcher, "HasBody(%v)", matcher)
+}
+
+// Matches responses with a JSON Content-Type (application/json, or any
+// media type ending in "+json") whose body (as a string) is matched by
+// the given matcher, typically one from the hamcrest json package:
+//    HasJSONBody(json.AtJSONPath("$.id", EqualTo(7.0)))
+func HasJSONBody(matcher *base.Matcher) *base.Matcher {
+	return _NewResponseMatcher(func(response *_Response) *base.Result {
+		mediaType := _MediaType(response.header.Get("Content-Type"))
+		if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+			return base.NewResultf(false,
+				"Content-Type was %q, not JSON", response.header.Get("Content-Type"))
+		}
+		body, err := response.body()
+		if err != nil {
+			return base.NewErrorResultf("Could not read body: %v", err)
+		}
+		result := matcher.Match(string(body))
+		return base.NewResultf(result.Matched(),
+			"%v body was %v bytes", mediaType, len(body)).
+			WithError(result.Err()).
+			WithCauses(result)
+	}, "HasJSONBody", nil, matcher, "HasJSONBody(%v)", matcher)
+}
+
+// Applies the given matcher to the value of the named cookie set by
+// the input response (in a Set-Cookie header).  If the response does
+// not set that cookie, the matcher fails to match.
+func HasCookie(name string, matcher *base.Matcher) *base.Matcher {
+	return _NewResponseMatcher(func(response *_Response) *base.Result {
+		cookies := (&http.Response{Header: response.header}).Cookies()
+		names := make([]string, len(cookies))
+		for i, cookie := range cookies {
+			if cookie.Name == name {
+				result := matcher.Match(cookie.Value)
+				return base.NewResultf(result.Matched(),
+					"cookie %v was %q", name, cookie.Value).
+					WithError(result.Err()).
+					WithCauses(result)
+			}
+			names[i] = cookie.Name
+		}
+		return base.NewResultf(false, "no cookie %v among %q", name, names)
+	}, "HasCookie", []interface{}{name}, matcher, "HasCookie[%q](%v)", name, matcher)
+}
+
+// Matches redirect responses (with a 3xx status code) whose Location
+// header is matched by the given matcher.  For example:
+//    IsRedirectTo(HasPrefix("/login"))
+func IsRedirectTo(matcher *base.Matcher) *base.Matcher {
+	return _NewResponseMatcher(func(response *_Response) *base.Result {
+		if response.status < 300 || 400 <= response.status {
+			return base.NewResultf(false,
+				"status was %v %v, not a redirect",
+				response.status, http.StatusText(response.status))
+		}
+		location := response.header.Get("Location")
+		if location == "" {
+			return base.NewResultf(false,
+				"status was %v %v, but there was no Location header",
+				response.status, http.StatusText(response.status))
+		}
+		result := matcher.Match(location)
+		return base.NewResultf(result.Matched(),
+			"redirected (%v) to %q", response.status, location).
+			WithError(result.Err()).
+			WithCauses(result)
+	}, "IsRedirectTo", nil, matcher, "IsRedirectTo(%v)", matcher)
+}
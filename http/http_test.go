@@ -0,0 +1,129 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	. "github.com/rdrdr/hamcrest/core"
+	"github.com/rdrdr/hamcrest/json"
+	"github.com/rdrdr/hamcrest/strings"
+	"http"
+	"http/httptest"
+	"io/ioutil"
+	"testing"
+)
+
+var Matched = base.Matched()
+var DidNotMatch = base.DidNotMatch()
+var Errored = base.Errored()
+
+func _Recorded(handler func(w http.ResponseWriter, r *http.Request)) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "http://example.com/old", nil)
+	handler(recorder, request)
+	return recorder
+}
+
+func _JSONHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123"})
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(`{"id": 7}`))
+}
+
+func Test_HasStatus(t *testing.T) {
+	we := asserter.Using(t)
+	recorder := _Recorded(_JSONHandler)
+	we.CheckThat(recorder, HasStatus(EqualTo(http.StatusCreated)))
+	we.CheckThat(HasStatus(EqualTo(http.StatusOK)).Match(recorder), DidNotMatch)
+	we.CheckThat(_Recorded(func(w http.ResponseWriter, r *http.Request) {}),
+		HasStatus(EqualTo(http.StatusOK)).Comment("implicit status"))
+	we.CheckThat(HasStatus(Anything()).Match("200"), Errored)
+}
+
+func Test_HasHeader(t *testing.T) {
+	we := asserter.Using(t)
+	recorder := _Recorded(_JSONHandler)
+	we.CheckThat(recorder, HasHeader("content-type", strings.HasPrefix("application/json")))
+	we.CheckThat(HasHeader("X-Missing", Anything()).Match(recorder), DidNotMatch)
+}
+
+func Test_HasContentType(t *testing.T) {
+	we := asserter.Using(t)
+	recorder := _Recorded(_JSONHandler)
+	we.CheckThat(recorder, HasContentType("application/json"))
+	we.CheckThat(recorder, HasContentType("Application/JSON"))
+	we.CheckThat(HasContentType("text/html").Match(recorder), DidNotMatch)
+}
+
+func Test_HasBody(t *testing.T) {
+	we := asserter.Using(t)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{},
+		Body: ioutil.NopCloser(bytes.NewBufferString("hello, world")),
+	}
+	we.CheckThat(response, HasBody(strings.HasPrefix("hello")))
+	we.CheckThat(response, HasBody(strings.HasSuffix("world")).
+		Comment("body can be read more than once"))
+	data, _ := ioutil.ReadAll(response.Body)
+	we.CheckThat(string(data), EqualTo("hello, world").
+		Comment("body is still readable afterwards"))
+	we.CheckThat(_Recorded(_JSONHandler), HasBody(strings.Contains(`"id"`)))
+}
+
+func Test_HasJSONBody(t *testing.T) {
+	we := asserter.Using(t)
+	recorder := _Recorded(_JSONHandler)
+	we.CheckThat(recorder, HasJSONBody(json.AtJSONPath("$.id", EqualTo(7.0))))
+	we.CheckThat(HasJSONBody(json.HasJSONKey("name")).Match(recorder), DidNotMatch)
+	text := _Recorded(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(`{"id": 7}`))
+	})
+	we.CheckThat(HasJSONBody(Anything()).Match(text), DidNotMatch.
+		Comment("not a JSON content type"))
+}
+
+func Test_HasCookie(t *testing.T) {
+	we := asserter.Using(t)
+	recorder := _Recorded(_JSONHandler)
+	we.CheckThat(recorder, HasCookie("session", EqualTo("abc123")))
+	we.CheckThat(HasCookie("session", EqualTo("xyz")).Match(recorder), DidNotMatch)
+	we.CheckThat(HasCookie("other", Anything()).Match(recorder), DidNotMatch)
+}
+
+func Test_IsRedirectTo(t *testing.T) {
+	we := asserter.Using(t)
+	redirect := _Recorded(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login?next=/old", http.StatusFound)
+	})
+	we.CheckThat(redirect, IsRedirectTo(strings.HasPrefix("/login")))
+	we.CheckThat(IsRedirectTo(strings.HasPrefix("/home")).Match(redirect), DidNotMatch)
+	we.CheckThat(IsRedirectTo(Anything()).Match(_Recorded(_JSONHandler)), DidNotMatch)
+}
+
+func Test_structure(t *testing.T) {
+	we := asserter.Using(t)
+	ok := EqualTo(http.StatusOK)
+	matcher := AllOf(HasStatus(ok), HasHeader("X-Id", Anything()),
+		HasContentType("text/html"), HasBody(Anything()), HasJSONBody(Anything()),
+		HasCookie("session", Anything()), IsRedirectTo(Anything()))
+	var names []string
+	matcher.Inspect(func(m *base.Matcher) bool {
+		names = append(names, m.Name())
+		return true
+	})
+	we.CheckThat(names, DeepEqualTo([]string{"AllOf",
+		"HasStatus", "EqualTo", "HasHeader", "Anything", "HasContentType",
+		"HasBody", "Anything", "HasJSONBody", "Anything",
+		"HasCookie", "Anything", "IsRedirectTo", "Anything"}))
+	we.CheckThat(HasStatus(ok).Children()[0] == ok, Is(True()))
+	we.CheckThat(HasHeader("X-Id", ok).Params(), DeepEqualTo([]interface{}{"X-Id"}))
+	we.CheckThat(HasContentType("text/html").Params(), DeepEqualTo([]interface{}{"text/html"}))
+	we.CheckThat(HasCookie("session", ok).Params(), DeepEqualTo([]interface{}{"session"}))
+}
@@ -0,0 +1,21 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+	Provides a local HTTP server for testing clients, which answers
+	requests with stubbed responses chosen by Matchers:
+	
+		server := httpstub.NewServer()
+		defer server.Close()
+		server.When("GET", EqualTo("/users/7")).Respond(200, `{"id": 7}`)
+		... exercise a client with server.URL ...
+		server.VerifyAllCalled(we)
+	
+	Further matchers passed to When are conditions on the request
+	header (see HasHeader) and, last, on the request body.
+	
+	Requests that match no stub get a 404 (Not Found) response that
+	explains why the closest stub did not match.
+*/
+package httpstub
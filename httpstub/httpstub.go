@@ -0,0 +1,269 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpstub
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	"http"
+	"http/httptest"
+	"io/ioutil"
+	"strings"
+	"sync"
+)
+
+// A request received by a Server.
+type Request struct {
+	Method string
+	Path string
+	Query string
+	Header http.Header
+	Body string
+}
+
+// Implements fmt.Stringer.
+func (self *Request) String() string {
+	if self.Query != "" {
+		return fmt.Sprintf("%v %v?%v", self.Method, self.Path, self.Query)
+	}
+	return fmt.Sprintf("%v %v", self.Method, self.Path)
+}
+
+// A local HTTP server that answers requests with the responses of
+// the first matching Stub, and records every request it receives.
+// Its URL field gives the base URL at which it is listening.
+type Server struct {
+	*httptest.Server
+	lock sync.Mutex
+	stubs []*Stub
+	requests []*Request
+}
+
+// Starts a new Server with no stubs.  Close it when the test is done:
+//    server := httpstub.NewServer()
+//    defer server.Close()
+//    server.When("GET", EqualTo("/users/7")).Respond(200, `{"id": 7}`)
+//    ... exercise a client with server.URL ...
+//    server.VerifyAllCalled(we)
+func NewServer() *Server {
+	server := &Server{}
+	server.Server = httptest.NewServer(server)
+	return server
+}
+
+// Adds a stub for requests with the given method (such as "GET") whose
+// path is matched by the given matcher, and whose response can be set
+// with Respond().  Any further matchers are conditions on the request:
+// the last is applied to the request body (as a string), and any
+// before it to the request header (an http.Header, as with HasHeader).
+// For example:
+//    server.When("POST", EqualTo("/users"),
+//        HasHeader("Content-Type", EqualTo("application/json")),
+//        strings.Contains(`"name"`))
+// Stubs are tried in the order in which they were added.
+func (self *Server) When(method string, pathMatcher *base.Matcher, matchers...*base.Matcher) *Stub {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	stub := &Stub{server: self, status: http.StatusOK, header: make(http.Header)}
+	stub.conditions = append(stub.conditions,
+		_NewCondition("method", base.EqualTo(strings.ToUpper(method)),
+			func(r *Request) interface{} { return r.Method }),
+		_NewCondition("path", pathMatcher,
+			func(r *Request) interface{} { return r.Path }))
+	if n := len(matchers); n > 0 {
+		for _, headerMatcher := range matchers[:n-1] {
+			stub.conditions = append(stub.conditions, _NewCondition("header", headerMatcher,
+				func(r *Request) interface{} { return r.Header }))
+		}
+		stub.conditions = append(stub.conditions, _NewCondition("body", matchers[n-1],
+			func(r *Request) interface{} { return r.Body }))
+	}
+	self.stubs = append(self.stubs, stub)
+	return stub
+}
+
+// Returns every request received so far, in the order received.
+func (self *Server) Requests() []*Request {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	requests := make([]*Request, len(self.requests))
+	copy(requests, self.requests)
+	return requests
+}
+
+// Checks (using the given Asserter) that every stub has been called
+// at least once.
+func (self *Server) VerifyAllCalled(we asserter.Asserter) {
+	self.lock.Lock()
+	stubs := make([]*Stub, len(self.stubs))
+	copy(stubs, self.stubs)
+	self.lock.Unlock()
+	for _, stub := range stubs {
+		we.CheckThat(stub, WasCalled())
+	}
+}
+
+// Implements http.Handler:  records the request and writes the
+// response of the first matching stub.  If no stub matches, writes a
+// 404 (Not Found) response explaining why the closest stub (the one
+// with the most matching conditions) did not match.
+func (self *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	request := &Request{
+		Method: r.Method,
+		Path: r.URL.Path,
+		Query: r.URL.RawQuery,
+		Header: r.Header,
+		Body: string(body),
+	}
+	self.lock.Lock()
+	self.requests = append(self.requests, request)
+	var closest *Stub
+	var closestResult *base.Result
+	closestMatched := -1
+	for _, stub := range self.stubs {
+		result, matched := stub.match(request)
+		if result.Matched() {
+			stub.calls++
+			status, header, body := stub.response()
+			self.lock.Unlock()
+			for name, values := range header {
+				for _, value := range values {
+					w.Header().Add(name, value)
+				}
+			}
+			w.WriteHeader(status)
+			fmt.Fprint(w, body)
+			return
+		}
+		if matched > closestMatched {
+			closest, closestResult, closestMatched = stub, result, matched
+		}
+	}
+	self.lock.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+	if closest == nil {
+		fmt.Fprintf(w, "No stub matched %v: no stubs were added\n", request)
+		return
+	}
+	fmt.Fprintf(w, "No stub matched %v; the closest was %v\n", request, closest)
+	asserter.UsingWriter(w).LogResult(closestResult)
+}
+
+// Conditions on a request (its method, path, header and body), and the
+// response to send to matching requests.
+type Stub struct {
+	server *Server
+	conditions []*_Condition
+	status int
+	header http.Header
+	body string
+	calls int
+}
+
+type _Condition struct {
+	name string
+	matcher *base.Matcher
+	extract func(r *Request) interface{}
+}
+
+// Creates a condition that applies the matcher to the named part of a
+// request (which is noted in a comment on the matcher, so that it
+// appears in explanations of near misses).
+func _NewCondition(name string, matcher *base.Matcher, extract func(r *Request) interface{}) *_Condition {
+	return &_Condition{name, matcher.Comment("request " + name), extract}
+}
+
+// Sets the status code and body of the response to matching requests.
+// (Without a call to Respond, a stub responds 200 OK with no body.)
+func (self *Stub) Respond(status int, body string) *Stub {
+	self.server.lock.Lock()
+	defer self.server.lock.Unlock()
+	self.status, self.body = status, body
+	return self
+}
+
+// Adds a header to the response to matching requests.
+func (self *Stub) RespondWithHeader(name, value string) *Stub {
+	self.server.lock.Lock()
+	defer self.server.lock.Unlock()
+	self.header.Add(name, value)
+	return self
+}
+
+// Returns the number of requests that this stub has answered.
+func (self *Stub) Calls() int {
+	self.server.lock.Lock()
+	defer self.server.lock.Unlock()
+	return self.calls
+}
+
+// Implements fmt.Stringer.
+func (self *Stub) String() string {
+	parts := make([]string, len(self.conditions))
+	for i, condition := range self.conditions {
+		parts[i] = fmt.Sprintf("%v %v", condition.name, condition.matcher)
+	}
+	return "Stub[" + strings.Join(parts, ", ") + "]"
+}
+
+// Applies every condition to the request, returning a Result (with a
+// cause for each condition) and the number of conditions that matched.
+func (self *Stub) match(request *Request) (result *base.Result, matched int) {
+	match := func(request *Request) *base.Result {
+		causes := make([]*base.Result, len(self.conditions))
+		for i, condition := range self.conditions {
+			causes[i] = condition.matcher.Match(condition.extract(request))
+			if causes[i].Matched() {
+				matched++
+			}
+		}
+		return base.NewResultf(matched == len(self.conditions),
+			"%v of %v conditions matched", matched, len(self.conditions)).
+			WithCauses(causes...)
+	}
+	result = base.NewMatcherf(match, "%v", self).Match(request)
+	return result, matched
+}
+
+// Returns a copy of the response to matching requests, so that it can
+// be written without holding the server's lock.  The caller must hold
+// the lock.
+func (self *Stub) response() (status int, header http.Header, body string) {
+	header = make(http.Header)
+	for name, values := range self.header {
+		for _, value := range values {
+			header.Add(name, value)
+		}
+	}
+	return self.status, header, self.body
+}
+
+// Returns a matcher for the http.Header of a request, for use with
+// Server.When, that applies the given matcher to the named header (its
+// first value, or "" if there is none).
+func HasHeader(name string, matcher *base.Matcher) *base.Matcher {
+	match := func(header http.Header) *base.Result {
+		value := header.Get(name)
+		result := matcher.Match(value)
+		return base.NewResultf(result.Matched(), "header %v was %q", name, value).
+			WithError(result.Err()).
+			WithCauses(result)
+	}
+	return base.NewMatcherf(match, "HasHeader[%v](%v)", name, matcher).
+		WithStructure("HasHeader", []interface{}{name}, matcher)
+}
+
+// Matches stubs that have answered at least one request.
+func WasCalled() *base.Matcher {
+	match := func(stub *Stub) *base.Result {
+		return base.NewResultf(stub.Calls() > 0, "%v was called %v times", stub, stub.Calls())
+	}
+	return base.NewMatcherf(match, "WasCalled").
+		WithStructure("WasCalled", nil)
+}
@@ -0,0 +1,139 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpstub
+
+import (
+	"bytes"
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	. "github.com/rdrdr/hamcrest/core"
+	"github.com/rdrdr/hamcrest/strings"
+	"http"
+	"http/httptest"
+	"io/ioutil"
+	"testing"
+)
+
+var Matched = base.Matched()
+var DidNotMatch = base.DidNotMatch()
+
+func _Serve(server *Server, method, url, body string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func Test_When_Respond(t *testing.T) {
+	we := asserter.Using(t)
+	server := NewServer()
+	defer server.Close()
+	server.When("GET", EqualTo("/users/7")).
+		Respond(http.StatusOK, `{"id": 7}`).
+		RespondWithHeader("Content-Type", "application/json")
+	server.When("post", strings.HasPrefix("/users"),
+		HasHeader("Content-Type", EqualTo("application/json")),
+		strings.Contains(`"name"`)).
+		Respond(http.StatusCreated, "")
+	server.When("PUT", EqualTo("/users/7"), strings.Contains(`"name"`)).
+		Respond(http.StatusNoContent, "")
+
+	recorder := _Serve(server, "GET", "http://example.com/users/7", "")
+	we.CheckThat(recorder.Code, EqualTo(http.StatusOK))
+	we.CheckThat(recorder.Body.String(), EqualTo(`{"id": 7}`))
+	we.CheckThat(recorder.HeaderMap.Get("Content-Type"), EqualTo("application/json"))
+
+	request, _ := http.NewRequest("POST", "http://example.com/users",
+		bytes.NewBufferString(`{"name": "Ann"}`))
+	request.Header.Set("Content-Type", "application/json")
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	we.CheckThat(recorder.Code, EqualTo(http.StatusCreated))
+
+	recorder = _Serve(server, "PUT", "http://example.com/users/7", `{"name": "Bo"}`)
+	we.CheckThat(recorder.Code, EqualTo(http.StatusNoContent).
+		Comment("a single extra matcher is applied to the body"))
+	recorder = _Serve(server, "POST", "http://example.com/users", `{"name": "Bo"}`)
+	we.CheckThat(recorder.Code, EqualTo(http.StatusNotFound).
+		Comment("no Content-Type header"))
+
+	requests := server.Requests()
+	we.AssertThat(len(requests), EqualTo(4))
+	we.CheckThat(requests[1].Method, EqualTo("POST"))
+	we.CheckThat(requests[1].Body, EqualTo(`{"name": "Ann"}`))
+}
+
+func Test_closestMiss(t *testing.T) {
+	we := asserter.Using(t)
+	server := NewServer()
+	defer server.Close()
+	server.When("GET", EqualTo("/orders")).Respond(http.StatusOK, "orders")
+	server.When("GET", EqualTo("/users"),
+		HasHeader("Accept", EqualTo("text/plain")), Anything()).
+		Respond(http.StatusOK, "users")
+
+	recorder := _Serve(server, "GET", "http://example.com/users?active=false", "")
+	we.CheckThat(recorder.Code, EqualTo(http.StatusNotFound))
+	we.CheckThat(recorder.Body.String(), strings.HasPrefix(
+		`No stub matched GET /users?active=false; the closest was Stub[method EqualTo(GET), path EqualTo(/users)`))
+	we.CheckThat(recorder.Body.String(), strings.Contains("request header"))
+
+	empty := NewServer()
+	defer empty.Close()
+	recorder = _Serve(empty, "GET", "http://example.com/", "")
+	we.CheckThat(recorder.Code, EqualTo(http.StatusNotFound))
+	we.CheckThat(recorder.Body.String(), strings.Contains("no stubs were added"))
+}
+
+func Test_HasHeader(t *testing.T) {
+	we := asserter.Using(t)
+	header := http.Header{"Accept": []string{"text/plain", "text/html"}}
+	we.CheckThat(HasHeader("Accept", EqualTo("text/plain")).Match(header), Matched)
+	we.CheckThat(HasHeader("Accept", EqualTo("text/html")).Match(header), DidNotMatch)
+	we.CheckThat(HasHeader("Accept-Language", EqualTo("")).Match(header), Matched)
+}
+
+func Test_overHTTP(t *testing.T) {
+	we := asserter.Using(t)
+	server := NewServer()
+	defer server.Close()
+	server.When("GET", EqualTo("/ping")).Respond(http.StatusOK, "pong")
+
+	request, _ := http.NewRequest("GET", server.URL + "/ping", nil)
+	response, err := http.DefaultClient.Do(request)
+	we.AssertThat(err, Nil())
+	body, _ := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	we.CheckThat(response.StatusCode, EqualTo(http.StatusOK))
+	we.CheckThat(string(body), EqualTo("pong"))
+}
+
+func Test_VerifyAllCalled(t *testing.T) {
+	we := asserter.Using(t)
+	server := NewServer()
+	defer server.Close()
+	called := server.When("GET", EqualTo("/called"))
+	uncalled := server.When("GET", EqualTo("/uncalled"))
+	_Serve(server, "GET", "http://example.com/called", "")
+	we.CheckThat(called, WasCalled())
+	we.CheckThat(uncalled, Not(WasCalled()))
+	we.CheckThat(called.Calls(), EqualTo(1))
+
+	var log bytes.Buffer
+	verifier := asserter.UsingWriter(&log)
+	server.VerifyAllCalled(verifier)
+	we.CheckThat(verifier.Failed(), True().Comment("one stub was not called"))
+	we.CheckThat(log.String(), strings.Contains("/uncalled"))
+}
+
+func Test_WasCalled_structure(t *testing.T) {
+	we := asserter.Using(t)
+	var names []string
+	Not(WasCalled()).Inspect(func(m *base.Matcher) bool {
+		names = append(names, m.Name())
+		return true
+	})
+	we.CheckThat(names, DeepEqualTo([]string{"Not", "WasCalled"}))
+}
@@ -0,0 +1,19 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+	Provides Matchers on JSON documents given as strings, byte slices
+	or io.Readers:  checking that they are well-formed, comparing them
+	for equivalence (ignoring whitespace and key order) and applying
+	other matchers to the values selected by JSON paths, such as
+	"$.items[0].id".
+
+	ConformsToSchema validates documents against a JSON Schema.
+
+	Readers are read without being used up where possible, so several
+	matchers can check the same *bytes.Buffer or io.Seeker (such as an
+	*os.File).  Other readers can only be matched once:  a later matcher
+	sees them as empty, and so does not match.
+*/
+package json
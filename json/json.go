@@ -0,0 +1,281 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/base"
+	"io"
+	"json"
+	"os"
+	"sort"
+)
+
+// Decodes a JSON document given as a string, a []byte or an io.Reader
+// (read with base.ReadAllRewinding, so that other matchers can read a
+// *bytes.Buffer or an io.Seeker again).  Returns a non-nil error
+// describing the problem if the input is not valid JSON, and ok=false
+// if the input is of some other type.
+func _Decode(actual interface{}) (value interface{}, err os.Error, ok bool) {
+	var data []byte
+	switch input := actual.(type) {
+	case string:
+		data = []byte(input)
+	case []byte:
+		data = input
+	case io.Reader:
+		data, err = base.ReadAllRewinding(input)
+		if err != nil {
+			return nil, err, true
+		}
+	default:
+		return nil, nil, false
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err, true
+	}
+	return value, nil, true
+}
+
+// Returns a matcher whose match function decodes its input (see
+// _Decode) and passes the decoded value to the given function.  Inputs
+// that are not valid JSON fail to match, and inputs of unsupported
+// types cannot be matched at all.  The matcher has the given structured
+// description (see base.Matcher.WithStructure), with the given child
+// matcher, if it is not nil.
+func _NewJSONMatcher(f func(value interface{}) *base.Result,
+		name string, params []interface{}, child *base.Matcher,
+		format string, args...interface{}) *base.Matcher {
+	match := func(actual interface{}) *base.Result {
+		value, err, ok := _Decode(actual)
+		if !ok {
+			return base.NewErrorResultf(
+				"Could not apply to %T: expected string, []byte or io.Reader", actual)
+		}
+		if err != nil {
+			return base.NewResultf(false, "not valid JSON: %v", err)
+		}
+		return f(value)
+	}
+	return base.NewMatcherf(match, format, args...).
+		WithStructure(name, params, _Children(child)...)
+}
+
+// Returns the given matcher as a list of children, or none if it is
+// nil.
+func _Children(child *base.Matcher) []*base.Matcher {
+	if child == nil {
+		return nil
+	}
+	return []*base.Matcher{child}
+}
+
+// Matches strings, byte slices and readers that contain a single valid
+// JSON document.
+func IsJSON() *base.Matcher {
+	return _NewJSONMatcher(func(value interface{}) *base.Result {
+		return base.NewResultf(true, "valid JSON %v", _Kind(value))
+	}, "IsJSON", nil, nil, "IsJSON")
+}
+
+// Returns a matcher that decodes JSON input and matches if it is
+// equivalent to the given JSON document:  the same values in the
+// same structure, regardless of whitespace and of the order of keys in
+// objects.  On a mismatch, each structural difference (a missing key,
+// an unexpected key, a different value) is reported as a separate
+// cause, identified by its JSON path.
+//
+// Note that all JSON numbers are compared as float64.
+//
+// If the expected document is not valid JSON, the returned matcher
+// reports the parse error as a matcher error on every input.
+func EquivalentJSON(expected string) *base.Matcher {
+	return _EquivalentJSON(expected, false, "EquivalentJSON")
+}
+
+// Lenient variant of EquivalentJSON() that ignores keys present in
+// objects of the input document that are not in the expected document
+// (at any depth), so that APIs can add fields without breaking tests.
+func EquivalentJSONIgnoringExtraFields(expected string) *base.Matcher {
+	return _EquivalentJSON(expected, true, "EquivalentJSONIgnoringExtraFields")
+}
+
+func _EquivalentJSON(expected string, lenient bool, name string) *base.Matcher {
+	var want interface{}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		return base.NewErrorMatcherf(err, "%v[%v]", name, expected).
+			WithStructure(name, []interface{}{expected})
+	}
+	rendered := _Render(want)
+	return _NewJSONMatcher(func(value interface{}) *base.Result {
+		differences := _Compare("$", want, value, lenient, nil)
+		if len(differences) == 0 {
+			return base.NewResultf(true, "equivalent to %v", rendered)
+		}
+		causes := make([]*base.Result, len(differences))
+		for i, difference := range differences {
+			causes[i] = base.NewResultf(false, "%v", difference)
+		}
+		return base.NewResultf(false,
+			"%v differences from %v", len(differences), rendered).
+			WithCauses(causes...)
+	}, name, []interface{}{expected}, nil, "%v[%v]", name, rendered)
+}
+
+// Appends to differences a description of each way in which actual
+// differs from expected, for values at the given JSON path.
+func _Compare(path string, expected, actual interface{}, lenient bool, differences []string) []string {
+	if _Kind(expected) != _Kind(actual) {
+		return append(differences, fmt.Sprintf(
+			"%v: expected %v %v but was %v %v", path,
+			_Kind(expected), _Render(expected), _Kind(actual), _Render(actual)))
+	}
+	switch want := expected.(type) {
+	case map[string]interface{}:
+		got := actual.(map[string]interface{})
+		for _, key := range _Keys(want) {
+			if value, ok := got[key]; ok {
+				differences = _Compare(_Child(path, key), want[key], value, lenient, differences)
+			} else {
+				differences = append(differences, fmt.Sprintf(
+					"%v: missing, expected %v", _Child(path, key), _Render(want[key])))
+			}
+		}
+		if !lenient {
+			for _, key := range _Keys(got) {
+				if _, ok := want[key]; !ok {
+					differences = append(differences, fmt.Sprintf(
+						"%v: unexpected %v", _Child(path, key), _Render(got[key])))
+				}
+			}
+		}
+	case []interface{}:
+		got := actual.([]interface{})
+		if len(want) != len(got) {
+			differences = append(differences, fmt.Sprintf(
+				"%v: expected %v elements but was %v", path, len(want), len(got)))
+		}
+		for i := 0; i < len(want) && i < len(got); i++ {
+			differences = _Compare(fmt.Sprintf("%v[%v]", path, i),
+				want[i], got[i], lenient, differences)
+		}
+	default:
+		if expected != actual {
+			differences = append(differences, fmt.Sprintf(
+				"%v: expected %v but was %v", path, _Render(expected), _Render(actual)))
+		}
+	}
+	return differences
+}
+
+// Returns a matcher that decodes JSON input and matches if it is an
+// object with the given key.  The matcher also accepts objects that
+// have already been decoded (as map[string]interface{}), so that it
+// can be used with AtJSONPath:
+//    AtJSONPath("$.items[0]", HasJSONKey("id"))
+func HasJSONKey(key string) *base.Matcher {
+	hasKey := func(value interface{}) *base.Result {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return base.NewResultf(false,
+				"expected object but was %v %v", _Kind(value), _Render(value))
+		}
+		if _, ok := object[key]; ok {
+			return base.NewResultf(true,
+				"has key %q in object with keys %q", key, _Keys(object))
+		}
+		return base.NewResultf(false,
+			"no key %q in object with keys %q", key, _Keys(object))
+	}
+	jsonMatcher := _NewJSONMatcher(hasKey,
+		"HasJSONKey", []interface{}{key}, nil, "HasJSONKey[%q]", key)
+	match := func(actual interface{}) *base.Result {
+		if object, ok := actual.(map[string]interface{}); ok {
+			return hasKey(object)
+		}
+		return jsonMatcher.Match(actual)
+	}
+	return base.NewMatcherf(match, "HasJSONKey[%q]", key).
+		WithStructure("HasJSONKey", []interface{}{key})
+}
+
+// Returns a matcher that decodes JSON input and applies the given
+// matcher to the value selected by the given JSON path.  Supported
+// paths begin with "$" (the whole document) followed by any sequence of:
+//    .name or ["name"] or ['name']   the member of an object
+//    [n]                             element n of an array (negative
+//                                    values count from the end)
+//    .* or [*]                       every member or element
+// For example:
+//    AtJSONPath("$.items[0].id", EqualTo(1.0))
+//
+// Decoded values are strings, float64s, bools, nil,
+// []interface{} or map[string]interface{}.  If the path contains a
+// wildcard, the matcher is applied to the []interface{} of all selected
+// values (skipping any that lack the steps after the wildcard).
+// Otherwise, if the path selects nothing, the matcher fails to match.
+//
+// If the path is invalid, the returned matcher reports the error as a
+// matcher error on every input.
+func AtJSONPath(path string, matcher *base.Matcher) *base.Matcher {
+	steps, err := _ParsePath(path)
+	if err != nil {
+		return base.NewErrorMatcherf(err, "AtJSONPath[%q][%v]", path, matcher).
+			WithStructure("AtJSONPath", []interface{}{path}, matcher)
+	}
+	return _NewJSONMatcher(func(value interface{}) *base.Result {
+		selected, err := _Select(steps, value)
+		if err != nil {
+			return base.NewResultf(false, "%v", err)
+		}
+		var input interface{} = selected
+		if !_HasWildcard(steps) {
+			input = selected[0]
+		}
+		result := matcher.Match(input)
+		return base.NewResultf(result.Matched(),
+			"%v selected %v", path, _Render(input)).
+			WithError(result.Err()).
+			WithCauses(result)
+	}, "AtJSONPath", []interface{}{path}, matcher, "AtJSONPath[%q][%v]", path, matcher)
+}
+
+// Returns the JSON type name of a decoded value.
+func _Kind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// Returns the compact JSON encoding of a decoded value.
+func _Render(value interface{}) string {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(bytes)
+}
+
+// Returns the keys of an object, in sorted order.
+func _Keys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"bytes"
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	. "github.com/rdrdr/hamcrest/core"
+	"io"
+	"testing"
+)
+
+var Matched = base.Matched()
+var DidNotMatch = base.DidNotMatch()
+var Errored = base.Errored()
+
+const order = `{
+	"id": 7,
+	"items": [{"sku": "a-1", "qty": 2}, {"sku": "b-2", "qty": 1}],
+	"gift": false,
+	"note": null
+}`
+
+func Test_IsJSON(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(IsJSON().Match(order), Matched)
+	we.CheckThat(IsJSON().Match([]byte(`[1, 2]`)), Matched)
+	we.CheckThat(IsJSON().Match(bytes.NewBufferString(`"text"`)), Matched)
+	we.CheckThat(IsJSON().Match(`{"id": 7`), DidNotMatch)
+	we.CheckThat(IsJSON().Match(`{id: 7}`), DidNotMatch)
+	we.CheckThat(IsJSON().Match(7), Errored.
+		Comment("unsupported input type"))
+}
+
+func Test_readersCanBeMatchedMoreThanOnce(t *testing.T) {
+	we := asserter.Using(t)
+	reader := bytes.NewBufferString(order)
+	we.CheckThat(AllOf(IsJSON(), AtJSONPath("$.id", EqualTo(7.0))).Match(reader), Matched)
+	we.CheckThat(reader.String(), EqualTo(order).Comment("not consumed"))
+	limited := io.LimitReader(bytes.NewBufferString(order), 1000)
+	we.CheckThat(IsJSON().Match(limited), Matched)
+	we.CheckThat(IsJSON().Match(limited), DidNotMatch.
+		Comment("already consumed"))
+	empty := io.LimitReader(bytes.NewBufferString(order), 0)
+	we.CheckThat(IsJSON().Match(empty), DidNotMatch.
+		Comment("empty, not an error"))
+}
+
+func Test_EquivalentJSON(t *testing.T) {
+	we := asserter.Using(t)
+	reordered := `{"note": null, "gift": false, "id": 7,
+		"items": [{"qty": 2, "sku": "a-1"}, {"qty": 1, "sku": "b-2"}]}`
+	we.CheckThat(EquivalentJSON(reordered).Match(order), Matched)
+	we.CheckThat(EquivalentJSON(`[1, 2]`).Match(`[2, 1]`), DidNotMatch.
+		Comment("array order matters"))
+	we.CheckThat(EquivalentJSON(`{"id": 7}`).Match(`{"id": "7"}`), DidNotMatch)
+	we.CheckThat(EquivalentJSON(`1.0`).Match(`1`), Matched)
+
+	result := EquivalentJSON(`{"id": 8, "gift": false, "note": null, "items": []}`).
+		Match(order)
+	we.CheckThat(result, DidNotMatch)
+	we.CheckThat(len(result.Causes()), EqualTo(2).
+		Comment("one cause per difference"))
+	we.CheckThat(EquivalentJSON(`{`).Match(order), Errored)
+}
+
+func Test_EquivalentJSONIgnoringExtraFields(t *testing.T) {
+	we := asserter.Using(t)
+	lenient := EquivalentJSONIgnoringExtraFields(`{"id": 7, "items": [{"sku": "a-1"}, {}]}`)
+	we.CheckThat(lenient.Match(order), Matched)
+	we.CheckThat(lenient.Match(`{"id": 7, "items": [{"sku": "a-1"}]}`), DidNotMatch.
+		Comment("arrays must still have the same length"))
+	we.CheckThat(lenient.Match(`{"items": [{"sku": "a-1"}, {}]}`), DidNotMatch.
+		Comment("expected fields are still required"))
+}
+
+func Test_HasJSONKey(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(HasJSONKey("note").Match(order), Matched)
+	we.CheckThat(HasJSONKey("total").Match(order), DidNotMatch)
+	we.CheckThat(HasJSONKey("id").Match(`[{"id": 7}]`), DidNotMatch)
+	we.CheckThat(AtJSONPath("$.items[0]", HasJSONKey("qty")).Match(order), Matched)
+}
+
+func Test_AtJSONPath(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(AtJSONPath("$.id", EqualTo(7.0)).Match(order), Matched)
+	we.CheckThat(AtJSONPath("$.items[1].sku", EqualTo("b-2")).Match(order), Matched)
+	we.CheckThat(AtJSONPath("$.items[-1].qty", EqualTo(1.0)).Match(order), Matched)
+	we.CheckThat(AtJSONPath("$['gift']", False()).Match(order), Matched)
+	we.CheckThat(AtJSONPath("$.note", Nil()).Match(order), Matched)
+	we.CheckThat(AtJSONPath("$", Anything()).Match(order), Matched)
+	we.CheckThat(AtJSONPath("$.items[*].sku",
+		DeepEqualTo([]interface{}{"a-1", "b-2"})).Match(order), Matched)
+	we.CheckThat(AtJSONPath("$.missing", Anything()).Match(order), DidNotMatch)
+	we.CheckThat(AtJSONPath("$.items[2]", Anything()).Match(order), DidNotMatch)
+	we.CheckThat(AtJSONPath("$.id.value", Anything()).Match(order), DidNotMatch)
+	we.CheckThat(AtJSONPath("id", Anything()).Match(order), Errored)
+	we.CheckThat(AtJSONPath("$.items[one]", Anything()).Match(order), Errored)
+}
+
+func Test_structure(t *testing.T) {
+	we := asserter.Using(t)
+	id := EqualTo(7.0)
+	matcher := AllOf(IsJSON(), AtJSONPath("$.items[0]", AllOf(HasJSONKey("id"))),
+		EquivalentJSON(order), ConformsToSchema(`{"type": "object"}`))
+	var names []string
+	matcher.Inspect(func(m *base.Matcher) bool {
+		names = append(names, m.Name())
+		return true
+	})
+	we.CheckThat(names, DeepEqualTo([]string{"AllOf", "IsJSON",
+		"AtJSONPath", "AllOf", "HasJSONKey", "EquivalentJSON", "ConformsToSchema"}))
+	atPath := AtJSONPath("$.id", id)
+	we.CheckThat(atPath.Params(), DeepEqualTo([]interface{}{"$.id"}))
+	we.CheckThat(atPath.Children()[0] == id, Is(True()))
+	we.CheckThat(AtJSONPath("id", id).Children()[0] == id, Is(True()).
+		Comment("invalid path"))
+	we.CheckThat(HasJSONKey("id").Params(), DeepEqualTo([]interface{}{"id"}))
+	we.CheckThat(EquivalentJSON(`{"id": 7}`).Params(), DeepEqualTo([]interface{}{`{"id": 7}`}))
+	we.CheckThat(EquivalentJSON(`{`).Name(), EqualTo("EquivalentJSON").
+		Comment("invalid document"))
+	we.CheckThat(EquivalentJSONIgnoringExtraFields(`{}`).Name(),
+		EqualTo("EquivalentJSONIgnoringExtraFields"))
+	we.CheckThat(ConformsToSchema(`{`).Name(), EqualTo("ConformsToSchema").
+		Comment("invalid schema"))
+}
@@ -0,0 +1,204 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A single step of a JSON path:  an object member (by key), an array
+// element (by index), or every member or element (a wildcard).
+type _Step struct {
+	key string
+	index int
+	isIndex bool
+	isWildcard bool
+}
+
+func (self *_Step) String() string {
+	switch {
+	case self.isWildcard:
+		return "[*]"
+	case self.isIndex:
+		return fmt.Sprintf("[%v]", self.index)
+	}
+	return _Child("", self.key)
+}
+
+// Parses a JSON path, as described for AtJSONPath.
+func _ParsePath(path string) ([]*_Step, os.Error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, _PathError(path, "must begin with \"$\"")
+	}
+	var steps []*_Step
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			switch name {
+			case "":
+				return nil, _PathError(path, "missing name after \".\"")
+			case "*":
+				steps = append(steps, &_Step{isWildcard: true})
+			default:
+				steps = append(steps, &_Step{key: name})
+			}
+		case '[':
+			step, remainder, err := _ParseBracket(path, rest)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+			rest = remainder
+		default:
+			return nil, _PathError(path, fmt.Sprintf("unexpected %q", rest[0]))
+		}
+	}
+	return steps, nil
+}
+
+// Parses a bracketed step at the beginning of rest, returning the step
+// and what follows it.
+func _ParseBracket(path, rest string) (*_Step, string, os.Error) {
+	if len(rest) > 1 && (rest[1] == '\'' || rest[1] == '"') {
+		quote := rest[1]
+		end := strings.IndexRune(rest[2:], int(quote))
+		if end < 0 || !strings.HasPrefix(rest[2+end+1:], "]") {
+			return nil, "", _PathError(path, "unterminated quoted name")
+		}
+		return &_Step{key: rest[2:2+end]}, rest[2+end+2:], nil
+	}
+	end := strings.Index(rest, "]")
+	if end < 0 {
+		return nil, "", _PathError(path, "missing \"]\"")
+	}
+	inside := rest[1:end]
+	if inside == "*" {
+		return &_Step{isWildcard: true}, rest[end+1:], nil
+	}
+	index, err := strconv.Atoi(inside)
+	if err != nil {
+		return nil, "", _PathError(path, fmt.Sprintf("invalid index %q", inside))
+	}
+	return &_Step{index: index, isIndex: true}, rest[end+1:], nil
+}
+
+func _PathError(path, problem string) os.Error {
+	return os.NewError(fmt.Sprintf("invalid JSON path %q: %v", path, problem))
+}
+
+// Returns true if any of the steps is a wildcard.
+func _HasWildcard(steps []*_Step) bool {
+	for _, step := range steps {
+		if step.isWildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// Applies the steps of a path to a decoded document, returning the
+// selected values.  Without wildcards, exactly one value is selected
+// or an error describes where the path left the document.  Wildcards
+// select every member or element, and values that do not have the
+// following steps are skipped.
+func _Select(steps []*_Step, document interface{}) ([]interface{}, os.Error) {
+	values := []interface{}{document}
+	wildcard := false
+	path := "$"
+	for _, step := range steps {
+		var selected []interface{}
+		for _, value := range values {
+			children, err := _Apply(step, value)
+			if err != nil {
+				if wildcard {
+					continue
+				}
+				return nil, os.NewError(fmt.Sprintf("%v: %v", path, err))
+			}
+			selected = append(selected, children...)
+		}
+		values = selected
+		wildcard = wildcard || step.isWildcard
+		path += step.String()
+	}
+	return values, nil
+}
+
+// Applies a single step to a decoded value.
+func _Apply(step *_Step, value interface{}) ([]interface{}, os.Error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if step.isWildcard {
+			children := make([]interface{}, 0, len(v))
+			for _, key := range _Keys(v) {
+				children = append(children, v[key])
+			}
+			return children, nil
+		}
+		if step.isIndex {
+			return nil, os.NewError(fmt.Sprintf(
+				"cannot select %v from an object", step))
+		}
+		if child, ok := v[step.key]; ok {
+			return []interface{}{child}, nil
+		}
+		return nil, os.NewError(fmt.Sprintf(
+			"no key %q in object with keys %q", step.key, _Keys(v)))
+	case []interface{}:
+		if step.isWildcard {
+			return v, nil
+		}
+		if !step.isIndex {
+			return nil, os.NewError(fmt.Sprintf(
+				"cannot select %v from an array", step))
+		}
+		index := step.index
+		if index < 0 {
+			index += len(v)
+		}
+		if index < 0 || len(v) <= index {
+			return nil, os.NewError(fmt.Sprintf(
+				"no element %v in array of %v elements", step.index, len(v)))
+		}
+		return []interface{}{v[index]}, nil
+	}
+	return nil, os.NewError(fmt.Sprintf(
+		"cannot select %v from %v %v", step, _Kind(value), _Render(value)))
+}
+
+// Returns the path of the member of the object at path with the given
+// key, using dotted notation for simple names.
+func _Child(path, key string) string {
+	if _IsSimpleName(key) {
+		return path + "." + key
+	}
+	return fmt.Sprintf("%v[%q]", path, key)
+}
+
+func _IsSimpleName(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case i > 0 && '0' <= c && c <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	. "github.com/rdrdr/hamcrest/core"
+	"testing"
+)
+
+func Test_ParsePath(t *testing.T) {
+	we := asserter.Using(t)
+	steps, err := _ParsePath(`$.items[0]["odd key"]['x'].*[*][-1]`)
+	we.AssertThat(err, Nil())
+	we.CheckThat(len(steps), EqualTo(7))
+	we.CheckThat(steps[0].key, EqualTo("items"))
+	we.CheckThat(steps[1].index, EqualTo(0))
+	we.CheckThat(steps[2].key, EqualTo("odd key"))
+	we.CheckThat(steps[3].key, EqualTo("x"))
+	we.CheckThat(steps[4].isWildcard, True())
+	we.CheckThat(steps[5].isWildcard, True())
+	we.CheckThat(steps[6].index, EqualTo(-1))
+
+	for _, bad := range []string{"", "items", "$.", "$[", "$[x]", "$['x]", "$x"} {
+		_, err := _ParsePath(bad)
+		we.CheckThat(err, Not(Nil()).Comment(bad))
+	}
+}
+
+func Test_Child(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(_Child("$", "id"), EqualTo("$.id"))
+	we.CheckThat(_Child("$", "odd key"), EqualTo(`$["odd key"]`))
+	we.CheckThat(_Child("$", "2nd"), EqualTo(`$["2nd"]`))
+}
@@ -0,0 +1,336 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/base"
+	"json"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+	"utf8"
+)
+
+// Returns a matcher that decodes JSON input and matches if it conforms
+// to the given JSON Schema (draft 2020-12).  On a mismatch, every
+// violation is reported as a separate cause, identified by the JSON
+// path of the offending value, such as:
+//    $.items[1].qty: 0 is less than minimum 1
+//
+// The supported keywords are:
+//    type, enum, const                          (any value)
+//    required, properties, additionalProperties (objects)
+//    items, prefixItems, minItems, maxItems     (arrays)
+//    pattern, minLength, maxLength              (strings)
+//    minimum, maximum,
+//    exclusiveMinimum, exclusiveMaximum         (numbers)
+//    $ref                                       (within the document,
+//                                                such as "#/$defs/item")
+// along with the boolean schemas true and false.  Other keywords are
+// ignored.  Patterns use the syntax of the regexp package.
+//
+// If the schema is not valid JSON, has an invalid pattern or has a
+// $ref that cannot be resolved (or that leads only through other $refs
+// back to itself, such as {"$ref": "#"}), the returned matcher reports
+// the problem as a matcher error on every input.
+func ConformsToSchema(schemaDoc string) *base.Matcher {
+	var root interface{}
+	if err := json.Unmarshal([]byte(schemaDoc), &root); err != nil {
+		return base.NewErrorMatcherf(err, "ConformsToSchema[%v]", schemaDoc).
+			WithStructure("ConformsToSchema", []interface{}{schemaDoc})
+	}
+	schema := &_Schema{root: root,
+		patterns: make(map[string]*regexp.Regexp),
+		checked: map[string]bool{"#": true}}
+	if err := schema.check(root, "#"); err != nil {
+		return base.NewErrorMatcherf(err, "ConformsToSchema[%v]", _Render(root)).
+			WithStructure("ConformsToSchema", []interface{}{schemaDoc})
+	}
+	return _NewJSONMatcher(func(value interface{}) *base.Result {
+		violations := schema.validate(root, value, "$", nil)
+		if len(violations) == 0 {
+			return base.NewResultf(true, "conforms to schema")
+		}
+		causes := make([]*base.Result, len(violations))
+		for i, violation := range violations {
+			causes[i] = base.NewResultf(false, "%v", violation)
+		}
+		return base.NewResultf(false,
+			"%v violations of schema", len(violations)).
+			WithCauses(causes...)
+	}, "ConformsToSchema", []interface{}{schemaDoc}, nil,
+		"ConformsToSchema[%v]", _Render(root))
+}
+
+type _Schema struct {
+	root interface{}
+	patterns map[string]*regexp.Regexp
+	checked map[string]bool // $refs whose targets have been checked
+}
+
+// Checks that every pattern in the schema (at the given location in
+// the schema document) compiles and every $ref resolves, following
+// each $ref so that the schemas it leads to are checked too (once
+// each, however many $refs lead to them).
+func (self *_Schema) check(schema interface{}, location string) os.Error {
+	keywords, ok := schema.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if pattern, ok := keywords["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return os.NewError(fmt.Sprintf(
+				"invalid pattern %q at %v/pattern: %v", pattern, location, err))
+		}
+		self.patterns[pattern] = re
+	}
+	if ref, ok := keywords["$ref"].(string); ok {
+		if err := self.checkRef(ref, location); err != nil {
+			return err
+		}
+		if !self.checked[ref] {
+			self.checked[ref] = true
+			target, err := self.resolve(ref)
+			if err != nil {
+				return err
+			}
+			if err := self.check(target, ref); err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyword := range []string{"items", "additionalProperties"} {
+		if err := self.check(keywords[keyword], location + "/" + keyword); err != nil {
+			return err
+		}
+	}
+	if schemas, ok := keywords["prefixItems"].([]interface{}); ok {
+		for i, subschema := range schemas {
+			if err := self.check(subschema, fmt.Sprintf("%v/prefixItems/%v", location, i)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyword := range []string{"properties", "$defs", "definitions"} {
+		if schemas, ok := keywords[keyword].(map[string]interface{}); ok {
+			for _, name := range _Keys(schemas) {
+				if err := self.check(schemas[name], location + "/" + keyword + "/" + name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Checks that the $ref (at the given location in the schema document)
+// resolves, and that following it and any $ref of the schema it leads
+// to does not lead back to an earlier reference:  validation follows
+// each $ref without moving on through the instance, so such a cycle
+// would never end.
+func (self *_Schema) checkRef(ref string, location string) os.Error {
+	seen := make(map[string]bool)
+	for !seen[ref] {
+		seen[ref] = true
+		target, err := self.resolve(ref)
+		if err != nil {
+			return err
+		}
+		keywords, _ := target.(map[string]interface{})
+		next, ok := keywords["$ref"].(string)
+		if !ok {
+			return nil
+		}
+		ref = next
+	}
+	return os.NewError(fmt.Sprintf(
+		"circular $ref at %v/$ref: %q leads back to itself", location, ref))
+}
+
+// Returns the compiled pattern, which check() compiled in advance.
+func (self *_Schema) compile(pattern string) (*regexp.Regexp, os.Error) {
+	if re, ok := self.patterns[pattern]; ok {
+		return re, nil
+	}
+	return regexp.Compile(pattern)
+}
+
+// Resolves a reference to a location within the schema document, given
+// as a JSON pointer fragment such as "#/$defs/item".
+func (self *_Schema) resolve(ref string) (interface{}, os.Error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, os.NewError(fmt.Sprintf(
+			"unsupported $ref %q: only references within the schema are supported", ref))
+	}
+	value := self.root
+	if ref == "#" {
+		return value, nil
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, os.NewError(fmt.Sprintf("invalid $ref %q", ref))
+	}
+	for _, token := range strings.Split(ref[2:], "/", -1) {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		var found bool
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value, found = v[token]
+		case []interface{}:
+			var index int
+			if _, err := fmt.Sscan(token, &index); err == nil && 0 <= index && index < len(v) {
+				value, found = v[index], true
+			}
+		}
+		if !found {
+			return nil, os.NewError(fmt.Sprintf("unresolvable $ref %q", ref))
+		}
+	}
+	return value, nil
+}
+
+// Appends to violations a description of each way in which the
+// instance (at the given JSON path) fails to conform to the schema.
+func (self *_Schema) validate(schema, instance interface{}, path string, violations []string) []string {
+	violation := func(format string, args...interface{}) {
+		violations = append(violations, path + ": " + fmt.Sprintf(format, args...))
+	}
+	if allowed, ok := schema.(bool); ok {
+		if !allowed {
+			violation("no value is allowed here, but was %v", _Render(instance))
+		}
+		return violations
+	}
+	keywords, ok := schema.(map[string]interface{})
+	if !ok {
+		return violations
+	}
+
+	if ref, ok := keywords["$ref"].(string); ok {
+		// check() resolved every $ref that validation can reach (and
+		// checked it for cycles), so this should not fail.
+		target, err := self.resolve(ref)
+		if err != nil {
+			violation("%v", err)
+		} else {
+			violations = self.validate(target, instance, path, violations)
+		}
+	}
+	if types, ok := keywords["type"]; ok && !_HasType(instance, types) {
+		violation("expected type %v but was %v %v",
+			_Render(types), _Kind(instance), _Render(instance))
+		return violations // other keywords would only add noise
+	}
+	if values, ok := keywords["enum"].([]interface{}); ok {
+		found := false
+		for _, value := range values {
+			if len(_Compare("$", value, instance, false, nil)) == 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			violation("%v is not one of %v", _Render(instance), _Render(values))
+		}
+	}
+	if value, ok := keywords["const"]; ok {
+		if len(_Compare("$", value, instance, false, nil)) > 0 {
+			violation("expected %v but was %v", _Render(value), _Render(instance))
+		}
+	}
+
+	switch v := instance.(type) {
+	case map[string]interface{}:
+		if required, ok := keywords["required"].([]interface{}); ok {
+			for _, name := range required {
+				if key, ok := name.(string); ok {
+					if _, ok := v[key]; !ok {
+						violation("missing required property %q", key)
+					}
+				}
+			}
+		}
+		properties, _ := keywords["properties"].(map[string]interface{})
+		additional, hasAdditional := keywords["additionalProperties"]
+		for _, key := range _Keys(v) {
+			if property, ok := properties[key]; ok {
+				violations = self.validate(property, v[key], _Child(path, key), violations)
+			} else if allowed, ok := additional.(bool); ok && !allowed {
+				violations = append(violations, fmt.Sprintf(
+					"%v: additional property %v is not allowed",
+					_Child(path, key), _Render(v[key])))
+			} else if hasAdditional {
+				violations = self.validate(additional, v[key], _Child(path, key), violations)
+			}
+		}
+	case []interface{}:
+		if limit, ok := keywords["minItems"].(float64); ok && float64(len(v)) < limit {
+			violation("has %v items, fewer than minItems %v", len(v), limit)
+		}
+		if limit, ok := keywords["maxItems"].(float64); ok && float64(len(v)) > limit {
+			violation("has %v items, more than maxItems %v", len(v), limit)
+		}
+		prefix, _ := keywords["prefixItems"].([]interface{})
+		items, hasItems := keywords["items"]
+		for i, item := range v {
+			itemPath := fmt.Sprintf("%v[%v]", path, i)
+			if i < len(prefix) {
+				violations = self.validate(prefix[i], item, itemPath, violations)
+			} else if hasItems {
+				violations = self.validate(items, item, itemPath, violations)
+			}
+		}
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if limit, ok := keywords["minLength"].(float64); ok && length < limit {
+			violation("%v has length %v, shorter than minLength %v", _Render(v), length, limit)
+		}
+		if limit, ok := keywords["maxLength"].(float64); ok && length > limit {
+			violation("%v has length %v, longer than maxLength %v", _Render(v), length, limit)
+		}
+		if pattern, ok := keywords["pattern"].(string); ok {
+			if re, err := self.compile(pattern); err != nil {
+				violation("invalid pattern %q: %v", pattern, err)
+			} else if !re.MatchString(v) {
+				violation("%v does not match pattern %q", _Render(v), pattern)
+			}
+		}
+	case float64:
+		if limit, ok := keywords["minimum"].(float64); ok && v < limit {
+			violation("%v is less than minimum %v", v, limit)
+		}
+		if limit, ok := keywords["maximum"].(float64); ok && v > limit {
+			violation("%v is greater than maximum %v", v, limit)
+		}
+		if limit, ok := keywords["exclusiveMinimum"].(float64); ok && v <= limit {
+			violation("%v is not greater than exclusiveMinimum %v", v, limit)
+		}
+		if limit, ok := keywords["exclusiveMaximum"].(float64); ok && v >= limit {
+			violation("%v is not less than exclusiveMaximum %v", v, limit)
+		}
+	}
+	return violations
+}
+
+// Returns true if the instance has the given JSON Schema type, or one
+// of the given types (if types is an array).
+func _HasType(instance interface{}, types interface{}) bool {
+	if list, ok := types.([]interface{}); ok {
+		for _, t := range list {
+			if _HasType(instance, t) {
+				return true
+			}
+		}
+		return false
+	}
+	name, _ := types.(string)
+	if name == "integer" {
+		number, ok := instance.(float64)
+		return ok && number == math.Floor(number) && !math.IsInf(number, 0)
+	}
+	return name == _Kind(instance)
+}
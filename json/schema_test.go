@@ -0,0 +1,96 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	. "github.com/rdrdr/hamcrest/core"
+	"testing"
+)
+
+const orderSchema = `{
+	"type": "object",
+	"required": ["id", "items"],
+	"properties": {
+		"id": {"type": "integer", "minimum": 1},
+		"items": {"type": "array", "minItems": 1, "items": {"$ref": "#/$defs/item"}},
+		"status": {"enum": ["new", "paid"]},
+		"note": {"type": ["string", "null"], "maxLength": 20}
+	},
+	"additionalProperties": false,
+	"$defs": {
+		"item": {
+			"type": "object",
+			"required": ["sku"],
+			"properties": {
+				"sku": {"type": "string", "pattern": "^[a-z]-\\d+$"},
+				"qty": {"type": "integer", "exclusiveMinimum": 0, "maximum": 10}
+			}
+		}
+	}
+}`
+
+func Test_ConformsToSchema(t *testing.T) {
+	we := asserter.Using(t)
+	conforms := ConformsToSchema(orderSchema)
+	we.CheckThat(conforms.Match(
+		`{"id": 7, "items": [{"sku": "a-1", "qty": 2}], "status": "paid", "note": null}`),
+		Matched)
+	we.CheckThat(conforms.Match(`{"id": 7, "items": [{"sku": "a-1"}]}`), Matched)
+	we.CheckThat(conforms.Match(`[]`), DidNotMatch)
+	we.CheckThat(conforms.Match(`{"id": 7, "items": []}`), DidNotMatch.
+		Comment("minItems"))
+}
+
+func Test_ConformsToSchema_reportsEveryViolation(t *testing.T) {
+	we := asserter.Using(t)
+	result := ConformsToSchema(orderSchema).Match(`{
+		"id": 0.5,
+		"items": [{"sku": "A1", "qty": 0}, {"qty": 11}],
+		"status": "lost",
+		"extra": true
+	}`)
+	we.CheckThat(result, DidNotMatch)
+	// id type, qty minimum, sku pattern, missing sku, qty maximum,
+	// status enum and the additional property
+	we.CheckThat(len(result.Causes()), EqualTo(7))
+}
+
+func Test_ConformsToSchema_booleanAndArraySchemas(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ConformsToSchema(`true`).Match(`{"any": "thing"}`), Matched)
+	we.CheckThat(ConformsToSchema(`false`).Match(`1`), DidNotMatch)
+	tuple := ConformsToSchema(`{"prefixItems": [{"type": "string"}], "items": {"type": "number"}}`)
+	we.CheckThat(tuple.Match(`["total", 1, 2.5]`), Matched)
+	we.CheckThat(tuple.Match(`[1, "total"]`), DidNotMatch)
+	we.CheckThat(ConformsToSchema(`{"const": {"a": [1]}}`).Match(`{"a": [1.0]}`), Matched)
+}
+
+func Test_ConformsToSchema_invalidSchemas(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ConformsToSchema(`{"type": `).Match(`1`), Errored)
+	we.CheckThat(ConformsToSchema(`{"pattern": "("}`).Match(`"x"`), Errored)
+	we.CheckThat(ConformsToSchema(`{"$ref": "#/$defs/missing"}`).Match(`1`), Errored)
+	we.CheckThat(ConformsToSchema(`{"$ref": "other.json#/item"}`).Match(`1`), Errored)
+	we.CheckThat(ConformsToSchema(`{"$ref": "#/x",
+		"x": {"properties": {"a": {"$ref": "#/bad"}}}}`).Match(`{"a": 1}`), Errored.
+		Comment("unresolvable $ref within the target of a $ref"))
+}
+
+func Test_ConformsToSchema_circularRefs(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ConformsToSchema(`{"$ref": "#"}`).Match(`1`), Errored)
+	we.CheckThat(ConformsToSchema(`{"$ref": "#/$defs/a", "$defs": {
+		"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}}`).Match(`1`), Errored)
+	we.CheckThat(ConformsToSchema(`{"$ref": "#/x",
+		"x": {"properties": {"a": {"$ref": "#/x/properties/a"}}}}`).Match(`{"a": 1}`), Errored.
+		Comment("self-reference within the target of a $ref"))
+	tree := ConformsToSchema(`{"$defs": {"node": {"type": "object",
+		"properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}}},
+		"$ref": "#/$defs/node"}`)
+	we.CheckThat(tree.Match(`{"children": [{"children": []}, {}]}`), Matched.
+		Comment("recursion through the instance is not a cycle"))
+	we.CheckThat(tree.Match(`{"children": [{"children": 1}]}`), DidNotMatch)
+}
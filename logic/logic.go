@@ -6,8 +6,19 @@ package logic
 
 import (
 	"github.com/rdrdr/hamcrest/base"
+	"os"
 )
 
+// Creates a Result that propagates the error of a component matcher
+// that could not be applied, so that a broken matcher is never
+// mistaken for a successful (or failed) logical clause.
+func _Error(part string, actual interface{}, err os.Error, causes...*base.Result) *base.Result {
+	return base.NewResultf(false,
+		"%v could not be applied to [%v]: %v", part, actual, err).
+		WithError(err).
+		WithCauses(causes...)
+}
+
 // First part of a builder for a short-circuiting both/and matcher:
 //     matcher := Both(Matcher1).And(Matcher2)
 func Both(matcher *base.Matcher) *BothClause {
@@ -24,22 +35,30 @@ func (self *BothClause) And(matcher2 *base.Matcher) *base.Matcher {
 	matcher1 := self.matcher
 	match := func(actual interface{}) *base.Result {
 		result1 := matcher1.Match(actual)
+		if err := result1.Err(); err != nil {
+			return _Error("first part of 'Both/And'", actual, err, result1)
+		}
 		if !result1.Matched() {
 			return base.NewResultf(false,
-				"first part of 'Both/And' did not match [%v]", actual).
+				"[%v] %v", actual, matcher1.Negation()).
 				WithCauses(result1)
 		}
 		result2 := matcher2.Match(actual)
+		if err := result2.Err(); err != nil {
+			return _Error("second part of 'Both/And'", actual, err, result2)
+		}
 		if !result2.Matched() {
 			return base.NewResultf(false,
-				"second part of 'Both/And' did not match [%v]", actual).
+				"[%v] matched [%v], but %v", actual, matcher1, matcher2.Negation()).
 				WithCauses(result2)
 		}
 		return base.NewResultf(true,
 			"both parts of 'Both/And' matched [%v]", actual).
 			WithCauses(result1, result2)
 	}
-	return base.NewMatcherf(match, "both [%v] and [%v]", matcher1, matcher2)
+	return base.NewMatcherf(match, "both [%v] and [%v]", matcher1, matcher2).
+		WithStructure("Both/And", nil, matcher1, matcher2).
+		WithNegationf("%v or %v", matcher1.Negation(), matcher2.Negation())
 }
 
 
@@ -67,6 +86,9 @@ func (self *EitherClause) Or(matcher2 *base.Matcher) *base.Matcher {
 	matcher1 := self.matcher
 	match := func(actual interface{}) *base.Result {
 		result1 := matcher1.Match(actual)
+		if err := result1.Err(); err != nil {
+			return _Error("first part of 'Either/Or'", actual, err, result1)
+		}
 		if result1.Matched() {
 			return base.NewResultf(true,
 				"first part of 'Either/Or' matched [%v]",
@@ -74,6 +96,9 @@ func (self *EitherClause) Or(matcher2 *base.Matcher) *base.Matcher {
 				WithCauses(result1)
 		}
 		result2 := matcher2.Match(actual)
+		if err := result2.Err(); err != nil {
+			return _Error("second part of 'Either/Or'", actual, err, result2)
+		}
 		if result2.Matched() {
 			return base.NewResultf(true,
 				"second part of 'Either/Or' matched [%v]",
@@ -81,11 +106,13 @@ func (self *EitherClause) Or(matcher2 *base.Matcher) *base.Matcher {
 				WithCauses(result2)
 		}
 		return base.NewResultf(false,
-			"neither part of 'Either/Or' matched [%v]", actual).
+			"[%v] %v and %v", actual, matcher1.Negation(), matcher2.Negation()).
 			WithCauses(result1, result2)
 	}
 	return base.NewMatcherf(match,
-		"either [%v] or [%v]", matcher1, matcher2)
+		"either [%v] or [%v]", matcher1, matcher2).
+		WithStructure("Either/Or", nil, matcher1, matcher2).
+		WithNegationf("%v and %v", matcher1.Negation(), matcher2.Negation())
 }
 
 // Second part of a builder for an either/xor matcher:
@@ -99,6 +126,12 @@ func (self *EitherClause) Xor(matcher2 *base.Matcher) *base.Matcher {
 	match := func(actual interface{}) *base.Result {
 		result1 := matcher1.Match(actual)
 		result2 := matcher2.Match(actual)
+		if err := result1.Err(); err != nil {
+			return _Error("first part of 'Either/Xor'", actual, err, result1, result2)
+		}
+		if err := result2.Err(); err != nil {
+			return _Error("second part of 'Either/Xor'", actual, err, result1, result2)
+		}
 		if result1.Matched() {
 			if result2.Matched() {
 				return base.NewResultf(false,
@@ -115,10 +148,12 @@ func (self *EitherClause) Xor(matcher2 *base.Matcher) *base.Matcher {
 				WithCauses(result1, result2)
 		}
 		return base.NewResultf(false,
-			"neither part of 'Either/Xor' matched [%v]", actual).
+			"[%v] %v and %v", actual, matcher1.Negation(), matcher2.Negation()).
 			WithCauses(result1, result2)
 	}
-	return base.NewMatcherf(match, "either [%v] xor [%v]", matcher1, matcher2)
+	return base.NewMatcherf(match, "either [%v] xor [%v]", matcher1, matcher2).
+		WithStructure("Either/Xor", nil, matcher1, matcher2).
+		WithNegationf("matches both or neither of [%v] and [%v]", matcher1, matcher2)
 }
 
 // First part of a builder for a short-circuiting neither/nor matcher:
@@ -147,22 +182,30 @@ func (self *NeitherClause) Nor(matcher2 *base.Matcher) *base.Matcher {
 	matcher1 := self.matcher
 	match := func(actual interface{}) *base.Result {
 		result1 := matcher1.Match(actual)
+		if err := result1.Err(); err != nil {
+			return _Error("first part of 'Nor'", actual, err, result1)
+		}
 		if result1.Matched() {
 			return base.NewResultf(false,
-			"first part of 'Nor' matched [%v]", actual).
-			WithCauses(result1)
+				"expected a value that %v, but was [%v]", matcher1.Negation(), actual).
+				WithCauses(result1)
 		}
 		result2 := matcher2.Match(actual)
+		if err := result2.Err(); err != nil {
+			return _Error("second part of 'Nor'", actual, err, result2)
+		}
 		if result2.Matched() {
 			return base.NewResultf(false,
-				"second part of 'Nor' matched [%v]", actual).
+				"expected a value that %v, but was [%v]", matcher2.Negation(), actual).
 				WithCauses(result2)
 		}
 		return base.NewResultf(true,
-			"neither part of 'Nor' matched [%v]", actual).
+			"[%v] %v and %v", actual, matcher1.Negation(), matcher2.Negation()).
 			WithCauses(result1, result2)
 	}
-	return base.NewMatcherf(match, "neither [%v] nor [%v]", matcher1, matcher2)
+	return base.NewMatcherf(match, "neither [%v] nor [%v]", matcher1, matcher2).
+		WithStructure("Neither/Nor", nil, matcher1, matcher2).
+		WithNegationf("matches [%v] or [%v]", matcher1, matcher2)
 }
 
 
@@ -197,23 +240,31 @@ func (self *IfClause) Then(consequent *base.Matcher) *base.Matcher {
 	antecedent := self.antecedent
 	match := func(actual interface{}) *base.Result {
 		result1 := antecedent.Match(actual)
+		if err := result1.Err(); err != nil {
+			return _Error("antecedent of 'If/Then'", actual, err, result1)
+		}
 		if !result1.Matched() {
 			return base.NewResultf(true,
-				"'If/Then' matched because antecedent failed on [%v]", actual).
+				"'If/Then' matched because [%v] %v", actual, antecedent.Negation()).
 				WithCauses(result1)
 		}
 		result2 := consequent.Match(actual)
+		if err := result2.Err(); err != nil {
+			return _Error("consequent of 'If/Then'", actual, err, result1, result2)
+		}
 		if result2.Matched() {
 			return base.NewResultf(true,
 				"'If/Then' matched because consequent matched on [%v]", actual).
 				WithCauses(result2)
 		}
 		return base.NewResultf(false,
-			"'If/Then' failed on [%v]", actual).
+			"[%v] matched [%v], but %v", actual, antecedent, consequent.Negation()).
 			WithCauses(result1, result2)
 	}
 	return base.NewMatcherf(match,
-		"if [%v] then [%v]", antecedent, consequent)
+		"if [%v] then [%v]", antecedent, consequent).
+		WithStructure("If/Then", nil, antecedent, consequent).
+		WithNegationf("matches [%v], but %v", antecedent, consequent.Negation())
 }
 
 
@@ -243,6 +294,12 @@ func (self *IfAndOnlyIfClause) Then(consequent *base.Matcher) *base.Matcher {
 	match := func(actual interface{}) *base.Result {
 		result1 := antecedent.Match(actual)
 		result2 := consequent.Match(actual)
+		if err := result1.Err(); err != nil {
+			return _Error("antecedent of 'Iff/Then'", actual, err, result1, result2)
+		}
+		if err := result2.Err(); err != nil {
+			return _Error("consequent of 'Iff/Then'", actual, err, result1, result2)
+		}
 		if result1.Matched() {
 			if result2.Matched() {
 				return base.NewResultf(true,
@@ -250,18 +307,20 @@ func (self *IfAndOnlyIfClause) Then(consequent *base.Matcher) *base.Matcher {
 					WithCauses(result1, result2)
 			}
 			return base.NewResultf(false,
-				"Failed because only the first part of 'Iff/Then' matched on [%v]", actual).
+				"[%v] matched [%v], but %v", actual, antecedent, consequent.Negation()).
 				WithCauses(result1, result2)
 		}
 		if result2.Matched() {
 			return base.NewResultf(false,
-				"Failed because only the second part of 'IFf/Then' matched on [%v]", actual).
+				"[%v] matched [%v], but %v", actual, consequent, antecedent.Negation()).
 				WithCauses(result1, result2)
 		}
 		return base.NewResultf(true,
-			"Matched because neither part of 'Iff/Then' matched on [%v]", actual).
+			"Matched because [%v] %v and %v", actual, antecedent.Negation(), consequent.Negation()).
 			WithCauses(result1, result2)
 	}
-	return base.NewMatcherf(match, "if and only if [%v] then [%v]", antecedent, consequent)
+	return base.NewMatcherf(match, "if and only if [%v] then [%v]", antecedent, consequent).
+		WithStructure("IfAndOnlyIf/Then", nil, antecedent, consequent).
+		WithNegationf("matches exactly one of [%v] and [%v]", antecedent, consequent)
 }
 
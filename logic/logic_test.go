@@ -231,3 +231,82 @@ func Test_IfAndOnlyIfThen(t *testing.T) {
 	logSamples(t, Iff(no).Then(yes))
 	logSamples(t, Iff(no).Then(no))
 }
+
+func Test_clausesPropagateErrors(t *testing.T) {
+	yes, no := Anything(), Not(Anything())
+	broken := base.NewMatcherf(func(s string) bool { return true }, "OnlyStrings")
+	matchers := []*base.Matcher{
+		Both(yes).And(broken),
+		Either(no).Or(broken),
+		Either(yes).Xor(broken),
+		Neither(no).Nor(broken),
+		If(yes).Then(broken),
+		If(broken).Then(yes),
+		IfAndOnlyIf(no).Then(broken),
+	}
+	for _, matcher := range matchers {
+		result := matcher.Match(42)
+		if result.Err() == nil {
+			t.Errorf("%v should propagate the error, was [%v]", matcher, result)
+		}
+		checkResultIsNonMatching(t, result, "errors never match")
+	}
+}
+
+func Test_structure(t *testing.T) {
+	yes, no := Anything(), Not(Anything())
+	for matcher, name := range map[*base.Matcher]string{
+			Both(yes).And(no): "Both/And",
+			Either(yes).Or(no): "Either/Or",
+			Either(yes).Xor(no): "Either/Xor",
+			Neither(yes).Nor(no): "Neither/Nor",
+			If(yes).Then(no): "If/Then",
+			IfAndOnlyIf(yes).Then(no): "IfAndOnlyIf/Then"} {
+		children := matcher.Children()
+		if matcher.Name() != name || len(children) != 2 || children[0] != yes || children[1] != no {
+			t.Errorf("Expected %v with children [%v, %v], was %v with %v",
+				name, yes, no, matcher.Name(), children)
+		}
+	}
+}
+
+func Test_negatedDescriptions(t *testing.T) {
+	between := Both(GreaterThan(5)).And(LessThan(10))
+	outside := Either(LessThan(5)).Or(GreaterThan(10))
+	outsideXor := Either(LessThan(5)).Xor(GreaterThan(10))
+	neither := Neither(GreaterThan(5)).Nor(LessThan(0))
+	ifThen := If(GreaterThan(5)).Then(LessThan(10))
+	for _, test := range []struct {
+		result *base.Result
+		expected string
+	}{
+		{between.Match(3), "[3] is not greater than 5"},
+		{between.Match(12), "[12] matched [GreaterThan(5)], but is not less than 10"},
+		{outside.Match(7), "[7] is not less than 5 and is not greater than 10"},
+		{outsideXor.Match(7), "[7] is not less than 5 and is not greater than 10"},
+		{neither.Match(7), "expected a value that is not greater than 5, but was [7]"},
+		{neither.Match(3), "[3] is not greater than 5 and is not less than 0"},
+		{ifThen.Match(3), "'If/Then' matched because [3] is not greater than 5"},
+		{ifThen.Match(12), "[12] matched [GreaterThan(5)], but is not less than 10"},
+		{Not(between).Match(3), "[3] is not greater than 5 or is not less than 10"},
+	} {
+		if test.result.String() != test.expected {
+			t.Errorf("Expected result [%v], was [%v]", test.expected, test.result)
+		}
+	}
+	if negation := Not(outside).String(); negation != "is not less than 5 and is not greater than 10" {
+		t.Errorf("Expected Not to use the negation of Either/Or, was %v", negation)
+	}
+	if negation := Not(outsideXor).String();
+			negation != "matches both or neither of [LessThan(5)] and [GreaterThan(10)]" {
+		t.Errorf("Expected Not to use the negation of Either/Xor, was %v", negation)
+	}
+	iff := IfAndOnlyIf(GreaterThan(5)).Then(GreaterThan(6))
+	if negation := Not(iff).String();
+			negation != "matches exactly one of [GreaterThan(5)] and [GreaterThan(6)]" {
+		t.Errorf("Expected Not to use the negation of IfAndOnlyIf/Then, was %v", negation)
+	}
+	if result := Not(iff).Match(6); !result.Matched() {
+		t.Errorf("Expected Not(%v) to match 6, was %v", iff, result)
+	}
+}
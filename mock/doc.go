@@ -0,0 +1,29 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+	Provides a Mock to embed in hand-written test doubles, which records
+	the calls made to them and checks those calls against expectations
+	whose arguments are matched by Matchers:
+	
+		type MockStore struct {
+			mock.Mock
+		}
+		func (self *MockStore) Save(user *User) os.Error {
+			return self.Called("Save", user).Error(0)
+		}
+	
+		store := &MockStore{}
+		store.Expect("Save").
+			With(reflect.HasField("ID", EqualTo(5))).
+			Times(mock.AtLeast(1)).
+			Return(nil)
+		... exercise code that uses store ...
+		store.Verify(we)
+	
+	Verify reports every expectation that was not met and every call
+	that was not expected, explaining (with the Result of each argument
+	matcher) why each call did not meet each expectation.
+*/
+package mock
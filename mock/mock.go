@@ -0,0 +1,345 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mock
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	"os"
+	"strings"
+	"sync"
+)
+
+// A call to a method of a Mock.
+type Call struct {
+	Method string
+	Args []interface{}
+}
+
+// Implements fmt.Stringer.
+func (self *Call) String() string {
+	args := make([]string, len(self.Args))
+	for i, arg := range self.Args {
+		args[i] = fmt.Sprintf("%v", arg)
+	}
+	return self.Method + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Records the calls made to a test double and checks them against
+// expectations.  A Mock is usually embedded in a hand-written type
+// whose methods report their calls, such as:
+//    type MockStore struct {
+//        mock.Mock
+//    }
+//    func (self *MockStore) Save(user *User) os.Error {
+//        return self.Called("Save", user).Error(0)
+//    }
+// A test then sets expectations and verifies them:
+//    store := &MockStore{}
+//    store.Expect("Save").With(HasField("ID", EqualTo(5))).Return(nil)
+//    ... exercise code that uses store ...
+//    store.Verify(we)
+//
+// The zero value is a Mock with no expectations, ready to use.
+type Mock struct {
+	lock sync.Mutex
+	expectations []*Expectation
+	calls []*Call
+	unexpected []*Call
+}
+
+// Adds an expectation that the named method will be called.  By
+// default, it may be called with any arguments, at least once, and
+// returns no values.
+func (self *Mock) Expect(method string) *Expectation {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	expectation := &Expectation{mock: self, method: method, times: AtLeast(1)}
+	self.expectations = append(self.expectations, expectation)
+	return expectation
+}
+
+// Records a call to the named method with the given arguments, and
+// returns the values of the first expectation that it meets.  (If
+// several expectations are met, those that have not yet been called as
+// many times as they allow are preferred.)  If no expectation is met,
+// the call is recorded as unexpected and no values are returned.
+func (self *Mock) Called(method string, args...interface{}) Results {
+	call := &Call{Method: method, Args: args}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.calls = append(self.calls, call)
+	var chosen *Expectation
+	for _, expectation := range self.expectations {
+		if !expectation.matcher().Match(call).Matched() {
+			continue
+		}
+		if expectation.times.Match(len(expectation.calls) + 1).Matched() {
+			chosen = expectation
+			break
+		}
+		if chosen == nil {
+			chosen = expectation
+		}
+	}
+	if chosen == nil {
+		self.unexpected = append(self.unexpected, call)
+		return nil
+	}
+	chosen.calls = append(chosen.calls, call)
+	return chosen.returns
+}
+
+// Returns every call made so far, in the order made.
+func (self *Mock) Calls() []*Call {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	calls := make([]*Call, len(self.calls))
+	copy(calls, self.calls)
+	return calls
+}
+
+// Checks (using the given Asserter) that every expectation was called
+// the expected number of times and that there were no unexpected calls.
+// Failures are logged with Result trees that show, for each unmet
+// expectation, why each call to its method did not match and, for each
+// unexpected call, why it did not match each expectation for its method.
+func (self *Mock) Verify(we asserter.Asserter) {
+	self.lock.Lock()
+	expectations := make([]*Expectation, len(self.expectations))
+	copy(expectations, self.expectations)
+	unexpected := make([]*Call, len(self.unexpected))
+	copy(unexpected, self.unexpected)
+	calls := make([]*Call, len(self.calls))
+	copy(calls, self.calls)
+	self.lock.Unlock()
+
+	for _, expectation := range expectations {
+		we.CheckThat(expectation, _Satisfied(calls))
+	}
+	for _, call := range unexpected {
+		we.CheckThat(call, _Expected(expectations))
+	}
+}
+
+// Matches expectations that were called the expected number of times.
+// On a mismatch, the causes explain why the other calls of the same
+// method did not meet the expectation.
+func _Satisfied(calls []*Call) *base.Matcher {
+	match := func(expectation *Expectation) *base.Result {
+		count := len(expectation.calls)
+		result := expectation.times.Match(count)
+		if result.Matched() {
+			return base.NewResultf(true,
+				"%v was called %v times", expectation, count).
+				WithCauses(result)
+		}
+		causes := []*base.Result{result}
+		for _, call := range calls {
+			if call.Method == expectation.method && !expectation.called(call) {
+				causes = append(causes, expectation.matcher().Match(call))
+			}
+		}
+		return base.NewResultf(false,
+			"%v was called %v times", expectation, count).
+			WithCauses(causes...)
+	}
+	return base.NewMatcherf(match, "Satisfied")
+}
+
+// Matches calls that meet one of the given expectations.  On a
+// mismatch, the causes explain why the call did not meet each of the
+// expectations for its method.
+func _Expected(expectations []*Expectation) *base.Matcher {
+	match := func(call *Call) *base.Result {
+		var causes []*base.Result
+		for _, expectation := range expectations {
+			if expectation.method == call.Method {
+				result := expectation.matcher().Match(call)
+				if result.Matched() {
+					return base.NewResultf(true, "%v was expected", call).
+						WithCauses(result)
+				}
+				causes = append(causes, result)
+			}
+		}
+		if len(causes) == 0 {
+			return base.NewResultf(false,
+				"unexpected call %v: no expectations for %v", call, call.Method)
+		}
+		return base.NewResultf(false,
+			"unexpected call %v: met none of the %v expectations for %v",
+			call, len(causes), call.Method).
+			WithCauses(causes...)
+	}
+	return base.NewMatcherf(match, "Expected")
+}
+
+// An expected call to a method of a Mock:  the arguments it accepts,
+// the number of times it should be called, and the values it returns.
+type Expectation struct {
+	mock *Mock
+	method string
+	args []*base.Matcher // nil accepts any arguments
+	times *base.Matcher
+	returns Results
+	calls []*Call
+}
+
+// Restricts the expectation to calls with exactly as many arguments as
+// the given matchers, each matched by the corresponding matcher.
+func (self *Expectation) With(argMatchers...*base.Matcher) *Expectation {
+	self.mock.lock.Lock()
+	defer self.mock.lock.Unlock()
+	self.args = argMatchers
+	if self.args == nil {
+		self.args = []*base.Matcher{}
+	}
+	return self
+}
+
+// Sets a matcher for the number of times that the method should be
+// called, such as AtLeast(1) (the default), Exactly(2) or Never().
+func (self *Expectation) Times(countMatcher *base.Matcher) *Expectation {
+	self.mock.lock.Lock()
+	defer self.mock.lock.Unlock()
+	self.times = countMatcher
+	return self
+}
+
+// Sets the values returned by calls that meet this expectation.
+func (self *Expectation) Return(values...interface{}) *Expectation {
+	self.mock.lock.Lock()
+	defer self.mock.lock.Unlock()
+	self.returns = values
+	return self
+}
+
+// Implements fmt.Stringer.
+func (self *Expectation) String() string {
+	if self.args == nil {
+		return self.method + "(...)"
+	}
+	args := make([]string, len(self.args))
+	for i, arg := range self.args {
+		args[i] = arg.String()
+	}
+	return self.method + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Returns true if the given call met this expectation.
+func (self *Expectation) called(call *Call) bool {
+	for _, c := range self.calls {
+		if c == call {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns a matcher for calls that meet this expectation (regardless
+// of how many times it has been called).  Each argument's Result is a
+// cause.
+func (self *Expectation) matcher() *base.Matcher {
+	match := func(call *Call) *base.Result {
+		if call.Method != self.method {
+			return base.NewResultf(false,
+				"called %v, not %v", call.Method, self.method)
+		}
+		if self.args == nil {
+			return base.NewResultf(true, "any arguments are allowed")
+		}
+		if len(call.Args) != len(self.args) {
+			return base.NewResultf(false,
+				"called with %v arguments, expected %v", len(call.Args), len(self.args))
+		}
+		causes := make([]*base.Result, len(self.args))
+		failures := 0
+		for i, arg := range self.args {
+			causes[i] = arg.Match(call.Args[i])
+			if !causes[i].Matched() {
+				failures++
+			}
+		}
+		if failures > 0 {
+			return base.NewResultf(false,
+				"%v of %v arguments of %v did not match", failures, len(self.args), call).
+				WithCauses(causes...)
+		}
+		return base.NewResultf(true, "all arguments of %v matched", call).
+			WithCauses(causes...)
+	}
+	return base.NewMatcherf(match, "%v", self)
+}
+
+// Matches call counts of at least n.
+func AtLeast(n int) *base.Matcher {
+	match := func(count int) *base.Result {
+		return base.NewResultf(count >= n, "called %v times", count)
+	}
+	return base.NewMatcherf(match, "AtLeast(%v)", n).
+		WithStructure("AtLeast", []interface{}{n})
+}
+
+// Matches call counts of at most n.
+func AtMost(n int) *base.Matcher {
+	match := func(count int) *base.Result {
+		return base.NewResultf(count <= n, "called %v times", count)
+	}
+	return base.NewMatcherf(match, "AtMost(%v)", n).
+		WithStructure("AtMost", []interface{}{n})
+}
+
+// Matches call counts of exactly n.
+func Exactly(n int) *base.Matcher {
+	match := func(count int) *base.Result {
+		return base.NewResultf(count == n, "called %v times", count)
+	}
+	return base.NewMatcherf(match, "Exactly(%v)", n).
+		WithStructure("Exactly", []interface{}{n})
+}
+
+// Matches a call count of zero.
+func Never() *base.Matcher {
+	return Exactly(0).WithStructure("Never", nil)
+}
+
+// The values returned by a call to a Mock, with accessors that return
+// zero values for missing (or nil) results, so that a mocked method
+// never panics when a call was unexpected.
+type Results []interface{}
+
+// Returns the i'th value, or nil if there are too few.
+func (self Results) Get(i int) interface{} {
+	if i < 0 || len(self) <= i {
+		return nil
+	}
+	return self[i]
+}
+
+// Returns the i'th value as an os.Error, or nil.
+func (self Results) Error(i int) os.Error {
+	err, _ := self.Get(i).(os.Error)
+	return err
+}
+
+// Returns the i'th value as an int, or 0.
+func (self Results) Int(i int) int {
+	value, _ := self.Get(i).(int)
+	return value
+}
+
+// Returns the i'th value as a string, or "".
+func (self Results) String(i int) string {
+	value, _ := self.Get(i).(string)
+	return value
+}
+
+// Returns the i'th value as a bool, or false.
+func (self Results) Bool(i int) bool {
+	value, _ := self.Get(i).(bool)
+	return value
+}
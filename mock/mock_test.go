@@ -0,0 +1,159 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mock
+
+import (
+	"bytes"
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	. "github.com/rdrdr/hamcrest/core"
+	"github.com/rdrdr/hamcrest/reflect"
+	"github.com/rdrdr/hamcrest/strings"
+	"os"
+	"testing"
+)
+
+var Matched = base.Matched()
+var DidNotMatch = base.DidNotMatch()
+
+type _User struct {
+	ID int
+	Name string
+}
+
+type _MockStore struct {
+	Mock
+}
+
+func (self *_MockStore) Save(user *_User) os.Error {
+	return self.Called("Save", user).Error(0)
+}
+
+func (self *_MockStore) Count() int {
+	return self.Called("Count").Int(0)
+}
+
+func Test_Expect_Called(t *testing.T) {
+	we := asserter.Using(t)
+	failure := os.NewError("disk full")
+	store := &_MockStore{}
+	store.Expect("Save").
+		With(reflect.HasField("ID", EqualTo(5))).
+		Times(AtLeast(1))
+	store.Expect("Save").With(Anything()).Return(failure)
+	store.Expect("Count").Return(3)
+
+	we.CheckNil(store.Save(&_User{ID: 5}))
+	we.CheckThat(store.Save(&_User{ID: 6}), EqualTo(failure))
+	we.CheckThat(store.Count(), EqualTo(3))
+
+	calls := store.Calls()
+	we.AssertThat(len(calls), EqualTo(3))
+	we.CheckThat(calls[0].Method, EqualTo("Save"))
+	we.CheckThat(calls[2].String(), EqualTo("Count()"))
+
+	store.Verify(we)
+}
+
+func Test_Called_prefersUnsatisfiedExpectations(t *testing.T) {
+	we := asserter.Using(t)
+	store := &_MockStore{}
+	store.Expect("Count").Times(Exactly(1)).Return(1)
+	store.Expect("Count").Times(Exactly(1)).Return(2)
+	we.CheckThat(store.Count(), EqualTo(1))
+	we.CheckThat(store.Count(), EqualTo(2))
+	we.CheckThat(store.Count(), EqualTo(1))
+}
+
+func Test_Verify_unmetExpectation(t *testing.T) {
+	we := asserter.Using(t)
+	store := &_MockStore{}
+	store.Expect("Save").With(reflect.HasField("ID", EqualTo(5)))
+	store.Save(&_User{ID: 6})
+
+	var log bytes.Buffer
+	verifier := asserter.UsingWriter(&log)
+	store.Verify(verifier)
+	we.CheckThat(verifier.Failed(), True().Comment("expectation was not met"))
+	we.CheckThat(log.String(), strings.Contains("Save(HasField[ID]"))
+	we.CheckThat(log.String(), strings.Contains("was called 0 times"))
+	we.CheckThat(log.String(), strings.Contains("unexpected call Save("))
+}
+
+func Test_Verify_unexpectedCall(t *testing.T) {
+	we := asserter.Using(t)
+	store := &_MockStore{}
+	we.CheckThat(store.Count(), EqualTo(0).Comment("zero value for no results"))
+	we.CheckNil(store.Save(&_User{}))
+
+	var log bytes.Buffer
+	verifier := asserter.UsingWriter(&log)
+	store.Verify(verifier)
+	we.CheckThat(verifier.Failed(), True().Comment("calls were unexpected"))
+	we.CheckThat(log.String(), strings.Contains("no expectations for Count"))
+}
+
+func Test_Verify_never(t *testing.T) {
+	we := asserter.Using(t)
+	store := &_MockStore{}
+	store.Expect("Save").Times(Never())
+	store.Verify(we)
+
+	store.Save(&_User{})
+	var log bytes.Buffer
+	verifier := asserter.UsingWriter(&log)
+	store.Verify(verifier)
+	we.CheckThat(verifier.Failed(), True().Comment("called despite Never()"))
+}
+
+func Test_With_argumentCount(t *testing.T) {
+	we := asserter.Using(t)
+	expectation := (&Mock{}).Expect("Save")
+	expectation.With(Anything(), Anything())
+	we.CheckThat(expectation.matcher().Match(&Call{"Save", []interface{}{1}}), DidNotMatch)
+	we.CheckThat(expectation.matcher().Match(&Call{"Save", []interface{}{1, 2}}), Matched)
+	we.CheckThat(expectation.matcher().Match(&Call{"Load", []interface{}{1, 2}}), DidNotMatch)
+	expectation.With()
+	we.CheckThat(expectation.matcher().Match(&Call{"Save", nil}), Matched)
+	we.CheckThat(expectation.matcher().Match(&Call{"Save", []interface{}{1}}), DidNotMatch)
+}
+
+func Test_CountMatchers(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(AtLeast(2).Match(2), Matched)
+	we.CheckThat(AtLeast(2).Match(1), DidNotMatch)
+	we.CheckThat(AtMost(2).Match(2), Matched)
+	we.CheckThat(AtMost(2).Match(3), DidNotMatch)
+	we.CheckThat(Exactly(2).Match(2), Matched)
+	we.CheckThat(Exactly(2).Match(1), DidNotMatch)
+	we.CheckThat(Never().Match(0), Matched)
+	we.CheckThat(Never().Match(1), DidNotMatch)
+}
+
+func Test_CountMatchers_structure(t *testing.T) {
+	we := asserter.Using(t)
+	matcher := AnyOf(AtLeast(2), AtMost(1), Exactly(3), Never())
+	var names []string
+	matcher.Inspect(func(m *base.Matcher) bool {
+		names = append(names, m.Name())
+		return true
+	})
+	we.CheckThat(names, DeepEqualTo([]string{"AnyOf", "AtLeast", "AtMost", "Exactly", "Never"}))
+	we.CheckThat(AtLeast(2).Params(), DeepEqualTo([]interface{}{2}))
+	we.CheckThat(Exactly(3).Params(), DeepEqualTo([]interface{}{3}))
+}
+
+func Test_Results(t *testing.T) {
+	we := asserter.Using(t)
+	results := Results{"a", 2, true, nil}
+	we.CheckThat(results.String(0), EqualTo("a"))
+	we.CheckThat(results.Int(1), EqualTo(2))
+	we.CheckThat(results.Bool(2), True())
+	we.CheckNil(results.Error(3))
+	we.CheckNil(results.Get(4))
+	we.CheckThat(results.Int(0), EqualTo(0))
+	var none Results
+	we.CheckThat(none.String(0), EqualTo(""))
+}
@@ -0,0 +1,22 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+	Provides property-based testing with Matchers:  a Property claims
+	that every value produced by a Generator is matched by a Matcher.
+	
+		ints := property.GeneratorFunc(func(random *rand.Rand) interface{} {
+			return random.Intn(2000) - 1000
+		})
+		property.ForAll(ints, Applying(abs, "Abs")(GreaterThanOrEqualTo(0))).
+			WithTrials(500).
+			Check(we)
+	
+	When an input is not matched, it is shrunk to a minimal counterexample
+	(integers toward zero, strings by truncation, slices by removing
+	elements), which is reported along with the seed of the run and the
+	full Result of the matcher on the counterexample.  Use WithSeed to
+	generate the same inputs on every run.
+*/
+package property
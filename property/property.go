@@ -0,0 +1,302 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package property
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	"rand"
+	"reflect"
+	"time"
+)
+
+// Produces random input values for a Property.
+type Generator interface {
+	// Returns a new value, using the given source of randomness.
+	Generate(random *rand.Rand) interface{}
+}
+
+// Optionally implemented by a Generator that knows how to simplify
+// the values that it generates.  (Values from Generators that do not
+// implement Shrinker are simplified by the Shrink function.)
+type Shrinker interface {
+	// Returns values that are simpler than the given value, most
+	// aggressively simplified first.  Returns an empty slice if the
+	// value cannot be simplified.
+	Shrink(value interface{}) []interface{}
+}
+
+// Adapts a function to the Generator interface.
+type GeneratorFunc func(random *rand.Rand) interface{}
+
+// Implements Generator.
+func (self GeneratorFunc) Generate(random *rand.Rand) interface{} {
+	return self(random)
+}
+
+// Implements fmt.Stringer.
+func (self GeneratorFunc) String() string {
+	return "GeneratorFunc"
+}
+
+const (
+	// Number of inputs generated by a Property, unless set by WithTrials.
+	DefaultTrials = 100
+
+	// Maximum number of candidate values a Property tries while
+	// shrinking a counterexample, unless set by WithMaxShrinks.
+	DefaultMaxShrinks = 1000
+)
+
+// A claim that every value produced by a Generator is matched by a
+// Matcher.  Create one with ForAll, and test it with Check:
+//    property.ForAll(ints, GreaterThanOrEqualTo(0)).Check(we)
+type Property struct {
+	generator Generator
+	matcher *base.Matcher
+	seed int64
+	seeded bool
+	trials int
+	maxShrinks int
+}
+
+// Returns a Property that the given matcher matches every value
+// produced by the given generator.
+func ForAll(generator Generator, matcher *base.Matcher) *Property {
+	return &Property{
+		generator: generator,
+		matcher: matcher,
+		trials: DefaultTrials,
+		maxShrinks: DefaultMaxShrinks,
+	}
+}
+
+// Sets the seed of the random source passed to the generator, so that
+// every run generates the same inputs.  Without a seed, each run uses
+// a different seed (which is reported with any counterexample, so that
+// a failure can be reproduced).
+func (self *Property) WithSeed(seed int64) *Property {
+	self.seed, self.seeded = seed, true
+	return self
+}
+
+// Sets the number of inputs to generate.
+func (self *Property) WithTrials(trials int) *Property {
+	self.trials = trials
+	return self
+}
+
+// Sets the maximum number of candidate values to try while shrinking a
+// counterexample (zero disables shrinking).
+func (self *Property) WithMaxShrinks(maxShrinks int) *Property {
+	self.maxShrinks = maxShrinks
+	return self
+}
+
+// Implements fmt.Stringer.
+func (self *Property) String() string {
+	return fmt.Sprintf("ForAll[%v](%v)", self.generator, self.matcher)
+}
+
+// Generates inputs and checks (using the given Asserter) that each one
+// is matched.  On the first input that is not, the input is shrunk to
+// a minimal counterexample, which is reported along with the Result
+// of the matcher on it.
+func (self *Property) Check(we asserter.Asserter) {
+	we.CheckThat(self, Holds())
+}
+
+// Matches a *Property if the property's matcher matches every input
+// generated for it.  On a mismatch, the cause is the Result of the
+// matcher on the minimal counterexample.
+func Holds() *base.Matcher {
+	match := func(property *Property) *base.Result {
+		seed := property.seed
+		if !property.seeded {
+			seed = time.Nanoseconds()
+		}
+		random := rand.New(rand.NewSource(seed))
+		for trial := 1; trial <= property.trials; trial++ {
+			value := property.generator.Generate(random)
+			result := property.test(value)
+			if result.Matched() {
+				continue
+			}
+			minimal, minimalResult, steps := property.shrink(value, result)
+			if steps == 0 {
+				return base.NewResultf(false,
+					"falsified on trial %v of %v (seed %v) by %#v",
+					trial, property.trials, seed, value).
+					WithCauses(result)
+			}
+			return base.NewResultf(false,
+				"falsified on trial %v of %v (seed %v) by %#v, shrunk in %v steps from %#v",
+				trial, property.trials, seed, minimal, steps, value).
+				WithCauses(minimalResult)
+		}
+		return base.NewResultf(true,
+			"held for %v trials (seed %v)", property.trials, seed)
+	}
+	return base.NewMatcherf(match, "Holds").
+		WithStructure("Holds", nil)
+}
+
+// Applies the property's matcher to the value, converting any panic
+// into a Result that carries the recovered value.
+func (self *Property) test(value interface{}) (result *base.Result) {
+	defer func() {
+		if x := recover(); x != nil {
+			result = base.NewPanicResult(base.CapturePanic(x, 0)).
+				WithMatcherAndValue(self.matcher, value)
+		}
+	}()
+	return self.matcher.Match(value)
+}
+
+// Repeatedly replaces the counterexample with the first simpler value
+// that also fails to match, until no simpler value fails (or the limit
+// on candidates is reached).  Returns the simplest counterexample found,
+// its Result, and the number of times it was replaced.
+func (self *Property) shrink(value interface{}, result *base.Result) (interface{}, *base.Result, int) {
+	shrinker, ok := self.generator.(Shrinker)
+	if !ok {
+		shrinker = _DefaultShrinker{}
+	}
+	steps, tries := 0, 0
+	for tries < self.maxShrinks {
+		shrunk := false
+		for _, candidate := range shrinker.Shrink(value) {
+			if tries >= self.maxShrinks {
+				break
+			}
+			tries++
+			if candidateResult := self.test(candidate); !candidateResult.Matched() {
+				value, result = candidate, candidateResult
+				steps++
+				shrunk = true
+				break
+			}
+		}
+		if !shrunk {
+			break
+		}
+	}
+	return value, result, steps
+}
+
+type _DefaultShrinker struct{}
+
+func (self _DefaultShrinker) Shrink(value interface{}) []interface{} {
+	return Shrink(value)
+}
+
+// Returns values simpler than the given value, most aggressively
+// simplified first:
+//    - integers move toward zero (zero, then half, then one step closer),
+//    - strings are truncated (to empty, then half, then one rune shorter),
+//    - slices lose elements (all, then half, then each single element).
+// Values of other kinds are not simplified.
+func Shrink(value interface{}) []interface{} {
+	var candidates []interface{}
+	switch v := reflect.NewValue(value).(type) {
+	case *reflect.IntValue:
+		for _, x := range _TowardZero(v.Get()) {
+			shrunk := reflect.MakeZero(v.Type()).(*reflect.IntValue)
+			shrunk.Set(x)
+			candidates = append(candidates, shrunk.Interface())
+		}
+	case *reflect.UintValue:
+		n := v.Get()
+		for _, x := range []uint64{0, n / 2, n - 1} {
+			if x < n {
+				shrunk := reflect.MakeZero(v.Type()).(*reflect.UintValue)
+				shrunk.Set(x)
+				candidates = _AppendNew(candidates, shrunk.Interface())
+			}
+		}
+	case *reflect.StringValue:
+		runes := []int(v.Get())
+		for _, n := range []int{0, len(runes) / 2, len(runes) - 1} {
+			if n >= 0 && n < len(runes) {
+				shrunk := reflect.MakeZero(v.Type()).(*reflect.StringValue)
+				shrunk.Set(string(runes[:n]))
+				candidates = _AppendNew(candidates, shrunk.Interface())
+			}
+		}
+	case *reflect.SliceValue:
+		if v.IsNil() {
+			break
+		}
+		n := v.Len()
+		if n > 0 {
+			candidates = append(candidates, _SubSlice(v, 0, 0).Interface())
+		}
+		if n > 1 {
+			candidates = append(candidates,
+				_SubSlice(v, 0, n / 2).Interface(),
+				_SubSlice(v, n / 2, n).Interface())
+		}
+		if n > 2 {
+			for i := 0; i < n; i++ {
+				candidates = append(candidates, _Without(v, i).Interface())
+			}
+		}
+	}
+	return candidates
+}
+
+// Returns the values between zero and n (exclusive) that n shrinks to:
+// zero, half of n, and one step closer to zero than n.
+func _TowardZero(n int64) []int64 {
+	var values []int64
+	for _, x := range []int64{0, n / 2, n - _Sign(n)} {
+		if x != n && (len(values) == 0 || values[len(values)-1] != x) {
+			values = append(values, x)
+		}
+	}
+	return values
+}
+
+func _Sign(n int64) int64 {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
+
+// Appends the value unless it equals the last value in the slice.
+func _AppendNew(values []interface{}, value interface{}) []interface{} {
+	if len(values) > 0 && values[len(values)-1] == value {
+		return values
+	}
+	return append(values, value)
+}
+
+// Returns a copy of the elements of the slice from i up to j.
+func _SubSlice(slice *reflect.SliceValue, i, j int) *reflect.SliceValue {
+	shrunk := reflect.MakeSlice(slice.Type().(*reflect.SliceType), j - i, j - i)
+	for k := i; k < j; k++ {
+		shrunk.Elem(k - i).SetValue(slice.Elem(k))
+	}
+	return shrunk
+}
+
+// Returns a copy of the slice without its i'th element.
+func _Without(slice *reflect.SliceValue, i int) *reflect.SliceValue {
+	n := slice.Len()
+	shrunk := reflect.MakeSlice(slice.Type().(*reflect.SliceType), n - 1, n - 1)
+	for k := 0; k < n; k++ {
+		if k < i {
+			shrunk.Elem(k).SetValue(slice.Elem(k))
+		} else if k > i {
+			shrunk.Elem(k - 1).SetValue(slice.Elem(k))
+		}
+	}
+	return shrunk
+}
@@ -0,0 +1,169 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package property
+
+import (
+	"bytes"
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	. "github.com/rdrdr/hamcrest/core"
+	"github.com/rdrdr/hamcrest/slices"
+	"github.com/rdrdr/hamcrest/strings"
+	"rand"
+	"testing"
+)
+
+var Matched = base.Matched()
+var DidNotMatch = base.DidNotMatch()
+
+var ints = GeneratorFunc(func(random *rand.Rand) interface{} {
+	return random.Intn(2000) - 1000
+})
+
+var words = GeneratorFunc(func(random *rand.Rand) interface{} {
+	runes := make([]int, random.Intn(20))
+	for i := range runes {
+		runes[i] = 'a' + random.Intn(26)
+	}
+	return string(runes)
+})
+
+func Test_ForAll_holds(t *testing.T) {
+	we := asserter.Using(t)
+	square := Applying(func(n int) int { return n * n }, "Square")
+	ForAll(ints, square(GreaterThanOrEqualTo(0))).Check(we)
+	we.CheckThat(ForAll(ints, LessThan(1000)).WithTrials(50), Holds())
+}
+
+func Test_ForAll_shrinksInts(t *testing.T) {
+	we := asserter.Using(t)
+	property := ForAll(ints, LessThan(100)).WithSeed(1).WithTrials(1000)
+	result := Holds().Match(property)
+	we.CheckThat(result, DidNotMatch)
+	we.CheckThat(result.String(), strings.Contains("by 100, shrunk"))
+	we.CheckThat(result.String(), strings.Contains("(seed 1)"))
+	we.AssertThat(len(result.Causes()), EqualTo(1))
+	we.CheckThat(result.Causes()[0].Value(), EqualTo(100))
+	we.CheckThat(result.Causes()[0].Matcher(), EqualTo(property.matcher))
+}
+
+func Test_ForAll_shrinksStrings(t *testing.T) {
+	we := asserter.Using(t)
+	property := ForAll(words, Not(strings.Contains("q"))).WithSeed(7).WithTrials(1000)
+	result := Holds().Match(property)
+	we.AssertThat(result, DidNotMatch)
+	shrunk := result.Causes()[0].Value().(string)
+	we.CheckThat(shrunk, strings.HasSuffix("q"))
+}
+
+func Test_ForAll_reportsThroughAsserter(t *testing.T) {
+	we := asserter.Using(t)
+	var log bytes.Buffer
+	checker := asserter.UsingWriter(&log)
+	ForAll(ints, LessThan(100)).WithSeed(1).WithTrials(1000).Check(checker)
+	we.CheckThat(checker.Failed(), True().Comment("property was falsified"))
+	we.CheckThat(log.String(), strings.Contains("DID NOT MATCH input: 100"))
+}
+
+func Test_ForAll_seedIsReproducible(t *testing.T) {
+	we := asserter.Using(t)
+	var first, second []interface{}
+	record := func(values *[]interface{}) Generator {
+		return GeneratorFunc(func(random *rand.Rand) interface{} {
+			value := random.Int()
+			*values = append(*values, value)
+			return value
+		})
+	}
+	ForAll(record(&first), Anything()).WithSeed(42).WithTrials(10).Check(we)
+	ForAll(record(&second), Anything()).WithSeed(42).WithTrials(10).Check(we)
+	we.AssertThat(len(first), EqualTo(10))
+	we.AssertThat(len(second), EqualTo(10))
+	for i := range first {
+		we.CheckThat(second[i], EqualTo(first[i]))
+	}
+}
+
+func Test_ForAll_panics(t *testing.T) {
+	we := asserter.Using(t)
+	explode := Applying(func(n int) int {
+		if n > 10 {
+			panic("too big")
+		}
+		return n
+	}, "Explode")
+	result := Holds().Match(ForAll(ints, explode(Anything())).WithSeed(3))
+	we.AssertThat(result, DidNotMatch)
+	we.CheckThat(result.Causes()[0].Value(), EqualTo(11))
+	we.CheckNonNil(result.Causes()[0].Panic())
+}
+
+func Test_ForAll_withoutShrinking(t *testing.T) {
+	we := asserter.Using(t)
+	result := Holds().Match(ForAll(ints, LessThan(100)).WithSeed(1).WithMaxShrinks(0))
+	we.AssertThat(result, DidNotMatch)
+	we.CheckThat(result.String(), Not(strings.Contains("shrunk")))
+}
+
+type _Evens struct{}
+
+func (self _Evens) Generate(random *rand.Rand) interface{} {
+	return 2 * random.Intn(1000)
+}
+
+func (self _Evens) Shrink(value interface{}) []interface{} {
+	n := value.(int)
+	if n == 0 {
+		return nil
+	}
+	return []interface{}{n - 2}
+}
+
+func Test_ForAll_usesGeneratorShrinker(t *testing.T) {
+	we := asserter.Using(t)
+	result := Holds().Match(ForAll(_Evens{}, LessThan(1501)).WithSeed(5))
+	we.AssertThat(result, DidNotMatch)
+	we.CheckThat(result.Causes()[0].Value(), EqualTo(1502))
+}
+
+func Test_Holds_structure(t *testing.T) {
+	we := asserter.Using(t)
+	var names []string
+	Not(Holds()).Inspect(func(m *base.Matcher) bool {
+		names = append(names, m.Name())
+		return true
+	})
+	we.CheckThat(names, DeepEqualTo([]string{"Not", "Holds"}))
+}
+
+func Test_Shrink(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(Shrink(10), slices.ToLen(EqualTo(3)))
+	we.CheckThat(Shrink(10)[0], EqualTo(0))
+	we.CheckThat(Shrink(10)[1], EqualTo(5))
+	we.CheckThat(Shrink(10)[2], EqualTo(9))
+	we.CheckThat(Shrink(-3)[2], EqualTo(-2))
+	we.CheckThat(Shrink(int8(1)), slices.ToLen(EqualTo(1)))
+	we.CheckThat(Shrink(int8(1))[0], EqualTo(int8(0)))
+	we.CheckThat(Shrink(0), slices.Empty())
+	we.CheckThat(Shrink(uint(4))[2], EqualTo(uint(3)))
+
+	we.CheckThat(Shrink("abcd"), slices.ToLen(EqualTo(3)))
+	we.CheckThat(Shrink("abcd")[1], EqualTo("ab"))
+	we.CheckThat(Shrink("abcd")[2], EqualTo("abc"))
+	we.CheckThat(Shrink("héllo")[2], EqualTo("héll"))
+	we.CheckThat(Shrink(""), slices.Empty())
+
+	shrunk := Shrink([]int{1, 2, 3})
+	we.AssertThat(shrunk, slices.ToLen(EqualTo(6)))
+	we.CheckThat(shrunk[0], slices.Empty())
+	we.CheckThat(shrunk[1], slices.ToLen(EqualTo(1)))
+	we.CheckThat(shrunk[2], slices.ToLen(EqualTo(2)))
+	we.CheckThat(shrunk[4].([]int)[1], EqualTo(3))
+	we.CheckThat(Shrink([]int{}), slices.Empty())
+
+	we.CheckThat(Shrink(1.5), slices.Empty())
+	we.CheckThat(Shrink(nil), slices.Empty())
+}
@@ -20,7 +20,8 @@ func ToType(matcher *base.Matcher) *base.Matcher {
 			"reflect.Typeof() returned %v", actualType).
 			WithCauses(result)
 	}
-	return base.NewMatcherf(match, "ToType(%v)", matcher)
+	return base.NewMatcherf(match, "ToType(%v)", matcher).
+		WithStructure("ToType", nil, matcher)
 }
 
 // Returns a matcher that matches any object with the same
@@ -42,7 +43,8 @@ func _TypeMatcher(name string, expectedType reflect.Type) *base.Matcher {
 		return base.NewResultf(false,
 			"was a %v, not a %v", actualType, expectedType)
 	}
-	return base.NewMatcherf(match, "Typeof[%v]", expectedType)
+	return base.NewMatcherf(match, "Typeof[%v]", expectedType).
+		WithStructure("Typeof", []interface{}{expectedType})
 }
 
 var (
@@ -174,7 +176,8 @@ func ArrayTypeOf(elementTypeMatcher *base.Matcher) *base.Matcher {
 		return base.NewResultf(false,
 			"was of type %T, not an ArrayType", actual)
 	}
-	return base.NewMatcherf(match, "ArrayTypeOf(%v)", elementTypeMatcher)
+	return base.NewMatcherf(match, "ArrayTypeOf(%v)", elementTypeMatcher).
+		WithStructure("ArrayTypeOf", nil, elementTypeMatcher)
 }
 
 // Returns a new matcher that, on any input that is an array, extracts
@@ -195,7 +198,8 @@ func ArrayOf(elementTypeMatcher *base.Matcher) *base.Matcher {
 		}
 		return base.NewResultf(false, "was of type %T, not an array", actual)
 	}
-	return base.NewMatcherf(match, "ArrayOf(%v)", elementTypeMatcher)
+	return base.NewMatcherf(match, "ArrayOf(%v)", elementTypeMatcher).
+		WithStructure("ArrayOf", nil, elementTypeMatcher)
 }
 
 // Returns a new matcher that, on any input that is a *reflect.ChanType,
@@ -216,7 +220,8 @@ func ChannelTypeOf(elementTypeMatcher *base.Matcher) *base.Matcher {
 		return base.NewResultf(false,
 			"was of type %T, not a *reflect.ChanType", actual)
 	}
-	return base.NewMatcherf(match, "ChannelTypeOf(%v)", elementTypeMatcher)
+	return base.NewMatcherf(match, "ChannelTypeOf(%v)", elementTypeMatcher).
+		WithStructure("ChannelTypeOf", nil, elementTypeMatcher)
 }
 
 // Returns a new matcher that, on any input that is a channel, extracts
@@ -237,7 +242,8 @@ func ChannelOf(elementTypeMatcher *base.Matcher) *base.Matcher {
 		}
 		return base.NewResultf(false, "was of type %T, not a channel", actual)
 	}
-	return base.NewMatcherf(match, "ChannelOf(%v)", elementTypeMatcher)
+	return base.NewMatcherf(match, "ChannelOf(%v)", elementTypeMatcher).
+		WithStructure("ChannelOf", nil, elementTypeMatcher)
 }
 
 // Returns a new matcher that, on any input that is a *reflect.SliceType,
@@ -257,7 +263,8 @@ func SliceTypeOf(elementTypeMatcher *base.Matcher) *base.Matcher {
 		}
 		return base.NewResultf(false, "was of type %T, not a slice", actual)
 	}
-	return base.NewMatcherf(match, "SliceTypeOf(%v)", elementTypeMatcher)
+	return base.NewMatcherf(match, "SliceTypeOf(%v)", elementTypeMatcher).
+		WithStructure("SliceTypeOf", nil, elementTypeMatcher)
 }
 
 // Returns a new matcher that, on any input that is an array, extracts
@@ -278,7 +285,8 @@ func SliceOf(elementTypeMatcher *base.Matcher) *base.Matcher {
 		}
 		return base.NewResultf(false, "was of type %T, not a slice", actual)
 	}
-	return base.NewMatcherf(match, "SliceOf(%v)", elementTypeMatcher)
+	return base.NewMatcherf(match, "SliceOf(%v)", elementTypeMatcher).
+		WithStructure("SliceOf", nil, elementTypeMatcher)
 }
 
 // Returns a new matcher that, on any input that is a *reflect.MapType,
@@ -307,7 +315,8 @@ func MapTypeOf(keyTypeMatcher, elementTypeMatcher *base.Matcher) *base.Matcher {
 		return base.NewResultf(false, "was of type %T, not a MapType", actual)
 	}
 	return base.NewMatcherf(match,
-		"MapTypeOf(%v, %v)", keyTypeMatcher, elementTypeMatcher)
+		"MapTypeOf(%v, %v)", keyTypeMatcher, elementTypeMatcher).
+		WithStructure("MapTypeOf", nil, keyTypeMatcher, elementTypeMatcher)
 }
 
 // Returns a new matcher that, on any input that is a map, extracts the
@@ -345,7 +354,8 @@ func PtrTypeTo(pointeeTypeMatcher *base.Matcher) *base.Matcher {
 			"was type %T, not a PtrType", actual)
 	}
 	return base.NewMatcherf(match,
-		"PtrTypeTo(%v)", pointeeTypeMatcher)
+		"PtrTypeTo(%v)", pointeeTypeMatcher).
+		WithStructure("PtrTypeTo", nil, pointeeTypeMatcher)
 }
 
 // Returns a new matcher that, on any input that is a pointer, extracts the
@@ -368,9 +378,42 @@ func PtrTo(pointeeTypeMatcher *base.Matcher) *base.Matcher {
 			"was type %T, not a pointer", actual)
 	}
 	return base.NewMatcherf(match,
-		"PtrTo(%v)", pointeeTypeMatcher)
+		"PtrTo(%v)", pointeeTypeMatcher).
+		WithStructure("PtrTo", nil, pointeeTypeMatcher)
 }
 
-
-
-
+// Returns a new matcher that, on any input that is a struct or a
+// pointer to a struct, extracts the value of the named field and
+// matches it against the given matcher.  For example:
+//    HasField("ID", EqualTo(5))
+//
+// If the given input is not a struct (or a pointer to one) with a field
+// of that name, this fails to match.
+func HasField(name string, matcher *base.Matcher) *base.Matcher {
+	match := func(actual interface{}) *base.Result {
+		value := reflect.NewValue(actual)
+		if ptrValue, ok := value.(*reflect.PtrValue); ok {
+			if ptrValue.IsNil() {
+				return base.NewResultf(false, "was a nil %T", actual)
+			}
+			value = ptrValue.Elem()
+		}
+		structValue, ok := value.(*reflect.StructValue)
+		if !ok {
+			return base.NewResultf(false,
+				"was type %T, not a struct or pointer to struct", actual)
+		}
+		field := structValue.FieldByName(name)
+		if field == nil {
+			return base.NewResultf(false, "%T has no field named %v", actual, name)
+		}
+		fieldValue := field.Interface()
+		result := matcher.Match(fieldValue)
+		return base.NewResultf(result.Matched(),
+			"field %v was %v", name, fieldValue).
+			WithError(result.Err()).
+			WithCauses(result)
+	}
+	return base.NewMatcherf(match, "HasField[%v](%v)", name, matcher).
+		WithStructure("HasField", []interface{}{name}, matcher)
+}
@@ -368,3 +368,29 @@ func Test_PtrTypeTo(t *testing.T) {
 	we.CheckThat(intPtrPtr, ToType(Is(PtrTypeTo(PtrTypeTo(IntType())))))
 }
 
+type _Record struct {
+	ID int
+	Name string
+}
+
+func Test_HasField(t *testing.T) {
+	we := asserter.Using(t)
+	record := _Record{ID: 5, Name: "five"}
+	we.CheckThat(HasField("ID", EqualTo(5)).Match(record), Matched)
+	we.CheckThat(HasField("ID", EqualTo(5)).Match(&record), Matched)
+	we.CheckThat(HasField("Name", EqualTo("six")).Match(&record), DidNotMatch)
+	we.CheckThat(HasField("Missing", Anything()).Match(record), DidNotMatch)
+	we.CheckThat(HasField("ID", Anything()).Match(5), DidNotMatch)
+	var nilRecord *_Record
+	we.CheckThat(HasField("ID", Anything()).Match(nilRecord), DidNotMatch)
+}
+
+func Test_HasField_structure(t *testing.T) {
+	we := asserter.Using(t)
+	inner := EqualTo(5)
+	matcher := HasField("ID", inner)
+	we.CheckThat(matcher.Name(), EqualTo("HasField"))
+	we.CheckThat(matcher.Params(), DeepEqualTo([]interface{}{"ID"}))
+	we.CheckThat(matcher.Children()[0] == inner, Is(True()))
+	we.CheckThat(MapTypeOf(StringType(), IntType()).Children()[1] == IntType(), Is(True()))
+}
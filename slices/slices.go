@@ -38,7 +38,8 @@ func AnyElem(matcher *base.Matcher) *base.Matcher {
 		}
 		return matcher.Match(v)
 	}
-	return base.NewMatcherf(match, "AnyElement[%v]", matcher)
+	return base.NewMatcherf(match, "AnyElement[%v]", matcher).
+		WithStructure("AnyElement", nil, matcher)
 }
 
 // Returns a matcher that matches on any array or slice input value
@@ -72,7 +73,8 @@ func EachElem(matcher *base.Matcher) *base.Matcher {
 		return base.NewResultf(true,
 			"Matched all of the %v elements", n)
 	}
-	return base.NewMatcherf(match, "EveryElement[%v]", matcher)
+	return base.NewMatcherf(match, "EveryElement[%v]", matcher).
+		WithStructure("EveryElement", nil, matcher)
 }
 
 type _HasLen interface { Len() int }
@@ -89,7 +91,9 @@ func ToLen(matcher *base.Matcher) *base.Matcher {
 		return base.NewResultf(false,
 			"Can't determine Len() for %T", actual)
 	}
-	return base.NewMatcherf(match, "ToLen[%v]", matcher)
+	return base.NewMatcherf(match, "ToLen[%v]", matcher).
+		WithStructure("ToLen", nil, matcher).
+		WithNegationf("has a length that %v", matcher.Negation())
 }
 
 // Matches any input element that is an empty array, slice, or map.
@@ -103,6 +107,8 @@ func Empty() *base.Matcher {
 		}
 		return base.NewResultf(false, "Can't determine length of type %T", actual)
 	}
-	return base.NewMatcherf(match, "Empty")
+	return base.NewMatcherf(match, "Empty").
+		WithStructure("Empty", nil).
+		WithNegationf("is not empty")
 }
 
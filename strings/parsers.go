@@ -0,0 +1,185 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strings
+
+import (
+	"github.com/rdrdr/hamcrest/base"
+	"http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Applies the given matcher to the int parsed from the input string
+// (using strconv.Atoi).  If the input cannot be parsed, the matcher
+// fails to match, and the Result describes the parse error.
+func ParsedAsInt(matcher *base.Matcher) *base.Matcher {
+	match := func(s string) *base.Result {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return base.NewResultf(false, "%v", err)
+		}
+		return _Parsed(matcher, i, "int")
+	}
+	return base.NewMatcherf(match, "ParsedAsInt(%v)", matcher).
+		WithStructure("ParsedAsInt", nil, matcher)
+}
+
+// Applies the given matcher to the float64 parsed from the input string
+// (using strconv.Atof64).  If the input cannot be parsed, the matcher
+// fails to match, and the Result describes the parse error.
+func ParsedAsFloat(matcher *base.Matcher) *base.Matcher {
+	match := func(s string) *base.Result {
+		f, err := strconv.Atof64(s)
+		if err != nil {
+			return base.NewResultf(false, "%v", err)
+		}
+		return _Parsed(matcher, f, "float64")
+	}
+	return base.NewMatcherf(match, "ParsedAsFloat(%v)", matcher).
+		WithStructure("ParsedAsFloat", nil, matcher)
+}
+
+// Applies the given matcher to the bool parsed from the input string
+// (using strconv.Atob, which accepts "1", "t", "T", "TRUE", "true",
+// "True", "0", "f", "F", "FALSE", "false" and "False").  If the input
+// cannot be parsed, the matcher fails to match, and the Result
+// describes the parse error.
+func ParsedAsBool(matcher *base.Matcher) *base.Matcher {
+	match := func(s string) *base.Result {
+		b, err := strconv.Atob(s)
+		if err != nil {
+			return base.NewResultf(false, "%v", err)
+		}
+		return _Parsed(matcher, b, "bool")
+	}
+	return base.NewMatcherf(match, "ParsedAsBool(%v)", matcher).
+		WithStructure("ParsedAsBool", nil, matcher)
+}
+
+// Units accepted by ParsedAsDuration, in nanoseconds.
+const (
+	_Nanosecond int64 = 1
+	_Microsecond = 1000 * _Nanosecond
+	_Millisecond = 1000 * _Microsecond
+	_Second = 1000 * _Millisecond
+	_Minute = 60 * _Second
+	_Hour = 60 * _Minute
+)
+
+var _DurationUnits = map[string]int64{
+	"ns": _Nanosecond,
+	"us": _Microsecond,
+	"µs": _Microsecond,
+	"ms": _Millisecond,
+	"s": _Second,
+	"m": _Minute,
+	"h": _Hour,
+}
+
+// Applies the given matcher to the duration parsed from the input
+// string, as an int64 number of nanoseconds.  A duration is an
+// optionally-signed sequence of decimal numbers, each with an optional
+// fraction and a unit suffix ("ns", "us" or "µs", "ms", "s", "m" or
+// "h"), such as "300ms", "1.5h" or "-2h45m".  For example:
+//    ParsedAsDuration(EqualTo(int64(90e9)))
+// matches "1m30s" and "90s".  If the input cannot be parsed, the matcher
+// fails to match, and the Result describes the parse error.
+func ParsedAsDuration(matcher *base.Matcher) *base.Matcher {
+	match := func(s string) *base.Result {
+		ns, err := _ParseDuration(s)
+		if err != nil {
+			return base.NewResultf(false, "%v", err)
+		}
+		return _Parsed(matcher, ns, "duration (in ns)")
+	}
+	return base.NewMatcherf(match, "ParsedAsDuration(%v)", matcher).
+		WithStructure("ParsedAsDuration", nil, matcher)
+}
+
+func _ParseDuration(s string) (int64, os.Error) {
+	invalid := os.NewError("invalid duration \"" + s + "\"")
+	rest := s
+	sign := int64(1)
+	if rest != "" && (rest[0] == '-' || rest[0] == '+') {
+		if rest[0] == '-' {
+			sign = -1
+		}
+		rest = rest[1:]
+	}
+	if rest == "0" {
+		return 0, nil
+	}
+	if rest == "" {
+		return 0, invalid
+	}
+	total := 0.0
+	for rest != "" {
+		i := 0
+		for i < len(rest) && (rest[i] == '.' || ('0' <= rest[i] && rest[i] <= '9')) {
+			i++
+		}
+		if i == 0 {
+			return 0, invalid
+		}
+		number, err := strconv.Atof64(rest[:i])
+		if err != nil {
+			return 0, invalid
+		}
+		rest = rest[i:]
+		j := 0
+		for j < len(rest) && rest[j] != '.' && (rest[j] < '0' || '9' < rest[j]) {
+			j++
+		}
+		unit, ok := _DurationUnits[rest[:j]]
+		if !ok {
+			return 0, os.NewError("unknown unit \"" + rest[:j] +
+				"\" in duration \"" + s + "\"")
+		}
+		rest = rest[j:]
+		total += number * float64(unit)
+	}
+	return sign * int64(total + 0.5), nil
+}
+
+// Applies the given matcher to the *time.Time parsed from the input
+// string using the given layout (as for time.Parse).  For example:
+//    ParsedAsTime(time.RFC3339, Anything())
+// matches any RFC 3339 timestamp.  If the input cannot be parsed, the
+// matcher fails to match, and the Result describes the parse error.
+func ParsedAsTime(layout string, matcher *base.Matcher) *base.Matcher {
+	match := func(s string) *base.Result {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return base.NewResultf(false, "%v", err)
+		}
+		return _Parsed(matcher, t, "time")
+	}
+	return base.NewMatcherf(match, "ParsedAsTime[\"%v\"](%v)", layout, matcher).
+		WithStructure("ParsedAsTime", []interface{}{layout}, matcher)
+}
+
+// Applies the given matcher to the *http.URL parsed from the input
+// string (using http.ParseURL).  If the input cannot be parsed, the
+// matcher fails to match, and the Result describes the parse error.
+func ParsedAsURL(matcher *base.Matcher) *base.Matcher {
+	match := func(s string) *base.Result {
+		url, err := http.ParseURL(s)
+		if err != nil {
+			return base.NewResultf(false, "%v", err)
+		}
+		return _Parsed(matcher, url, "URL")
+	}
+	return base.NewMatcherf(match, "ParsedAsURL(%v)", matcher).
+		WithStructure("ParsedAsURL", nil, matcher)
+}
+
+// Applies the matcher to a successfully-parsed value.
+func _Parsed(matcher *base.Matcher, value interface{}, kind string) *base.Result {
+	result := matcher.Match(value)
+	return base.NewResultf(result.Matched(), "parsed as %v %v", kind, value).
+		WithError(result.Err()).
+		WithCauses(result)
+}
@@ -0,0 +1,65 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strings
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	. "github.com/rdrdr/hamcrest/core"
+	"testing"
+)
+
+func Test_ParsedAsInt(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ParsedAsInt(EqualTo(42)).Match("42"), Matched)
+	we.CheckThat(ParsedAsInt(EqualTo(42)).Match("-42"), DidNotMatch)
+	we.CheckThat(ParsedAsInt(Anything()).Match("forty-two"), DidNotMatch.
+		Comment("unparseable input fails to match"))
+	we.CheckThat(ParsedAsInt(Anything()).Match("4.2"), DidNotMatch)
+}
+
+func Test_ParsedAsFloat(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ParsedAsFloat(EqualTo(2.5)).Match("2.5"), Matched)
+	we.CheckThat(ParsedAsFloat(EqualTo(2.5)).Match("25e-1"), Matched)
+	we.CheckThat(ParsedAsFloat(Anything()).Match("2,5"), DidNotMatch)
+}
+
+func Test_ParsedAsBool(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ParsedAsBool(True()).Match("true"), Matched)
+	we.CheckThat(ParsedAsBool(True()).Match("T"), Matched)
+	we.CheckThat(ParsedAsBool(False()).Match("0"), Matched)
+	we.CheckThat(ParsedAsBool(Anything()).Match("yes"), DidNotMatch)
+}
+
+func Test_ParsedAsDuration(t *testing.T) {
+	we := asserter.Using(t)
+	ninetySeconds := ParsedAsDuration(EqualTo(90 * _Second))
+	we.CheckThat(ninetySeconds.Match("90s"), Matched)
+	we.CheckThat(ninetySeconds.Match("1m30s"), Matched)
+	we.CheckThat(ninetySeconds.Match("1.5m"), Matched)
+	we.CheckThat(ninetySeconds.Match("90000ms"), Matched)
+	we.CheckThat(ninetySeconds.Match("91s"), DidNotMatch)
+	we.CheckThat(ParsedAsDuration(EqualTo(-2 * _Microsecond)).Match("-2µs"), Matched)
+	we.CheckThat(ParsedAsDuration(EqualTo(int64(0))).Match("0"), Matched)
+	we.CheckThat(ParsedAsDuration(Anything()).Match("90"), DidNotMatch.
+		Comment("missing unit"))
+	we.CheckThat(ParsedAsDuration(Anything()).Match("90 s"), DidNotMatch)
+	we.CheckThat(ParsedAsDuration(Anything()).Match("1d"), DidNotMatch)
+	we.CheckThat(ParsedAsDuration(Anything()).Match(""), DidNotMatch)
+}
+
+func Test_ParsedAsTime(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ParsedAsTime("2006-01-02", Not(Nil())).Match("2011-03-15"), Matched)
+	we.CheckThat(ParsedAsTime("2006-01-02", Anything()).Match("15/03/2011"), DidNotMatch)
+}
+
+func Test_ParsedAsURL(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ParsedAsURL(ToString(EqualTo("http://example.com/a?b=c"))).
+		Match("http://example.com/a?b=c"), Matched)
+	we.CheckThat(ParsedAsURL(Anything()).Match("http://[::1"), DidNotMatch)
+}
@@ -0,0 +1,780 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strings
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/base"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// Matches strings that contain the given regexp pattern, using
+// the same syntax as the standard regexp package.
+//
+// If the pattern is invalid, the returned matcher reports the compile
+// error as a matcher error (see base.Result.Err) on every input.
+func HasPattern(pattern string) *base.Matcher {
+	name := _NewName("HasPattern", []interface{}{pattern},
+		"HasPattern[\"%v\"]", pattern)
+	re, err := _Compile(pattern)
+	if err != nil {
+		return base.NewErrorMatcherf(err, "%v", name).
+			WithStructure(name.function, name.params)
+	}
+	return _HasPattern(re, name)
+}
+
+// Variant of HasPattern() that uses a precompiled regexp.
+func HasRegexp(re *regexp.Regexp) *base.Matcher {
+	name := _NewName("HasRegexp", []interface{}{re},
+		"HasRegexp[\"%v\"]", _Source(re))
+	if re == nil {
+		return base.NewErrorMatcherf(_NilRegexp, "%v", name).
+			WithStructure(name.function, name.params)
+	}
+	return _HasPattern(re, name)
+}
+
+func _HasPattern(re *regexp.Regexp, name *_Name) *base.Matcher {
+	pattern := re.String()
+	match := func (s string) *base.Result {
+		if found := re.FindStringIndex(s); found != nil {
+			start, end := found[0], found[1]
+			return base.NewResultf(true,
+				"pattern \"%v\" matched substring[%v:%v]=\"%v\"",
+				pattern, start, end, s[start:end])
+		}
+		return base.NewResultf(false,
+			"pattern \"%v\" not found in \"%v\"", pattern, s)
+	}
+	return base.NewMatcherf(match, "%v", name).
+		WithStructure(name.function, name.params)
+}
+
+// Matches strings that match the given regexp pattern in their
+// entirety, rather than merely containing a match (as HasPattern
+// does).  For example, MatchesPattern(`\d+`) matches "2011" but not
+// "in 2011".
+//
+// If the pattern is invalid, the returned matcher reports the compile
+// error as a matcher error (see base.Result.Err) on every input.
+func MatchesPattern(pattern string) *base.Matcher {
+	name := _NewName("MatchesPattern", []interface{}{pattern},
+		"MatchesPattern[\"%v\"]", pattern)
+	re, err := _Compile(pattern)
+	if err != nil {
+		return base.NewErrorMatcherf(err, "%v", name).
+			WithStructure(name.function, name.params)
+	}
+	return _MatchesPattern(re, name)
+}
+
+// Variant of MatchesPattern() that uses a precompiled regexp.
+func MatchesRegexp(re *regexp.Regexp) *base.Matcher {
+	name := _NewName("MatchesRegexp", []interface{}{re},
+		"MatchesRegexp[\"%v\"]", _Source(re))
+	if re == nil {
+		return base.NewErrorMatcherf(_NilRegexp, "%v", name).
+			WithStructure(name.function, name.params)
+	}
+	return _MatchesPattern(re, name)
+}
+
+func _MatchesPattern(re *regexp.Regexp, name *_Name) *base.Matcher {
+	pattern := re.String()
+	// Anchoring the whole pattern (rather than comparing the bounds of
+	// the leftmost match) is necessary for alternations like "a|ab".
+	anchored, err := _Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return base.NewErrorMatcherf(err, "%v", name).
+			WithStructure(name.function, name.params)
+	}
+	match := func (s string) *base.Result {
+		if anchored.MatchString(s) {
+			return base.NewResultf(true,
+				"pattern \"%v\" matched all of \"%v\"", pattern, s)
+		}
+		if found := re.FindStringIndex(s); found != nil {
+			start, end := found[0], found[1]
+			return base.NewResultf(false,
+				"pattern \"%v\" matched only substring[%v:%v]=\"%v\" of \"%v\"",
+				pattern, start, end, s[start:end], s)
+		}
+		return base.NewResultf(false,
+			"pattern \"%v\" not found in \"%v\"", pattern, s)
+	}
+	return base.NewMatcherf(match, "%v", name).
+		WithStructure(name.function, name.params)
+}
+
+// Deprecated and unused:  kept only so that code that refers to it
+// still compiles.
+type WithPatternClause struct {
+	re *regexp.Regexp
+	group int
+}
+
+// Returns a short-circuiting function that applies the matcher to each
+// occurrence of the pattern in an input string, until a failing pattern
+// is found (in which case the output matcher fails to match) or all
+// matching instances are exhausted (in which case the output matcher
+// successfully matches).
+//
+// For example:
+//    EachPattern("q.")(EqualTo("qu"))
+// would match:
+//    "quick quack mq" (two matches of "q.", both equal to "qu")
+// but not:
+//    "quick qs for mq" (two matches of "q.", second is not "qu")
+func EachPattern(pattern string) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("EachPattern", []interface{}{pattern},
+		"EachPattern[\"%v\"]", pattern)
+	re, err := _Compile(pattern)
+	if err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _EachPattern(re, name)
+}
+
+// Variant of EachPattern() that uses a precompiled regexp.
+func EachRegexp(re *regexp.Regexp) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("EachRegexp", []interface{}{re},
+		"EachRegexp[\"%v\"]", _Source(re))
+	if re == nil {
+		return _ErrorMatcherFunc(_NilRegexp, name)
+	}
+	return _EachPattern(re, name)
+}
+
+func _EachPattern(re *regexp.Regexp, name *_Name) func(matcher *base.Matcher) *base.Matcher  {
+	pattern := re.String()
+	return func(matcher *base.Matcher) *base.Matcher {
+		match := func(s string) *base.Result {
+			matches := re.FindAllStringIndex(s, -1)
+			if matches == nil {
+				return base.NewResultf(true,
+					"No occurrences of pattern \"%v\"", pattern)
+			}
+			for index, loc := range matches {
+				start, end := loc[0], loc[1]
+				substring := s[start:end]
+				result := matcher.Match(substring)
+				if !result.Matched() {
+					return base.NewResultf(false,
+						"did not match substring[%v:%v]=\"%v\", occurrence #%v (of %v) of pattern \"%v\"",
+						start, end, substring, index+1, len(matches), pattern).
+						WithError(result.Err()).
+						WithCauses(result)
+				}
+			}
+			return base.NewResultf(true,
+				"Matched every occurrence (all %v) of pattern \"%v\"",
+				len(matches), pattern)
+		}
+		return base.NewMatcherf(match, "%v[%v]", name, matcher).
+			WithStructure(name.function, name.params, matcher)
+	}
+}
+
+// Variant of EachPattern() that uses the given subgroup of the pattern.
+func EachPatternGroup(pattern string, group int) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("EachPatternGroup", []interface{}{pattern, group},
+		"EachPatternGroup[\"%v\", %v]", pattern, group)
+	re, err := _CompileWithGroup(pattern, group)
+	if err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _EachPatternGroup(re, group, name)
+}
+
+// Variant of EachPattern() that uses the subgroup of the pattern with
+// the given name, as in (?P<name>re).
+func EachPatternNamed(pattern string, groupName string) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("EachPatternNamed", []interface{}{pattern, groupName},
+		"EachPatternNamed[\"%v\", \"%v\"]", pattern, groupName)
+	re, group, err := _CompileWithGroupNamed(pattern, groupName)
+	if err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _EachPatternGroup(re, group, name)
+}
+
+// Variant of EachPatternGroup() that uses a precompiled regexp.
+func EachRegexpGroup(re *regexp.Regexp, group int) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("EachRegexpGroup", []interface{}{re, group},
+		"EachRegexpGroup[\"%v\", %v]", _Source(re), group)
+	if err := _CheckGroup(re, group); err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _EachPatternGroup(re, group, name)
+}
+
+// Variant of EachPatternNamed() that uses a precompiled regexp.
+func EachRegexpNamed(re *regexp.Regexp, groupName string) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("EachRegexpNamed", []interface{}{re, groupName},
+		"EachRegexpNamed[\"%v\", \"%v\"]", _Source(re), groupName)
+	group, err := _GroupNamed(re, groupName)
+	if err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _EachPatternGroup(re, group, name)
+}
+
+func _EachPatternGroup(re *regexp.Regexp, group int, name *_Name) func(matcher *base.Matcher) *base.Matcher  {
+	pattern := re.String()
+	return func(matcher *base.Matcher) *base.Matcher {
+		match := func(s string) *base.Result {
+			matches := re.FindAllStringSubmatchIndex(s, -1)
+			if matches == nil {
+				return base.NewResultf(true,
+					"No occurrences of pattern \"%v\"", pattern)
+			}
+			for index, loc := range matches {
+				substart, subend := loc[2*group], loc[2*group+1]
+				if substart < 0 {
+					continue // group did not participate in this occurrence
+				}
+				substring := s[substart:subend]
+				result := matcher.Match(substring)
+				if !result.Matched() {
+					start, end := loc[0], loc[1]
+					prefix, suffix := s[start:substart], s[subend:end]
+					return base.NewResultf(false,
+						"did not match substring[%v:%v], [%v:%v]=\"%v[%v]%v\", occurrence #%v (of %v) of pattern \"%v\"",
+						substart, subend, start, end,
+						prefix, substring, suffix, index+1, len(matches), pattern).
+						WithError(result.Err()).
+						WithCauses(result)
+				}
+			}
+			return base.NewResultf(true,
+				"Matched every occurrence (all %v) of pattern \"%v\", group %v",
+				len(matches), pattern, group)
+		}
+		return base.NewMatcherf(match, "%v[%v]", name, matcher).
+			WithStructure(name.function, name.params, matcher)
+	}
+}
+
+
+// Returns a short-circuiting function that applies the matcher to each
+// occurrence of the pattern in an input string, until a matching pattern
+// is found (in which case the matcher successfully matches) or all
+// matching instances are exhausted (in which case the output matcher
+// fails to match).
+//
+// For example:
+//    AnyPattern("x.")(EqualTo("xy"))
+// would match:
+//    "six sax are sexy" (three matches of "x.", third is "xy")
+// but not:
+//    "pox pix are pixelated" (three matches of "x.", none is "xy")
+func AnyPattern(pattern string) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("AnyPattern", []interface{}{pattern},
+		"AnyPattern[\"%v\"]", pattern)
+	re, err := _Compile(pattern)
+	if err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _AnyPattern(re, name)
+}
+
+// Variant of AnyPattern() that uses a precompiled regexp.
+func AnyRegexp(re *regexp.Regexp) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("AnyRegexp", []interface{}{re},
+		"AnyRegexp[\"%v\"]", _Source(re))
+	if re == nil {
+		return _ErrorMatcherFunc(_NilRegexp, name)
+	}
+	return _AnyPattern(re, name)
+}
+
+func _AnyPattern(re *regexp.Regexp, name *_Name) func(matcher *base.Matcher) *base.Matcher  {
+	pattern := re.String()
+	return func(matcher *base.Matcher) *base.Matcher {
+		match := func(s string) *base.Result {
+			matches := re.FindAllStringIndex(s, -1)
+			if matches == nil {
+				return base.NewResultf(false,
+					"No occurrences of pattern \"%v\"", pattern)
+			}
+
+			for index, loc := range matches {
+				start, end := loc[0], loc[1]
+				substring := s[start:end]
+				result := matcher.Match(substring)
+				if err := result.Err(); err != nil {
+					return base.NewResultf(false,
+						"could not apply matcher to substring[%v:%v]=\"%v\"",
+						start, end, substring).
+						WithError(err).
+						WithCauses(result)
+				}
+				if result.Matched() {
+					return base.NewResultf(true,
+						"matched substring[%v:%v]=\"%v\", occurrence #%v (of %v) of pattern \"%v\"",
+						start, end, substring, index+1, len(matches), pattern).
+						WithCauses(result)
+				}
+			}
+			return base.NewResultf(false,
+				"Did not match any occurrence (of %v) of pattern \"%v\"",
+				len(matches), pattern)
+		}
+		return base.NewMatcherf(match, "%v[%v]", name, matcher).
+			WithStructure(name.function, name.params, matcher)
+	}
+}
+
+
+// Variant of AnyPattern() that uses the given subgroup of the pattern.
+func AnyPatternGroup(pattern string, group int) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("AnyPatternGroup", []interface{}{pattern, group},
+		"AnyPatternGroup[\"%v\", %v]", pattern, group)
+	re, err := _CompileWithGroup(pattern, group)
+	if err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _AnyPatternGroup(re, group, name)
+}
+
+// Variant of AnyPattern() that uses the subgroup of the pattern with
+// the given name, as in (?P<name>re).
+func AnyPatternNamed(pattern string, groupName string) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("AnyPatternNamed", []interface{}{pattern, groupName},
+		"AnyPatternNamed[\"%v\", \"%v\"]", pattern, groupName)
+	re, group, err := _CompileWithGroupNamed(pattern, groupName)
+	if err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _AnyPatternGroup(re, group, name)
+}
+
+// Variant of AnyPatternGroup() that uses a precompiled regexp.
+func AnyRegexpGroup(re *regexp.Regexp, group int) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("AnyRegexpGroup", []interface{}{re, group},
+		"AnyRegexpGroup[\"%v\", %v]", _Source(re), group)
+	if err := _CheckGroup(re, group); err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _AnyPatternGroup(re, group, name)
+}
+
+// Variant of AnyPatternNamed() that uses a precompiled regexp.
+func AnyRegexpNamed(re *regexp.Regexp, groupName string) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("AnyRegexpNamed", []interface{}{re, groupName},
+		"AnyRegexpNamed[\"%v\", \"%v\"]", _Source(re), groupName)
+	group, err := _GroupNamed(re, groupName)
+	if err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _AnyPatternGroup(re, group, name)
+}
+
+func _AnyPatternGroup(re *regexp.Regexp, group int, name *_Name) func(matcher *base.Matcher) *base.Matcher  {
+	pattern := re.String()
+	return func(matcher *base.Matcher) *base.Matcher {
+		match := func(s string) *base.Result {
+			matches := re.FindAllStringSubmatchIndex(s, -1)
+			if matches == nil {
+				return base.NewResultf(false,
+					"No occurrences of pattern \"%v\"", pattern)
+			}
+
+			for index, loc := range matches {
+				substart, subend := loc[2*group], loc[2*group + 1]
+				if substart < 0 {
+					continue // group did not participate in this occurrence
+				}
+				substring := s[substart:subend]
+				result := matcher.Match(substring)
+				if err := result.Err(); err != nil {
+					return base.NewResultf(false,
+						"could not apply matcher to substring[%v:%v]=\"%v\"",
+						substart, subend, substring).
+						WithError(err).
+						WithCauses(result)
+				}
+				if result.Matched() {
+					start, end := loc[0], loc[1]
+					prefix, suffix := s[start:substart], s[subend:end]
+					return base.NewResultf(true,
+						"matched substring[%v:%v], [%v:%v]=\"%v[%v]%v\", occurrence #%v (of %v) of pattern \"%v\"",
+						substart, subend, start, end,
+						prefix, substring, suffix, index+1, len(matches), pattern).
+						WithCauses(result)
+				}
+			}
+			return base.NewResultf(false,
+				"Did not match any occurrence (of %v) of pattern \"%v\"",
+				len(matches), pattern)
+		}
+		return base.NewMatcherf(match, "%v[%v]", name, matcher).
+			WithStructure(name.function, name.params, matcher)
+	}
+}
+
+
+// Returns a function that applies the matcher to the first occurrence of
+// the pattern in an input string.
+//
+// For example:
+//    OnPattern("h.s")(EqualTo("his"))
+// would match:
+//    "hers and his" (because the first instance of "h.s" is equal to "his")
+// but none of:
+//    "just hers" (no instances of "h.s")
+//    "has chisel" (the first instance of "h.s" is not "his")
+func OnPattern(pattern string) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("OnPattern", []interface{}{pattern},
+		"OnPattern[\"%v\"]", pattern)
+	re, err := _Compile(pattern)
+	if err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _OnPattern(re, name)
+}
+
+// Variant of OnPattern() that uses a precompiled regexp.
+func OnRegexp(re *regexp.Regexp) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("OnRegexp", []interface{}{re},
+		"OnRegexp[\"%v\"]", _Source(re))
+	if re == nil {
+		return _ErrorMatcherFunc(_NilRegexp, name)
+	}
+	return _OnPattern(re, name)
+}
+
+func _OnPattern(re *regexp.Regexp, name *_Name) func(matcher *base.Matcher) *base.Matcher  {
+	pattern := re.String()
+	return func(matcher *base.Matcher) *base.Matcher {
+		match := func(s string) *base.Result {
+			matches := re.FindStringIndex(s)
+			if matches == nil {
+				return base.NewResultf(false,
+					"No occurrences of pattern \"%v\"", pattern)
+			}
+			start, end := matches[0], matches[1]
+			substring := s[start:end]
+			result := matcher.Match(substring)
+			return base.NewResultf(result.Matched(),
+				"Found substring[%v:%v]=\"%v\" for pattern \"%v\"",
+				start, end, substring, pattern).
+				WithError(result.Err()).
+				WithCauses(result)
+		}
+		return base.NewMatcherf(match, "%v[%v]", name, matcher).
+			WithStructure(name.function, name.params, matcher)
+	}
+}
+
+
+// Variant of OnPattern() that uses the given subgroup of the pattern.
+func OnPatternGroup(pattern string, group int) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("OnPatternGroup", []interface{}{pattern, group},
+		"OnPatternGroup[\"%v\", %v]", pattern, group)
+	re, err := _CompileWithGroup(pattern, group)
+	if err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _OnPatternGroup(re, group, name)
+}
+
+// Variant of OnPattern() that uses the subgroup of the pattern with
+// the given name, as in (?P<name>re).  For example:
+//    OnPatternNamed(`(?P<year>\d{4})-(?P<month>\d\d)`, "year")(EqualTo("2011"))
+func OnPatternNamed(pattern string, groupName string) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("OnPatternNamed", []interface{}{pattern, groupName},
+		"OnPatternNamed[\"%v\", \"%v\"]", pattern, groupName)
+	re, group, err := _CompileWithGroupNamed(pattern, groupName)
+	if err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _OnPatternGroup(re, group, name)
+}
+
+// Variant of OnPatternGroup() that uses a precompiled regexp.
+func OnRegexpGroup(re *regexp.Regexp, group int) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("OnRegexpGroup", []interface{}{re, group},
+		"OnRegexpGroup[\"%v\", %v]", _Source(re), group)
+	if err := _CheckGroup(re, group); err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _OnPatternGroup(re, group, name)
+}
+
+// Variant of OnPatternNamed() that uses a precompiled regexp.
+func OnRegexpNamed(re *regexp.Regexp, groupName string) func(matcher *base.Matcher) *base.Matcher  {
+	name := _NewName("OnRegexpNamed", []interface{}{re, groupName},
+		"OnRegexpNamed[\"%v\", \"%v\"]", _Source(re), groupName)
+	group, err := _GroupNamed(re, groupName)
+	if err != nil {
+		return _ErrorMatcherFunc(err, name)
+	}
+	return _OnPatternGroup(re, group, name)
+}
+
+func _OnPatternGroup(re *regexp.Regexp, group int, name *_Name) func(matcher *base.Matcher) *base.Matcher  {
+	pattern := re.String()
+	return func(matcher *base.Matcher) *base.Matcher {
+		match := func(s string) *base.Result {
+			loc := re.FindStringSubmatchIndex(s)
+			if loc == nil {
+				return base.NewResultf(false,
+					"No occurrences of pattern \"%v\"", pattern)
+			}
+			start, end := loc[0], loc[1]
+			substart, subend := loc[group*2], loc[group*2+1]
+			if substart < 0 {
+				return base.NewResultf(false,
+					"Group %v did not participate in substring[%v:%v]=\"%v\" for pattern \"%v\"",
+					group, start, end, s[start:end], pattern)
+			}
+			prefix := s[start:substart]
+			substring := s[substart:subend]
+			suffix := s[subend:end]
+			result := matcher.Match(substring)
+			return base.NewResultf(result.Matched(),
+				"Found substring[%v:%v], [%v:%v]=\"%v[%v]%v\" for pattern \"%v\"",
+				substart, subend, start, end,
+				prefix, substring, suffix, pattern).
+				WithError(result.Err()).
+				WithCauses(result)
+		}
+		return base.NewMatcherf(match, "%v[%v]", name, matcher).
+			WithStructure(name.function, name.params, matcher)
+	}
+}
+
+// Returns a matcher that finds the first occurrence of the pattern in
+// an input string and applies each of the given matchers to the named
+// subgroup with the same key.  Every named group is tested (in order
+// of name), and each Result is reported as a cause.  For example:
+//    PatternCaptures(`(?P<key>\w+)=(?P<value>\d+)`, map[string]*base.Matcher{
+//        "key": EqualTo("port"),
+//        "value": HasPrefix("80"),
+//    })
+func PatternCaptures(pattern string, matchers map[string]*base.Matcher) *base.Matcher {
+	name := _NewName("PatternCaptures", []interface{}{pattern},
+		"PatternCaptures[\"%v\"]%v", pattern, matchers)
+	re, err := _Compile(pattern)
+	if err != nil {
+		_, children := _SortedCaptures(matchers)
+		return base.NewErrorMatcherf(err, "%v", name).
+			WithStructure(name.function, name.params, children...)
+	}
+	return _PatternCaptures(re, matchers, name)
+}
+
+// Variant of PatternCaptures() that uses a precompiled regexp.
+func RegexpCaptures(re *regexp.Regexp, matchers map[string]*base.Matcher) *base.Matcher {
+	name := _NewName("RegexpCaptures", []interface{}{re},
+		"RegexpCaptures[\"%v\"]%v", _Source(re), matchers)
+	if re == nil {
+		_, children := _SortedCaptures(matchers)
+		return base.NewErrorMatcherf(_NilRegexp, "%v", name).
+			WithStructure(name.function, name.params, children...)
+	}
+	return _PatternCaptures(re, matchers, name)
+}
+
+func _PatternCaptures(re *regexp.Regexp, matchers map[string]*base.Matcher, name *_Name) *base.Matcher {
+	pattern := re.String()
+	names, children := _SortedCaptures(matchers)
+	groups := make(map[string]int)
+	for _, groupName := range names {
+		group, err := _GroupNamed(re, groupName)
+		if err != nil {
+			return base.NewErrorMatcherf(err, "%v", name).
+				WithStructure(name.function, name.params, children...)
+		}
+		groups[groupName] = group
+	}
+	match := func(s string) *base.Result {
+		loc := re.FindStringSubmatchIndex(s)
+		if loc == nil {
+			return base.NewResultf(false,
+				"No occurrences of pattern \"%v\"", pattern)
+		}
+		results := make([]*base.Result, 0, len(names))
+		failures := 0
+		for _, groupName := range names {
+			group := groups[groupName]
+			substart, subend := loc[2*group], loc[2*group+1]
+			var result *base.Result
+			if substart < 0 {
+				result = base.NewResultf(false,
+					"group \"%v\" did not participate in the match", groupName)
+			} else {
+				result = matchers[groupName].Match(s[substart:subend])
+				if err := result.Err(); err != nil {
+					return base.NewResultf(false,
+						"could not apply matcher for group \"%v\"", groupName).
+						WithError(err).
+						WithCauses(append(results, result)...)
+				}
+			}
+			if !result.Matched() {
+				failures++
+			}
+			results = append(results, result)
+		}
+		start, end := loc[0], loc[1]
+		if failures > 0 {
+			return base.NewResultf(false,
+				"%v of %v groups did not match in substring[%v:%v]=\"%v\" for pattern \"%v\"",
+				failures, len(names), start, end, s[start:end], pattern).
+				WithCauses(results...)
+		}
+		return base.NewResultf(true,
+			"all %v groups matched in substring[%v:%v]=\"%v\" for pattern \"%v\"",
+			len(names), start, end, s[start:end], pattern).
+			WithCauses(results...)
+	}
+	return base.NewMatcherf(match, "%v", name).
+		WithStructure(name.function, name.params, children...)
+}
+
+// Error reported by the matchers built from a nil *regexp.Regexp.
+var _NilRegexp = os.NewError("regexp is nil")
+
+// The maximum number of compiled patterns kept by _Compile.  (A
+// variable, so that tests can lower it.)
+var _maxCompiledPatterns = 256
+
+// Compiled patterns, keyed by pattern, so that matchers built
+// repeatedly from the same pattern compile it only once.  Holds at most
+// _maxCompiledPatterns patterns, forgetting the oldest first (their
+// order is kept in _compiledPatternOrder).
+var _compiledPatterns = make(map[string]*regexp.Regexp)
+var _compiledPatternOrder []string
+var _compiledPatternsLock sync.Mutex
+
+// Compiles the pattern, or returns the result of compiling it earlier.
+// Patterns that cannot be compiled are not remembered.
+func _Compile(pattern string) (*regexp.Regexp, os.Error) {
+	_compiledPatternsLock.Lock()
+	defer _compiledPatternsLock.Unlock()
+	if re, ok := _compiledPatterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	for len(_compiledPatternOrder) >= _maxCompiledPatterns {
+		_compiledPatterns[_compiledPatternOrder[0]] = nil, false
+		_compiledPatternOrder = _compiledPatternOrder[1:]
+	}
+	_compiledPatterns[pattern] = re
+	_compiledPatternOrder = append(_compiledPatternOrder, pattern)
+	return re, nil
+}
+
+// Compiles the pattern and checks that it has the given subgroup.
+func _CompileWithGroup(pattern string, group int) (*regexp.Regexp, os.Error) {
+	re, err := _Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if err := _CheckGroup(re, group); err != nil {
+		return nil, err
+	}
+	return re, nil
+}
+
+// Compiles the pattern and finds the index of its named subgroup.
+func _CompileWithGroupNamed(pattern string, groupName string) (*regexp.Regexp, int, os.Error) {
+	re, err := _Compile(pattern)
+	if err != nil {
+		return nil, -1, err
+	}
+	group, err := _GroupNamed(re, groupName)
+	if err != nil {
+		return nil, -1, err
+	}
+	return re, group, nil
+}
+
+// Returns the source text of re, even if it is nil.
+func _Source(re *regexp.Regexp) string {
+	if re == nil {
+		return "<nil>"
+	}
+	return re.String()
+}
+
+// Checks that re has the given subgroup.
+func _CheckGroup(re *regexp.Regexp, group int) os.Error {
+	if re == nil {
+		return _NilRegexp
+	}
+	if num := re.NumSubexp(); group < 0 || num < group {
+		return os.NewError(fmt.Sprintf(
+			"Illegal group #%v: pattern \"%v\" has only %v groups",
+			group, re, num))
+	}
+	return nil
+}
+
+// Returns the index of the subgroup of re with the given name.
+func _GroupNamed(re *regexp.Regexp, groupName string) (int, os.Error) {
+	if re == nil {
+		return -1, _NilRegexp
+	}
+	for group, name := range re.SubexpNames() {
+		if group > 0 && name == groupName {
+			return group, nil
+		}
+	}
+	return -1, os.NewError(fmt.Sprintf(
+		"Illegal group name \"%v\": pattern \"%v\" has no such group",
+		groupName, re))
+}
+
+// Variant of base.NewErrorMatcherf for pattern matchers that decorate another
+// matcher.
+func _ErrorMatcherFunc(err os.Error, name *_Name) func(matcher *base.Matcher) *base.Matcher {
+	return func(matcher *base.Matcher) *base.Matcher {
+		return base.NewErrorMatcherf(err, "%v[%v]", name, matcher).
+			WithStructure(name.function, name.params, matcher)
+	}
+}
+
+// The name of a pattern matcher:  its description (such as
+// HasPattern["a+"]), and the function name and parameters of its
+// structured description (see base.Matcher.WithStructure).
+type _Name struct {
+	description string
+	function string
+	params []interface{}
+}
+
+func _NewName(function string, params []interface{}, format string, args...interface{}) *_Name {
+	return &_Name{fmt.Sprintf(format, args...), function, params}
+}
+
+// Implements fmt.Stringer.
+func (self *_Name) String() string {
+	return self.description
+}
+
+// Returns the group names of the given matchers for PatternCaptures,
+// in order, and the matchers in the same order.
+func _SortedCaptures(matchers map[string]*base.Matcher) (names []string, sorted []*base.Matcher) {
+	names = make([]string, 0, len(matchers))
+	for groupName := range matchers {
+		names = append(names, groupName)
+	}
+	sort.Strings(names)
+	sorted = make([]*base.Matcher, len(names))
+	for i, groupName := range names {
+		sorted[i] = matchers[groupName]
+	}
+	return names, sorted
+}
@@ -0,0 +1,186 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strings
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	. "github.com/rdrdr/hamcrest/core"
+	"regexp"
+	"testing"
+)
+
+var Errored = base.Errored()
+
+const datePattern = `(?P<year>\d{4})-(?P<month>\d\d)-(?P<day>\d\d)`
+
+func Test_OnPatternNamed(t *testing.T) {
+	we := asserter.Using(t)
+	yearIs2011 := OnPatternNamed(datePattern, "year")(EqualTo("2011"))
+	we.CheckThat(yearIs2011.Match("released 2011-03-15"), Matched)
+	we.CheckThat(yearIs2011.Match("released 2010-03-15"), DidNotMatch)
+	we.CheckThat(yearIs2011.Match("released yesterday"), DidNotMatch)
+	we.CheckThat(OnPatternNamed(datePattern, "month")(EqualTo("03")).
+		Match("released 2011-03-15"), Matched)
+}
+
+func Test_EachPatternNamed(t *testing.T) {
+	we := asserter.Using(t)
+	allIn2011 := EachPatternNamed(datePattern, "year")(EqualTo("2011"))
+	we.CheckThat(allIn2011.Match("2011-01-01 to 2011-12-31"), Matched)
+	we.CheckThat(allIn2011.Match("2011-01-01 to 2012-12-31"), DidNotMatch)
+}
+
+func Test_AnyPatternNamed(t *testing.T) {
+	we := asserter.Using(t)
+	anyInMarch := AnyPatternNamed(datePattern, "month")(EqualTo("03"))
+	we.CheckThat(anyInMarch.Match("2011-01-01 to 2011-03-31"), Matched)
+	we.CheckThat(anyInMarch.Match("2011-01-01 to 2011-12-31"), DidNotMatch)
+}
+
+func Test_PatternCaptures(t *testing.T) {
+	we := asserter.Using(t)
+	matcher := PatternCaptures(datePattern, map[string]*base.Matcher{
+		"year": EqualTo("2011"),
+		"day": HasPrefix("1"),
+	})
+	we.CheckThat(matcher.Match("released 2011-03-15"), Matched)
+	result := matcher.Match("released 2010-03-25")
+	we.CheckThat(result, DidNotMatch)
+	we.CheckThat(len(result.Causes()), EqualTo(2).Comment("reports every group"))
+	we.CheckThat(matcher.Match("no date"), DidNotMatch)
+}
+
+func Test_patternConstructionErrors(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(HasPattern("(unclosed").Match("x"), Errored)
+	we.CheckThat(EachPattern("(unclosed")(Anything()).Match("x"), Errored)
+	we.CheckThat(AnyPattern("(unclosed")(Anything()).Match("x"), Errored)
+	we.CheckThat(OnPattern("(unclosed")(Anything()).Match("x"), Errored)
+	we.CheckThat(OnPatternGroup("(a)", 2)(Anything()).Match("a"), Errored)
+	we.CheckThat(EachPatternGroup("(a)", -1)(Anything()).Match("a"), Errored)
+	we.CheckThat(AnyPatternGroup("(a)", 3)(Anything()).Match("a"), Errored)
+	we.CheckThat(OnPatternNamed(datePattern, "hour")(Anything()).Match("2011-03-15"), Errored)
+	we.CheckThat(PatternCaptures(datePattern, map[string]*base.Matcher{
+			"hour": Anything(),
+		}).Match("2011-03-15"), Errored)
+}
+
+func Test_OnPatternGroup_nonParticipatingGroup(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(OnPatternGroup("a(b)?", 1)(Anything()).Match("a"), DidNotMatch)
+	we.CheckThat(EachPatternGroup("a(b)?", 1)(EqualTo("b")).Match("a ab"), Matched)
+}
+
+func Test_MatchesPattern(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(MatchesPattern(`\d+`).Match("2011"), Matched)
+	we.CheckThat(MatchesPattern(`\d+`).Match("in 2011"), DidNotMatch)
+	we.CheckThat(MatchesPattern(`\d+`).Match("2011!"), DidNotMatch)
+	we.CheckThat(MatchesPattern(`\d+`).Match("none"), DidNotMatch)
+	we.CheckThat(MatchesPattern(`a|ab`).Match("ab"),
+		Matched.Comment("not just the leftmost match"))
+	we.CheckThat(MatchesPattern("(unclosed").Match("x"), Errored)
+}
+
+func Test_regexpVariants(t *testing.T) {
+	we := asserter.Using(t)
+	date := regexp.MustCompile(datePattern)
+	we.CheckThat(HasRegexp(date).Match("released 2011-03-15"), Matched)
+	we.CheckThat(MatchesRegexp(date).Match("released 2011-03-15"), DidNotMatch)
+	we.CheckThat(MatchesRegexp(date).Match("2011-03-15"), Matched)
+	we.CheckThat(EachRegexp(date)(HasPrefix("2011")).
+		Match("2011-01-01 to 2011-12-31"), Matched)
+	we.CheckThat(AnyRegexp(date)(HasSuffix("31")).
+		Match("2011-01-01 to 2011-12-31"), Matched)
+	we.CheckThat(OnRegexp(date)(HasSuffix("31")).
+		Match("2011-01-01 to 2011-12-31"), DidNotMatch)
+	we.CheckThat(OnRegexpGroup(date, 2)(EqualTo("03")).Match("2011-03-15"), Matched)
+	we.CheckThat(EachRegexpNamed(date, "day")(EqualTo("01")).
+		Match("2011-01-01 to 2011-12-01"), Matched)
+	we.CheckThat(AnyRegexpGroup(date, 4)(Anything()).Match("2011-03-15"), Errored)
+	we.CheckThat(OnRegexpNamed(date, "hour")(Anything()).Match("2011-03-15"), Errored)
+	we.CheckThat(RegexpCaptures(date, map[string]*base.Matcher{
+			"month": EqualTo("03"),
+		}).Match("2011-03-15"), Matched)
+}
+
+func Test_regexpVariants_nilRegexp(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(HasRegexp(nil).Match("x"), Errored)
+	we.CheckThat(MatchesRegexp(nil).Match("x"), Errored)
+	we.CheckThat(EachRegexp(nil)(Anything()).Match("x"), Errored)
+	we.CheckThat(OnRegexpGroup(nil, 0)(Anything()).Match("x"), Errored)
+	we.CheckThat(AnyRegexpNamed(nil, "x")(Anything()).Match("x"), Errored)
+}
+
+func Test_compiledPatternsAreCached(t *testing.T) {
+	we := asserter.Using(t)
+	defer _UseCompiledPatterns(10)()
+	first, _ := _Compile(datePattern)
+	second, _ := _Compile(datePattern)
+	we.CheckThat(first == second, Is(True()).Comment("same *regexp.Regexp"))
+	_, err := _Compile("(unclosed")
+	we.CheckThat(err, Not(Nil()))
+	_, cached := _compiledPatterns["(unclosed"]
+	we.CheckThat(cached, Is(False()).Comment("errors are not cached"))
+}
+
+func Test_structure_ofPatternMatchers(t *testing.T) {
+	we := asserter.Using(t)
+	matcher := EachPatternGroup(`(\d+)x`, 1)(OnPattern(".")(HasPattern("7")))
+	var names []string
+	matcher.Inspect(func(m *base.Matcher) bool {
+		names = append(names, m.Name())
+		return true
+	})
+	we.CheckThat(names, DeepEqualTo([]string{"EachPatternGroup", "OnPattern", "HasPattern"}))
+	we.CheckThat(matcher.Params(), DeepEqualTo([]interface{}{`(\d+)x`, 1}))
+
+	first, second := MatchesPattern("x"), Anything()
+	captures := PatternCaptures(`(?P<b>.)(?P<a>.)`,
+		map[string]*base.Matcher{"a": first, "b": second})
+	we.CheckThat(captures.Name(), EqualTo("PatternCaptures"))
+	we.CheckThat(len(captures.Children()), EqualTo(2))
+	we.CheckThat(captures.Children()[0] == first, Is(True()).Comment("in order of name"))
+	we.CheckThat(captures.Children()[1] == second, Is(True()))
+
+	invalid := AnyPattern("(")(second)
+	we.CheckThat(invalid.Name(), EqualTo("AnyPattern").Comment("invalid pattern"))
+	we.CheckThat(invalid.Children()[0] == second, Is(True()))
+	re := regexp.MustCompile("a+")
+	we.CheckThat(MatchesRegexp(re).Params(), DeepEqualTo([]interface{}{re}))
+}
+
+// Replaces the compiled pattern cache with an empty one holding at most
+// max patterns, returning a function that restores the original.
+func _UseCompiledPatterns(max int) (restore func()) {
+	_compiledPatternsLock.Lock()
+	defer _compiledPatternsLock.Unlock()
+	patterns, order, oldMax := _compiledPatterns, _compiledPatternOrder, _maxCompiledPatterns
+	_compiledPatterns = make(map[string]*regexp.Regexp)
+	_compiledPatternOrder = nil
+	_maxCompiledPatterns = max
+	return func() {
+		_compiledPatternsLock.Lock()
+		defer _compiledPatternsLock.Unlock()
+		_compiledPatterns, _compiledPatternOrder, _maxCompiledPatterns = patterns, order, oldMax
+	}
+}
+
+func Test_compiledPatternsAreLimited(t *testing.T) {
+	we := asserter.Using(t)
+	defer _UseCompiledPatterns(3)()
+	for i := 0; i <= 3; i++ {
+		_Compile(fmt.Sprintf("pattern-%v", i))
+	}
+	we.CheckThat(len(_compiledPatterns), EqualTo(3))
+	we.CheckThat(len(_compiledPatternOrder), EqualTo(3))
+	_, cached := _compiledPatterns["pattern-0"]
+	we.CheckThat(cached, Is(False()).Comment("oldest pattern is forgotten"))
+	_, cached = _compiledPatterns["pattern-3"]
+	we.CheckThat(cached, Is(True()))
+}
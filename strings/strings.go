@@ -5,10 +5,13 @@
 package strings
 
 import (
+	"exp/norm"
 	"fmt"
 	"github.com/rdrdr/hamcrest/base"
-	"regexp"
+	"github.com/rdrdr/hamcrest/diff"
 	"strings"
+	"unicode"
+	"utf8"
 )
 
 // Applies the given matcher to the result of writing the input object's
@@ -28,7 +31,8 @@ func ToString(matcher *base.Matcher) *base.Matcher {
 			"Not a fmt.Stringer, but prints as %v", s).
 			WithCauses(result)
 	}
-	return base.NewMatcherf(match, "ToString(%v)", matcher)
+	return base.NewMatcherf(match, "ToString(%v)", matcher).
+		WithStructure("ToString", nil, matcher)
 }
 
 
@@ -49,13 +53,15 @@ func ToGoString(matcher *base.Matcher) *base.Matcher {
 			"Not a fmt.GoStringer, but prints as %v", s).
 			WithCauses(result)
 	}
-	return base.NewMatcherf(match, "ToGoString(%v)", matcher)
+	return base.NewMatcherf(match, "ToGoString(%v)", matcher).
+		WithStructure("ToGoString", nil, matcher)
 }
 
 
 // Creates a new matcher that applies the given matcher to the result of
 // converting an input string to lowercase (using strings.ToLower).
-// If the input value is not a string, the matcher fails to match.
+// If the input value is not a string, the matcher cannot be applied
+// (its Result is an error:  see base.Errored).
 func ToLower(matcher *base.Matcher) *base.Matcher {
 	match := func(s string) *base.Result {
 		lower := strings.ToLower(s)
@@ -64,13 +70,15 @@ func ToLower(matcher *base.Matcher) *base.Matcher {
 			"ToLower is %v", lower).
 			WithCauses(result)
 	}
-	return base.NewMatcherf(match, "ToLower(%v)", matcher)
+	return base.NewMatcherf(match, "ToLower(%v)", matcher).
+		WithStructure("ToLower", nil, matcher)
 }
 
 
 // Creates a new matcher that applies the given matcher to the result of
 // converting an input string to uppercase (using strings.ToUpper).
-// If the input value is not a string, the matcher fails to match.
+// If the input value is not a string, the matcher cannot be applied
+// (its Result is an error:  see base.Errored).
 func ToUpper(matcher *base.Matcher) *base.Matcher {
 	match := func(s string) *base.Result {
 		upper := strings.ToUpper(s)
@@ -79,7 +87,8 @@ func ToUpper(matcher *base.Matcher) *base.Matcher {
 			"ToUpper is %v", upper).
 			WithCauses(result)
 	}
-	return base.NewMatcherf(match, "ToUpper(%v)", matcher)
+	return base.NewMatcherf(match, "ToUpper(%v)", matcher).
+		WithStructure("ToUpper", nil, matcher)
 }
 
 func EqualToIgnoringCase(expected string) *base.Matcher {
@@ -95,14 +104,146 @@ func EqualToIgnoringCase(expected string) *base.Matcher {
 			"\"%v\" differs from \"%v\" (ignoring case)",
 			actual, expected)
 	}
-	return base.NewMatcherf(match, "EqualToIgnoringCase(\"%v\")", expected)
+	return base.NewMatcherf(match, "EqualToIgnoringCase(\"%v\")", expected).
+		WithStructure("EqualToIgnoringCase", []interface{}{expected}).
+		WithNegationf("is not equal to %q (ignoring case)", expected)
 }
 
+// Matches strings that are equal to the given (typically multi-line)
+// text.  On a mismatch, the Result's cause shows the first differing
+// character and a unified diff from the expected to the actual text,
+// with invisible characters escaped.
+func EqualToText(expected string) *base.Matcher {
+	match := func(actual string) *base.Result {
+		if actual == expected {
+			return base.NewResultf(true,
+				"text was equal to expected (%v lines)",
+				len(diff.SplitLines(actual)))
+		}
+		return base.NewResultf(false,
+			"text (%v lines) differs from expected (%v lines)",
+			len(diff.SplitLines(actual)), len(diff.SplitLines(expected))).
+			WithCauses(base.NewResultf(false, "%v", &diff.Comparison{
+				ExpectedName: "expected", ActualName: "actual",
+				Expected: expected, Actual: actual}))
+	}
+	return base.NewMatcherf(match, "EqualToText(\"%v\")", expected).
+		WithStructure("EqualToText", []interface{}{expected})
+}
+
+
+
+// Matches strings that are equal to the expected string once all
+// whitespace (as defined by unicode.IsSpace) is removed from both.
+func EqualToIgnoringWhitespace(expected string) *base.Matcher {
+	return _EqualToNormalized(expected, _RemoveWhitespace,
+		"ignoring whitespace", "EqualToIgnoringWhitespace")
+}
+
+// Matches strings that are equal to the expected string once leading
+// and trailing whitespace is removed from both, and every internal run
+// of whitespace is replaced with a single space.
+func EqualToCollapsingWhitespace(expected string) *base.Matcher {
+	return _EqualToNormalized(expected, _CollapseWhitespace,
+		"collapsing whitespace", "EqualToCollapsingWhitespace")
+}
+
+// Matches strings that are equal to the expected string once Windows
+// ("\r\n") and old Mac ("\r") line endings are converted to "\n".
+func EqualToIgnoringLineEndings(expected string) *base.Matcher {
+	return _EqualToNormalized(expected, _NormalizeLineEndings,
+		"ignoring line endings", "EqualToIgnoringLineEndings")
+}
+
+// Matches strings that are equal to the expected string once both are
+// converted to Unicode Normalization Form C (canonical composition).
+func EqualToNFC(expected string) *base.Matcher {
+	return _EqualToNormalized(expected, norm.NFC.String,
+		"in NFC", "EqualToNFC")
+}
+
+// Matches strings that are equal to the expected string once both are
+// converted to Unicode Normalization Form D (canonical decomposition).
+func EqualToNFD(expected string) *base.Matcher {
+	return _EqualToNormalized(expected, norm.NFD.String,
+		"in NFD", "EqualToNFD")
+}
+
+// Matches strings that are equal to the expected string under Unicode
+// case folding (as in strings.EqualFold).  Unlike EqualToIgnoringCase,
+// this treats characters such as 'K' (Kelvin sign) and 'k' as equal.
+// Results report the folded forms that were compared.
+func EqualToFoldingCase(expected string) *base.Matcher {
+	return _EqualToNormalized(expected, _FoldCase,
+		"under case folding", "EqualToFoldingCase")
+}
+
+// Creates a matcher that compares strings after applying the given
+// normalization to both the actual and expected strings, reporting the
+// normalized forms that were compared.
+func _EqualToNormalized(expected string, normalize func(string) string,
+		how string, name string) *base.Matcher {
+	normalizedExpected := normalize(expected)
+	match := func(actual string) *base.Result {
+		normalizedActual := normalize(actual)
+		if normalizedActual == normalizedExpected {
+			return base.NewResultf(true,
+				"\"%v\" matches \"%v\" (%v, compared \"%v\")",
+				actual, expected, how, normalizedActual)
+		}
+		return base.NewResultf(false,
+			"\"%v\" differs from \"%v\" (%v, compared \"%v\" to \"%v\")",
+			actual, expected, how, normalizedActual, normalizedExpected).
+			WithCauses(base.NewResultf(false, "%v", &diff.Comparison{
+				ExpectedName: "expected (" + how + ")",
+				ActualName: "actual (" + how + ")",
+				Expected: normalizedExpected, Actual: normalizedActual}))
+	}
+	return base.NewMatcherf(match, "%v(\"%v\")", name, expected).
+		WithStructure(name, []interface{}{expected})
+}
+
+func _RemoveWhitespace(s string) string {
+	return strings.Map(func(rune int) int {
+		if unicode.IsSpace(rune) {
+			return -1
+		}
+		return rune
+	}, s)
+}
+
+func _CollapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func _NormalizeLineEndings(s string) string {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	return strings.Replace(s, "\r", "\n", -1)
+}
+
+// Replaces each rune with the smallest rune equivalent to it under
+// Unicode simple case folding, so that two strings are equal under
+// strings.EqualFold exactly when their folded forms are equal.
+func _FoldCase(s string) string {
+	return strings.Map(func(rune int) int {
+		folded := rune
+		for other := unicode.SimpleFold(rune); other != rune; other = unicode.SimpleFold(other) {
+			if other < folded {
+				folded = other
+			}
+		}
+		return folded
+	}, s)
+}
 
 
 // Creates a new matcher that applies the given matcher to the result of
 // converting an input string its length. (using the `len()` builtin).
-// If the input value is not a string, the matcher fails to match.
+// If the input value is not a string, the matcher cannot be applied
+// (its Result is an error:  see base.Errored).
+//
+// Note that this is the length in bytes:  see ToRuneCount and
+// ToGraphemeCount for lengths in characters.
 func ToLen(matcher *base.Matcher) *base.Matcher {
 	match := func(s string) *base.Result {
 		length := len(s)
@@ -111,7 +252,194 @@ func ToLen(matcher *base.Matcher) *base.Matcher {
 			"length is %v", length).
 			WithCauses(result)
 	}
-	return base.NewMatcherf(match, "ToLen(%v)", matcher)
+	return base.NewMatcherf(match, "ToLen(%v)", matcher).
+		WithStructure("ToLen", nil, matcher).
+		WithNegationf("has a length that %v", matcher.Negation())
+}
+
+// Applies the given matcher to the number of runes (Unicode code
+// points) in the input string.  Invalid UTF-8 bytes are counted as
+// one rune each.
+func ToRuneCount(matcher *base.Matcher) *base.Matcher {
+	match := func(s string) *base.Result {
+		count := utf8.RuneCountInString(s)
+		result := matcher.Match(count)
+		return base.NewResultf(result.Matched(),
+			"rune count is %v (length in bytes is %v)", count, len(s)).
+			WithError(result.Err()).
+			WithCauses(result)
+	}
+	return base.NewMatcherf(match, "ToRuneCount(%v)", matcher).
+		WithStructure("ToRuneCount", nil, matcher)
+}
+
+// Applies the given matcher to the number of grapheme clusters
+// (user-perceived characters) in the input string.  For example,
+// "e\u0301" (an "e" followed by a combining acute accent) and
+// "\U0001f1ef\U0001f1f5" (a pair of regional indicators forming a
+// flag) are each a single grapheme.
+//
+// Clusters are approximated as in Unicode Standard Annex #29:  combining
+// marks, variation selectors, emoji modifiers and zero-width-joined
+// runes extend the preceding cluster, regional indicators pair up, and
+// "\r\n" is one cluster.
+func ToGraphemeCount(matcher *base.Matcher) *base.Matcher {
+	match := func(s string) *base.Result {
+		count := _GraphemeCount(s)
+		result := matcher.Match(count)
+		return base.NewResultf(result.Matched(),
+			"grapheme count is %v (rune count is %v)",
+			count, utf8.RuneCountInString(s)).
+			WithError(result.Err()).
+			WithCauses(result)
+	}
+	return base.NewMatcherf(match, "ToGraphemeCount(%v)", matcher).
+		WithStructure("ToGraphemeCount", nil, matcher)
+}
+
+const (
+	_ZeroWidthJoiner = 0x200d
+	_FirstEmojiModifier = 0x1f3fb
+	_LastEmojiModifier = 0x1f3ff
+	_FirstRegionalIndicator = 0x1f1e6
+	_LastRegionalIndicator = 0x1f1ff
+)
+
+func _GraphemeCount(s string) int {
+	count := 0
+	previous := -1
+	regionalIndicators := 0 // length of the current run of them
+	for _, rune := range s {
+		extends := previous >= 0 && (
+			unicode.Is(unicode.M, rune) ||
+			rune == _ZeroWidthJoiner ||
+			previous == _ZeroWidthJoiner ||
+			(_FirstEmojiModifier <= rune && rune <= _LastEmojiModifier) ||
+			(previous == '\r' && rune == '\n'))
+		if _FirstRegionalIndicator <= rune && rune <= _LastRegionalIndicator {
+			if regionalIndicators % 2 == 1 {
+				extends = true
+			}
+			regionalIndicators++
+		} else {
+			regionalIndicators = 0
+		}
+		if !extends {
+			count++
+		}
+		previous = rune
+	}
+	return count
+}
+
+// Matches strings that are valid UTF-8.  On a mismatch, the Result
+// gives the byte offset of the first invalid byte.
+func IsValidUTF8() *base.Matcher {
+	match := func(s string) *base.Result {
+		for offset := 0; offset < len(s); {
+			rune, size := utf8.DecodeRuneInString(s[offset:])
+			if rune == utf8.RuneError && size <= 1 {
+				return base.NewResultf(false,
+					"invalid UTF-8 byte %#02x at offset %v in \"%v\"",
+					s[offset], offset, diff.Escape(s))
+			}
+			offset += size
+		}
+		return base.NewResultf(true, "is valid UTF-8")
+	}
+	return base.NewMatcherf(match, "IsValidUTF8").
+		WithStructure("IsValidUTF8", nil).
+		WithNegationf("is not valid UTF-8")
+}
+
+// Matches strings whose runes all belong to the given Unicode range
+// table, such as unicode.Latin or unicode.Digit.  On a mismatch, the
+// Result gives the first rune that is not in the table.
+func AllRunesIn(table *unicode.RangeTable) *base.Matcher {
+	name := _TableName(table)
+	match := func(s string) *base.Result {
+		for offset, rune := range s {
+			if !unicode.Is(table, rune) {
+				return base.NewResultf(false,
+					"rune %U (%q) at offset %v is not in %v",
+					rune, rune, offset, name)
+			}
+		}
+		return base.NewResultf(true, "all runes are in %v", name)
+	}
+	return base.NewMatcherf(match, "AllRunesIn[%v]", name).
+		WithStructure("AllRunesIn", []interface{}{table})
+}
+
+// Returns the name of a table in the unicode package, or a generic
+// description for other tables.
+func _TableName(table *unicode.RangeTable) string {
+	for _, tables := range []map[string]*unicode.RangeTable{
+			unicode.Categories, unicode.Scripts, unicode.Properties} {
+		for name, candidate := range tables {
+			if candidate == table {
+				return name
+			}
+		}
+	}
+	return "custom range table"
+}
+
+// Matches strings that contain no control characters (Unicode
+// category Cc), which includes tabs and newlines.  On a mismatch, the
+// Result gives the first control character found.
+func HasNoControlChars() *base.Matcher {
+	match := func(s string) *base.Result {
+		for offset, rune := range s {
+			if unicode.Is(unicode.Cc, rune) {
+				return base.NewResultf(false,
+					"control character %U at offset %v in \"%v\"",
+					rune, offset, diff.Escape(s))
+			}
+		}
+		return base.NewResultf(true, "has no control characters")
+	}
+	return base.NewMatcherf(match, "HasNoControlChars").
+		WithStructure("HasNoControlChars", nil).
+		WithNegationf("has control characters")
+}
+
+// Applies the given matcher to the []string of lines in the input
+// string, typically a matcher from the slices package.  Lines are
+// split on "\n" (removing any "\r" before it), and a final newline
+// does not produce an additional empty line.  For example:
+//    ToLines(slices.EachElem(HasPrefix("> ")))
+func ToLines(matcher *base.Matcher) *base.Matcher {
+	match := func(s string) *base.Result {
+		lines := diff.SplitLines(s)
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, "\r")
+		}
+		result := matcher.Match(lines)
+		return base.NewResultf(result.Matched(),
+			"split into %v lines", len(lines)).
+			WithError(result.Err()).
+			WithCauses(result)
+	}
+	return base.NewMatcherf(match, "ToLines(%v)", matcher).
+		WithStructure("ToLines", nil, matcher)
+}
+
+// Applies the given matcher to the []string of whitespace-separated
+// fields in the input string (as given by strings.Fields), typically
+// a matcher from the slices package.  For example:
+//    ToFields(slices.ToLen(EqualTo(3)))
+func ToFields(matcher *base.Matcher) *base.Matcher {
+	match := func(s string) *base.Result {
+		fields := strings.Fields(s)
+		result := matcher.Match(fields)
+		return base.NewResultf(result.Matched(),
+			"split into %v fields: %q", len(fields), fields).
+			WithError(result.Err()).
+			WithCauses(result)
+	}
+	return base.NewMatcherf(match, "ToFields(%v)", matcher).
+		WithStructure("ToFields", nil, matcher)
 }
 
 
@@ -130,7 +458,9 @@ func HasPrefix(prefix string) *base.Matcher {
 		return base.NewResultf(false,
 			"\"%v%v\" does not start with \"%v\"", s, continued, prefix)
 	}
-	return base.NewMatcherf(match, "HasPrefix(\"%v\")", prefix)
+	return base.NewMatcherf(match, "HasPrefix(\"%v\")", prefix).
+		WithStructure("HasPrefix", []interface{}{prefix}).
+		WithNegationf("does not start with %q", prefix)
 }
 
 // Matches strings that end with the given prefix.
@@ -148,277 +478,174 @@ func HasSuffix(suffix string) *base.Matcher {
 		return base.NewResultf(false,
 			"\"%v%v\" does not end with \"%v\"", s, continued, suffix)
 	}
-	return base.NewMatcherf(match, "HasSuffix(\"%v\")", suffix)
+	return base.NewMatcherf(match, "HasSuffix(\"%v\")", suffix).
+		WithStructure("HasSuffix", []interface{}{suffix}).
+		WithNegationf("does not end with %q", suffix)
 }
 
 
 // Matches strings that contain the given substring.
 func Contains(substring string) *base.Matcher {
 	match := func (s string) *base.Result {
-		extra := 8
 		if foundStart := strings.Index(s, substring); foundStart >= 0 {
-			foundEnd := foundStart + len(substring)
-			start, end := foundStart - extra, foundEnd + extra
-			prefix, suffix := "", ""
-			if start <= 0 {
-				start = 0
-			} else {
-				prefix = "..."
-			}
-			if end >= len(s) {
-				end = len(s)
-			} else {
-				suffix = "..."
-			}
 			return base.NewResultf(true,
-				"substring \"%v\" appears in \"%v%v[%v]%v%v\"", substring,
-				prefix, s[start:foundStart], substring, s[foundEnd:end], suffix)
+				"substring \"%v\" appears in \"%v\"", substring,
+				_InContext(s, foundStart, foundStart + len(substring)))
 		}
 		return base.NewResultf(false,
 			"substring \"%v\" does not appear in \"%v\"",
 			substring, s)
 	}
-	return base.NewMatcherf(match, "Contains(\"%v\")", substring)
+	return base.NewMatcherf(match, "Contains(\"%v\")", substring).
+		WithStructure("Contains", []interface{}{substring}).
+		WithNegationf("does not contain %q", substring)
 }
 
-// Matches strings that contain the given regexp pattern, using
-// the same syntax as the standard regexp package.
-func HasPattern(pattern string) *base.Matcher {
-	re := regexp.MustCompile(pattern)
-	match := func (s string) *base.Result {
-		if found := re.FindStringIndex(s); found != nil {
-			start, end := found[0], found[1]
-			return base.NewResultf(true,
-				"pattern \"%v\" matched substring[%v:%v]=\"%v\"",
-				pattern, start, end, s[start:end])
-		}
-		return base.NewResultf(false,
-			"pattern \"%v\" not found in \"%v\"", pattern, s)
+// Returns s[foundStart:foundEnd] in brackets, within a window of a
+// few characters of surrounding context (elided with "...").
+func _InContext(s string, foundStart, foundEnd int) string {
+	extra := 8
+	start, end := foundStart - extra, foundEnd + extra
+	prefix, suffix := "", ""
+	if start <= 0 {
+		start = 0
+	} else {
+		prefix = "..."
 	}
-	return base.NewMatcherf(match, "HasPattern[\"%v\"]", pattern)
-}
-
-type WithPatternClause struct {
-	re *regexp.Regexp
-	group int
+	if end >= len(s) {
+		end = len(s)
+	} else {
+		suffix = "..."
+	}
+	return fmt.Sprintf("%v%v[%v]%v%v", prefix, s[start:foundStart],
+		s[foundStart:foundEnd], s[foundEnd:end], suffix)
 }
 
-// Returns a short-circuiting function that applies the matcher to each
-// occurrence of the pattern in an input string, until a failing pattern
-// is found (in which case the output matcher fails to match) or all
-// matching instances are exhausted (in which case the output matcher
-// successfully matches).
+// Matches strings that contain each of the given substrings, in the
+// given order and without overlapping.  On a mismatch, describes which
+// substring could not be found after the previous one.
 //
 // For example:
-//    EachPattern("q.")(EqualTo("qu"))
+//    ContainsInOrder("GET", "200", "OK")
 // would match:
-//    "quick quack mq" (two matches of "q.", both equal to "qu")
+//    "GET /index.html 200 OK"
 // but not:
-//    "quick qs for mq" (two matches of "q.", second is not "qu")
-func EachPattern(pattern string) func(matcher *base.Matcher) *base.Matcher  {
-	re := regexp.MustCompile(pattern)
-	return func(matcher *base.Matcher) *base.Matcher {
-		match := func(s string) *base.Result {
-			matches := re.FindAllStringIndex(s, -1)
-			if matches == nil {
-				return base.NewResultf(true,
-					"No occurrences of pattern \"%v\"", pattern)
-			}
-			for index, loc := range matches {
-				start, end := loc[0], loc[1]
-				substring := s[start:end]
-				result := matcher.Match(substring)
-				if !result.Matched() {
+//    "GET /index.html OK 200" ("OK" is not found after "200")
+func ContainsInOrder(substrings...string) *base.Matcher {
+	match := func(s string) *base.Result {
+		results := make([]*base.Result, 0, len(substrings))
+		offset := 0
+		for index, substring := range substrings {
+			found := strings.Index(s[offset:], substring)
+			if found < 0 {
+				if index == 0 {
 					return base.NewResultf(false,
-						"did not match substring[%v:%v]=\"%v\", occurrence #%v (of %v) of pattern \"%v\"",
-						start, end, substring, index+1, len(matches), pattern)
+						"first substring \"%v\" does not appear in \"%v\"",
+						substring, s).
+						WithCauses(results...)
 				}
+				return base.NewResultf(false,
+					"substring #%v \"%v\" does not appear after substring #%v \"%v\" (at offset %v) in \"%v\"",
+					index+1, substring, index, substrings[index-1], offset, s).
+					WithCauses(results...)
 			}
-			return base.NewResultf(true,
-				"Matched every occurrence (all %v) of pattern \"%v\"",
-				len(matches), pattern)
+			start := offset + found
+			end := start + len(substring)
+			results = append(results, base.NewResultf(true,
+				"substring #%v \"%v\" appears at [%v:%v] in \"%v\"",
+				index+1, substring, start, end, _InContext(s, start, end)))
+			offset = end
 		}
-		return base.NewMatcherf(match,
-			"EachPattern[\"%v\"][%v]", pattern, matcher)
+		return base.NewResultf(true,
+			"all %v substrings appear in order", len(substrings)).
+			WithCauses(results...)
 	}
+	return base.NewMatcherf(match, "ContainsInOrder%q", substrings).
+		WithStructure("ContainsInOrder", _Params(substrings))
 }
 
-// Variant of EachPattern() that uses the given subgroup of the pattern.
-func EachPatternGroup(pattern string, group int) func(matcher *base.Matcher) *base.Matcher  {
-	re := regexp.MustCompile(pattern)
-	if num := re.NumSubexp(); num < group {
-		println("Illegal group #", group, ": there are only ", num, "groups.") 
-		panic("Group index out of bounds.")
-	}
-	return func(matcher *base.Matcher) *base.Matcher {
-		match := func(s string) *base.Result {
-			matches := re.FindAllStringSubmatchIndex(s, -1)
-			if matches == nil {
-				return base.NewResultf(true,
-					"No occurrences of pattern \"%v\"", pattern)
-			}
-			for index, loc := range matches {
-				substart, subend := loc[2*group], loc[2*group+1]
-				substring := s[substart:subend]
-				result := matcher.Match(substring)
-				if !result.Matched() {
-					start, end := loc[0], loc[1]
-					prefix, suffix := s[start:substart], s[subend:end]
-					return base.NewResultf(false,
-						"did not match substring[%v:%v], [%v:%v]=\"%v[%v]%v\", occurrence #%v (of %v) of pattern \"%v\"",
-						substart, subend, start, end,
-						prefix, substring, suffix, index+1, len(matches), pattern)
-				}
-			}
-			return base.NewResultf(true,
-				"Matched every occurrence (all %v) of pattern \"%v\", group %v",
-				len(matches), pattern, group)
-		}
-		return base.NewMatcherf(match,
-			"EachPatternGroup[\"%v\", %v][%v]", pattern, group, matcher)
+// Matches strings in which the number of non-overlapping occurrences
+// of the given substring (as per strings.Count) matches the given
+// countMatcher.  For example:
+//    ContainsCount("\n", EqualTo(3))
+func ContainsCount(substring string, countMatcher *base.Matcher) *base.Matcher {
+	match := func(s string) *base.Result {
+		count := strings.Count(s, substring)
+		result := countMatcher.Match(count)
+		return base.NewResultf(result.Matched(),
+			"substring \"%v\" appears %v times", substring, count).
+			WithError(result.Err()).
+			WithCauses(result)
 	}
+	return base.NewMatcherf(match, "ContainsCount(\"%v\", %v)",
+		substring, countMatcher).
+		WithStructure("ContainsCount", []interface{}{substring}, countMatcher)
 }
 
-
-// Returns a short-circuiting function that applies the matcher to each
-// occurrence of the pattern in an input string, until a matching pattern
-// is found (in which case the matcher successfully matches) or all
-// matching instances are exhausted (in which case the output matcher
-// fails to match).
-//
-// For example:
-//    AnyPattern("x.")(EqualTo("xy"))
-// would match:
-//    "six sax are sexy" (three matches of "x.", third is "xy")
-// but not:
-//    "pox pix are pixelated" (three matches of "x.", none is "xy")
-func AnyPattern(pattern string) func(matcher *base.Matcher) *base.Matcher  {
-	re := regexp.MustCompile(pattern)
-	return func(matcher *base.Matcher) *base.Matcher {
-		match := func(s string) *base.Result {
-			matches := re.FindAllStringIndex(s, -1)
-			if matches == nil {
-				return base.NewResultf(false,
-					"No occurrences of pattern \"%v\"", pattern)
-			}
-			
-			for index, loc := range matches {
-				start, end := loc[0], loc[1]
-				substring := s[start:end]
-				result := matcher.Match(substring)
-				if result.Matched() {
-					return base.NewResultf(true,
-						"matched substring[%v:%v]=\"%v\", occurrence #%v (of %v) of pattern \"%v\"",
-						start, end, substring, index+1, len(matches), pattern)
-				}
-			}
-			return base.NewResultf(false,
-				"Did not match any occurrence (of %v) of pattern \"%v\"",
-				len(matches), pattern)
+// Matches strings that contain every one of the given substrings, in
+// any order.  Every substring is checked, and each is reported as a
+// cause.
+func ContainsAll(substrings...string) *base.Matcher {
+	match := func(s string) *base.Result {
+		results, found := _FindEach(s, substrings)
+		if found == len(substrings) {
+			return base.NewResultf(true,
+				"all %v substrings appear", len(substrings)).
+				WithCauses(results...)
 		}
-		return base.NewMatcherf(match,
-			"AnyPattern[\"%v\"][%v]", pattern, matcher)
+		return base.NewResultf(false,
+			"only %v of %v substrings appear in \"%v\"",
+			found, len(substrings), s).
+			WithCauses(results...)
 	}
+	return base.NewMatcherf(match, "ContainsAll%q", substrings).
+		WithStructure("ContainsAll", _Params(substrings)).
+		WithNegationf("does not contain all of %q", substrings)
 }
 
-
-// Variant of AnyPattern() that uses the given subgroup of the pattern.
-func AnyPatternGroup(pattern string, group int) func(matcher *base.Matcher) *base.Matcher  {
-	re := regexp.MustCompile(pattern)
-	if num := re.NumSubexp(); num < group {
-		println("Illegal group #", group, ": there are only ", num, "groups.") 
-		panic("Group index out of bounds.")
-	}
-	return func(matcher *base.Matcher) *base.Matcher {
-		match := func(s string) *base.Result {
-			matches := re.FindAllStringSubmatchIndex(s, -1)
-			if matches == nil {
-				return base.NewResultf(false,
-					"No occurrences of pattern \"%v\"", pattern)
-			}
-			
-			for index, loc := range matches {
-				substart, subend := loc[2*group], loc[2*group + 1]
-				substring := s[substart:subend]
-				result := matcher.Match(substring)
-				if result.Matched() {
-					start, end := loc[0], loc[1]
-					prefix, suffix := s[start:substart], s[subend:end]
-					return base.NewResultf(true,
-						"matched substring[%v:%v], [%v:%v]=\"%v[%v]%v\", occurrence #%v (of %v) of pattern \"%v\"",
-						substart, subend, start, end,
-						prefix, substring, suffix, index+1, len(matches), pattern)
-				}
-			}
-			return base.NewResultf(false,
-				"Did not match any occurrence (of %v) of pattern \"%v\"",
-				len(matches), pattern)
+// Matches strings that contain at least one of the given substrings.
+// Every substring is checked, and each is reported as a cause.
+func ContainsAny(substrings...string) *base.Matcher {
+	match := func(s string) *base.Result {
+		results, found := _FindEach(s, substrings)
+		if found > 0 {
+			return base.NewResultf(true,
+				"%v of %v substrings appear", found, len(substrings)).
+				WithCauses(results...)
 		}
-		return base.NewMatcherf(match,
-			"AnyPatternGroup[\"%v\", %v][%v]", pattern, group, matcher)
+		return base.NewResultf(false,
+			"none of the %v substrings appear in \"%v\"",
+			len(substrings), s).
+			WithCauses(results...)
 	}
+	return base.NewMatcherf(match, "ContainsAny%q", substrings).
+		WithStructure("ContainsAny", _Params(substrings)).
+		WithNegationf("contains none of %q", substrings)
 }
 
-
-// Returns a function that applies the matcher to the first occurrence of
-// the pattern in an input string.
-//
-// For example:
-//    OnPattern("h.s")(EqualTo("his"))
-// would match:
-//    "hers and his" (because the first instance of "h.s" is equal to "his")
-// but none of:
-//    "just hers" (no instances of "h.s")
-//    "has chisel" (the first instance of "h.s" is not "his")
-func OnPattern(pattern string) func(matcher *base.Matcher) *base.Matcher  {
-	re := regexp.MustCompile(pattern)
-	return func(matcher *base.Matcher) *base.Matcher {
-		match := func(s string) *base.Result {
-			matches := re.FindStringIndex(s)
-			if matches == nil {
-				return base.NewResultf(false,
-					"No occurrences of pattern \"%v\"", pattern)
-			}
-			start, end := matches[0], matches[1]
-			substring := s[start:end]
-			result := matcher.Match(substring)
-			return base.NewResultf(result.Matched(),
-				"Found substring[%v:%v]=\"%v\" for pattern \"%v\"",
-				start, end, substring, pattern).WithCauses(result)
-		}
-		return base.NewMatcherf(match,
-			"OnPattern[\"%v\"][%v]", pattern, matcher)
+// Converts substrings to the parameters of a structured description.
+func _Params(substrings []string) []interface{} {
+	params := make([]interface{}, len(substrings))
+	for i, substring := range substrings {
+		params[i] = substring
 	}
+	return params
 }
 
-
-// Variant of OnPattern() that uses the given subgroup of the pattern.
-func OnPatternGroup(pattern string, group int) func(matcher *base.Matcher) *base.Matcher  {
-	re := regexp.MustCompile(pattern)
-	if num := re.NumSubexp(); num < group {
-		println("Illegal group #", group, ": there are only ", num, "groups.") 
-		panic("Group index out of bounds.")
-	}
-	return func(matcher *base.Matcher) *base.Matcher {
-		match := func(s string) *base.Result {
-			loc := re.FindStringSubmatchIndex(s)
-			if loc == nil {
-				return base.NewResultf(false,
-					"No occurrences of pattern \"%v\"", pattern)
-			}
-			start, end := loc[0], loc[1]
-			substart, subend := loc[group*2], loc[group*2+1]
-			prefix := s[start:substart]
-			substring := s[substart:subend]
-			suffix := s[subend:end]
-			result := matcher.Match(substring)
-			return base.NewResultf(result.Matched(),
-				"Found substring[%v:%v], [%v:%v]=\"%v[%v]%v\" for pattern \"%v\"",
-				substart, subend, start, end,
-				prefix, substring, suffix, pattern).WithCauses(result)
+// Looks for each substring in s, returning a Result for each and the
+// number of substrings that were found.
+func _FindEach(s string, substrings []string) (results []*base.Result, found int) {
+	results = make([]*base.Result, len(substrings))
+	for index, substring := range substrings {
+		if start := strings.Index(s, substring); start >= 0 {
+			found++
+			results[index] = base.NewResultf(true,
+				"substring \"%v\" appears in \"%v\"", substring,
+				_InContext(s, start, start + len(substring)))
+		} else {
+			results[index] = base.NewResultf(false,
+				"substring \"%v\" does not appear", substring)
 		}
-		return base.NewMatcherf(match,
-			"OnPatternGroup[\"%v\", %v][%v]", pattern, group, matcher)
 	}
+	return
 }
-
@@ -9,7 +9,9 @@ import (
 	"github.com/rdrdr/hamcrest/asserter"
 	"github.com/rdrdr/hamcrest/base"
 	. "github.com/rdrdr/hamcrest/core"
+	"github.com/rdrdr/hamcrest/slices"
 	"testing"
+	"unicode"
 )
 
 var Matched = base.Matched()
@@ -64,7 +66,7 @@ func Test_ToLower(t *testing.T) {
 	we.CheckThat(ToLower(EqualTo("SHOUT")).Match("shout"), DidNotMatch)
 	we.CheckThat(ToLower(EqualTo("SHOUT")).Match("SHOUT"), DidNotMatch)
 	we.CheckThat(ToLower(EqualTo("123")).Match("123"), Matched)
-	we.CheckThat(ToLower(EqualTo("123")).Match(123), DidNotMatch)
+	we.CheckThat(ToLower(EqualTo("123")).Match(123), Errored)
 }
 
 func Test_ToUpper(t *testing.T) {
@@ -74,17 +76,80 @@ func Test_ToUpper(t *testing.T) {
 	we.CheckThat(ToUpper(EqualTo("SHOUT")).Match("shout"), Matched)
 	we.CheckThat(ToUpper(EqualTo("SHOUT")).Match("SHOUT"), Matched)
 	we.CheckThat(ToUpper(EqualTo("123")).Match("123"), Matched)
-	we.CheckThat(ToUpper(EqualTo("123")).Match(123), DidNotMatch)
+	we.CheckThat(ToUpper(EqualTo("123")).Match(123), Errored)
 }
 
 func Test_ToLen(t *testing.T) {
 	we := asserter.Using(t)
 	we.CheckThat(ToLen(EqualTo(5)).Match("SHOUT"), Matched)
 	we.CheckThat(ToLen(EqualTo(5)).Match("OOPS"), DidNotMatch)
-	we.CheckThat(ToLen(Anything()).Match(0), DidNotMatch.
+	we.CheckThat(ToLen(Anything()).Match(0), Errored.
 		Comment("should fail when can't determine length"))
 }
 
+func Test_ToRuneCount(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ToRuneCount(EqualTo(5)).Match("héllo"), Matched)
+	we.CheckThat(ToLen(EqualTo(5)).Match("héllo"), DidNotMatch.
+		Comment("ToLen counts bytes"))
+	we.CheckThat(ToRuneCount(EqualTo(2)).Match("日本"), Matched)
+}
+
+func Test_ToGraphemeCount(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ToGraphemeCount(EqualTo(5)).Match("he\u0301llo"), Matched)
+	we.CheckThat(ToRuneCount(EqualTo(6)).Match("he\u0301llo"), Matched)
+	we.CheckThat(ToGraphemeCount(EqualTo(2)).
+		Match("\U0001f1ef\U0001f1f5\U0001f1eb\U0001f1f7"), Matched.
+		Comment("two flags"))
+	we.CheckThat(ToGraphemeCount(EqualTo(1)).
+		Match("\U0001f469\u200d\U0001f4bb"), Matched.
+		Comment("zero-width-joined emoji"))
+	we.CheckThat(ToGraphemeCount(EqualTo(2)).Match("a\r\n"), Matched)
+	we.CheckThat(ToGraphemeCount(EqualTo(0)).Match(""), Matched)
+}
+
+func Test_IsValidUTF8(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(IsValidUTF8().Match("日本"), Matched)
+	we.CheckThat(IsValidUTF8().Match("ok\xffnot"), DidNotMatch)
+	we.CheckThat(IsValidUTF8().Match("\xe6\x97"), DidNotMatch.
+		Comment("truncated sequence"))
+	we.CheckThat(IsValidUTF8().Match("\ufffd"), Matched.
+		Comment("a literal replacement character is valid"))
+}
+
+func Test_AllRunesIn(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(AllRunesIn(unicode.Digit).Match("2011"), Matched)
+	we.CheckThat(AllRunesIn(unicode.Digit).Match("20l1"), DidNotMatch)
+	we.CheckThat(AllRunesIn(unicode.Han).Match("日本"), Matched)
+	we.CheckThat(AllRunesIn(unicode.Latin).Match("日本"), DidNotMatch)
+	we.CheckThat(AllRunesIn(unicode.Latin).Match(""), Matched)
+}
+
+func Test_HasNoControlChars(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(HasNoControlChars().Match("plain text"), Matched)
+	we.CheckThat(HasNoControlChars().Match("bell\a"), DidNotMatch)
+	we.CheckThat(HasNoControlChars().Match("two\nlines"), DidNotMatch)
+}
+
+func Test_ToLines(t *testing.T) {
+	we := asserter.Using(t)
+	quoted := ToLines(slices.EachElem(HasPrefix("> ")))
+	we.CheckThat(quoted.Match("> one\r\n> two\n"), Matched)
+	we.CheckThat(quoted.Match("> one\ntwo"), DidNotMatch)
+	we.CheckThat(ToLines(slices.ToLen(EqualTo(3))).Match("a\nb\nc\n"), Matched)
+	we.CheckThat(ToLines(DeepEqualTo([]string{"a", "b"})).Match("a\r\nb"), Matched)
+}
+
+func Test_ToFields(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ToFields(slices.ToLen(EqualTo(3))).Match("  a b\tc\n"), Matched)
+	we.CheckThat(ToFields(slices.AnyElem(EqualTo("b"))).Match("a c"), DidNotMatch)
+}
+
 func Test_EqualToIgnoringCase(t *testing.T) {
 	we := asserter.Using(t)
 	we.CheckThat(EqualToIgnoringCase("one").Match("one"), Matched)
@@ -93,8 +158,63 @@ func Test_EqualToIgnoringCase(t *testing.T) {
 	we.CheckThat(EqualToIgnoringCase("ONE").Match("ONE"), Matched)
 	we.CheckThat(EqualToIgnoringCase("oNe").Match("OnE"), Matched)
 	we.CheckThat(EqualToIgnoringCase("one").Match("two"), DidNotMatch)
-	we.CheckThat(EqualToIgnoringCase("one").Match(1), DidNotMatch)
-	we.CheckThat(EqualToIgnoringCase("one").Match(nil), DidNotMatch)
+	we.CheckThat(EqualToIgnoringCase("one").Match(1), Errored)
+	we.CheckThat(EqualToIgnoringCase("one").Match(nil), Errored)
+}
+
+func Test_EqualToIgnoringWhitespace(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(EqualToIgnoringWhitespace("a b c").Match("abc"), Matched)
+	we.CheckThat(EqualToIgnoringWhitespace("a b c").Match(" a\tb\nc "), Matched)
+	we.CheckThat(EqualToIgnoringWhitespace("a b c").Match("a b d"), DidNotMatch)
+	we.CheckThat(EqualToIgnoringWhitespace("abc").Match(123), Errored)
+}
+
+func Test_EqualToCollapsingWhitespace(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(EqualToCollapsingWhitespace("a b c").Match("  a \t b\n\nc\n"), Matched)
+	we.CheckThat(EqualToCollapsingWhitespace("a b c").Match("abc"), DidNotMatch)
+	we.CheckThat(EqualToCollapsingWhitespace("a  b").Match("a b"), Matched)
+}
+
+func Test_EqualToIgnoringLineEndings(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(EqualToIgnoringLineEndings("a\nb\n").Match("a\r\nb\r\n"), Matched)
+	we.CheckThat(EqualToIgnoringLineEndings("a\nb\n").Match("a\rb\r"), Matched)
+	we.CheckThat(EqualToIgnoringLineEndings("a\nb\n").Match("a b\n"), DidNotMatch)
+}
+
+func Test_EqualToNFC_NFD(t *testing.T) {
+	we := asserter.Using(t)
+	composed, decomposed := "caf\u00e9", "cafe\u0301"
+	we.CheckThat(EqualTo(composed).Match(decomposed), DidNotMatch)
+	we.CheckThat(EqualToNFC(composed).Match(decomposed), Matched)
+	we.CheckThat(EqualToNFD(composed).Match(decomposed), Matched)
+	we.CheckThat(EqualToNFC(composed).Match("cafe"), DidNotMatch)
+}
+
+func Test_EqualToFoldingCase(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(EqualToFoldingCase("Go").Match("GO"), Matched)
+	we.CheckThat(EqualToFoldingCase("k").Match("\u212a"), Matched.Comment("Kelvin sign"))
+	we.CheckThat(EqualToFoldingCase("Go").Match("Og"), DidNotMatch)
+	we.CheckThat(EqualToFoldingCase("Go").Match("go").String(),
+		EqualTo(`"go" matches "Go" (under case folding, compared "GO")`))
+	we.CheckThat(EqualToFoldingCase("Go").Match("Og").String(),
+		EqualTo(`"Og" differs from "Go" (under case folding, compared "OG" to "GO")`))
+}
+
+func Test_EqualToText(t *testing.T) {
+	we := asserter.Using(t)
+	text := "line one\nline two\nline three\n"
+	we.CheckThat(EqualToText(text).Match(text), Matched)
+	we.CheckThat(EqualToText(text).Match(42), Errored)
+	result := EqualToText(text).Match("line one\nline 2\nline three\n")
+	we.CheckThat(result, DidNotMatch)
+	we.AssertThat(len(result.Causes()), EqualTo(1).Comment("diff cause"))
+	cause := result.Causes()[0]
+	we.CheckThat(cause, ToString(Contains("line 2, column 6")))
+	we.CheckThat(cause, ToString(Contains("-line two\n+line 2\n")))
 }
 
 
@@ -104,8 +224,8 @@ func Test_Contains(t *testing.T) {
 	we.CheckThat(Contains("bcd").Match("abcde"), Matched.Comment("middle"))
 	we.CheckThat(Contains("cde").Match("abcde"), Matched.Comment("suffix"))
 	we.CheckThat(Contains("ace").Match("abcde"), DidNotMatch)
-	we.CheckThat(Contains("123").Match(123), DidNotMatch)
-	we.CheckThat(Contains("123").Match(nil), DidNotMatch)
+	we.CheckThat(Contains("123").Match(123), Errored)
+	we.CheckThat(Contains("123").Match(nil), Errored)
 }
 
 var alphabet = "abcdefghijklmnopqrstuvwxyz"
@@ -114,8 +234,8 @@ func Test_HasPrefix(t *testing.T) {
 	we := asserter.Using(t)
 	we.CheckThat(HasPrefix("abc").Match(alphabet), Matched)
 	we.CheckThat(HasPrefix("cde").Match(alphabet), DidNotMatch)
-	we.CheckThat(HasPrefix("123").Match(123), DidNotMatch)
-	we.CheckThat(HasPrefix("123").Match(nil), DidNotMatch)
+	we.CheckThat(HasPrefix("123").Match(123), Errored)
+	we.CheckThat(HasPrefix("123").Match(nil), Errored)
 
 	failResultString := HasPrefix("123").Match(alphabet).String()
 	we.CheckThat(failResultString, Contains("123"))
@@ -133,8 +253,8 @@ func Test_HasSuffix(t *testing.T) {
 	we := asserter.Using(t)
 	we.CheckThat(HasSuffix("xyz").Match(alphabet), Matched)
 	we.CheckThat(HasSuffix("wxy").Match(alphabet), DidNotMatch)
-	we.CheckThat(HasSuffix("123").Match(123), DidNotMatch)
-	we.CheckThat(HasSuffix("123").Match(nil), DidNotMatch)
+	we.CheckThat(HasSuffix("123").Match(123), Errored)
+	we.CheckThat(HasSuffix("123").Match(nil), Errored)
 	
 	failResultString := HasSuffix("123").Match(alphabet).String()
 	we.CheckThat(failResultString, Contains("123"))
@@ -163,8 +283,8 @@ func Test_EachPattern(t *testing.T) {
 	we.CheckThat(eachGoPlusIsGoo.Match("goo goo goo"), Matched)
 	we.CheckThat(eachGoPlusIsGoo.Match("goo go goo"), DidNotMatch)
 	we.CheckThat(eachGoPlusIsGoo.Match("go go go"), DidNotMatch)
-	we.CheckThat(eachGoPlusIsGoo.Match(123), DidNotMatch)
-	we.CheckThat(eachGoPlusIsGoo.Match(nil), DidNotMatch)
+	we.CheckThat(eachGoPlusIsGoo.Match(123), Errored)
+	we.CheckThat(eachGoPlusIsGoo.Match(nil), Errored)
 	
 	i_before_e := EachPattern("[^aeiou]ei")(HasPrefix("c"))
 	we.CheckThat("ceiling receipt", i_before_e)
@@ -176,8 +296,8 @@ func Test_EachPatternGroup(t *testing.T) {
 	eachQHasU := EachPatternGroup("([qQ])(.)", 2)(ToLower(EqualTo("u")))
 	we.CheckThat(eachQHasU.Match("Quick quack MQ"), Matched)
 	we.CheckThat(eachQHasU.Match("Quick FAQ for MQ"), DidNotMatch)
-	we.CheckThat(eachQHasU.Match(123), DidNotMatch)
-	we.CheckThat(eachQHasU.Match(nil), DidNotMatch)
+	we.CheckThat(eachQHasU.Match(123), Errored)
+	we.CheckThat(eachQHasU.Match(nil), Errored)
 	
 	ei_after_c := EachPatternGroup("[cC](ei|ie)", 1)(EqualTo("ei"))
 	we.CheckThat(ei_after_c.Match("Ceiling receipt"), Matched)
@@ -192,8 +312,8 @@ func Test_AnyPattern(t *testing.T) {
 	we.CheckThat(anyGoPlusIsGoo.Match("goo goo goo"), Matched)
 	we.CheckThat(anyGoPlusIsGoo.Match("goo go goo"), Matched)
 	we.CheckThat(anyGoPlusIsGoo.Match("go go go"), DidNotMatch)
-	we.CheckThat(anyGoPlusIsGoo.Match(123), DidNotMatch)
-	we.CheckThat(anyGoPlusIsGoo.Match(nil), DidNotMatch)
+	we.CheckThat(anyGoPlusIsGoo.Match(123), Errored)
+	we.CheckThat(anyGoPlusIsGoo.Match(nil), Errored)
 	
 	has_cat_word := AnyPattern("[a-z]+at")(HasPrefix("c"))
 	we.CheckThat("that cravat is phat", has_cat_word)
@@ -207,8 +327,8 @@ func Test_AnyPatternGroup(t *testing.T) {
 	we.CheckThat(isHappy.Match("x :- y+z :-)"), Matched)
 	we.CheckThat(isHappy.Match(":-P :-/ ;-}"), DidNotMatch)
 	we.CheckThat(isHappy.Match("<>v<> o rly?"), DidNotMatch)
-	we.CheckThat(isHappy.Match(123), DidNotMatch)
-	we.CheckThat(isHappy.Match(nil), DidNotMatch)
+	we.CheckThat(isHappy.Match(123), Errored)
+	we.CheckThat(isHappy.Match(nil), Errored)
 }
 
 func Test_OnPattern(t *testing.T) {
@@ -219,8 +339,8 @@ func Test_OnPattern(t *testing.T) {
 	we.CheckThat(firstGoPlusIsGoo.Match("take Go to Google"), DidNotMatch)
 	we.CheckThat(firstGoPlusIsGoo.Match("Goooooooal"), DidNotMatch)
 	we.CheckThat(firstGoPlusIsGoo.Match("no big-G goo"), DidNotMatch)
-	we.CheckThat(firstGoPlusIsGoo.Match(123), DidNotMatch)
-	we.CheckThat(firstGoPlusIsGoo.Match(nil), DidNotMatch)
+	we.CheckThat(firstGoPlusIsGoo.Match(123), Errored)
+	we.CheckThat(firstGoPlusIsGoo.Match(nil), Errored)
 	
 	his := OnPattern("h.s")(EqualTo("his"))
 	we.CheckThat(his.Match("hers and his"), Matched)
@@ -239,7 +359,74 @@ func Test_OnPatternGroup(t *testing.T) {
 	we.CheckThat(americanComedy.Match("humor I mean humour"), Matched)
 	we.CheckThat(americanComedy.Match("humour I mean humor"), DidNotMatch)
 	we.CheckThat(americanComedy.Match("not funny"), DidNotMatch)
-	we.CheckThat(americanComedy.Match(123), DidNotMatch)
-	we.CheckThat(americanComedy.Match(nil), DidNotMatch)
+	we.CheckThat(americanComedy.Match(123), Errored)
+	we.CheckThat(americanComedy.Match(nil), Errored)
+}
+
+func Test_ContainsInOrder(t *testing.T) {
+	we := asserter.Using(t)
+	matcher := ContainsInOrder("GET", "200", "OK")
+	we.CheckThat(matcher.Match("GET /index.html 200 OK"), Matched)
+	we.CheckThat(matcher.Match("GET /index.html OK 200"), DidNotMatch)
+	we.CheckThat(matcher.Match("POST /index.html 200 OK"), DidNotMatch)
+	we.CheckThat(ContainsInOrder("ab", "ba").Match("aba"), DidNotMatch.Comment("no overlap"))
+	we.CheckThat(ContainsInOrder("ab", "ab").Match("abab"), Matched)
+	we.CheckThat(ContainsInOrder().Match("anything"), Matched)
+	
+	result := matcher.Match("GET /index.html OK 200")
+	we.CheckThat(result, ToString(Contains("\"OK\" does not appear after substring #2")))
+	we.CheckThat(len(result.Causes()), EqualTo(2))
 }
 
+func Test_ContainsCount(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ContainsCount("\n", EqualTo(3)).Match("a\nb\nc\n"), Matched)
+	we.CheckThat(ContainsCount("\n", EqualTo(3)).Match("a\nb"), DidNotMatch)
+	we.CheckThat(ContainsCount("aa", EqualTo(2)).Match("aaaa"), Matched)
+	we.CheckThat(ContainsCount("x", GreaterThan(0)).Match(42), Errored)
+}
+
+func Test_ContainsAll(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ContainsAll("fox", "dog").Match("the quick fox and lazy dog"), Matched)
+	we.CheckThat(ContainsAll("dog", "fox").Match("the quick fox and lazy dog"), Matched)
+	we.CheckThat(ContainsAll("fox", "cat").Match("the quick fox and lazy dog"), DidNotMatch)
+	we.CheckThat(ContainsAll().Match(""), Matched)
+}
+
+func Test_ContainsAny(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(ContainsAny("cat", "dog").Match("the quick fox and lazy dog"), Matched)
+	we.CheckThat(ContainsAny("cat", "cow").Match("the quick fox and lazy dog"), DidNotMatch)
+	we.CheckThat(ContainsAny().Match("anything"), DidNotMatch)
+}
+
+func Test_structure(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(Contains("x").Name(), EqualTo("Contains"))
+	we.CheckThat(Contains("x").Params(), DeepEqualTo([]interface{}{"x"}))
+	we.CheckThat(ContainsAll("a", "b").Params(), DeepEqualTo([]interface{}{"a", "b"}))
+	we.CheckThat(EqualToNFC("x").Name(), EqualTo("EqualToNFC"))
+	count := EqualTo(2)
+	matcher := ContainsCount("l", count)
+	we.CheckThat(matcher.Params(), DeepEqualTo([]interface{}{"l"}))
+	we.CheckThat(matcher.Children()[0] == count, Is(True()))
+	we.CheckThat(ToLen(count).Children()[0] == count, Is(True()))
+}
+
+func Test_negatedDescriptions(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(Not(Contains("x")).String(), EqualTo(`does not contain "x"`))
+	we.CheckThat(Not(Contains("x")).Match("xyz").String(),
+		EqualTo(`expected a value that does not contain "x", but was [xyz]`))
+	we.CheckThat(Not(HasPrefix("ab")).String(), EqualTo(`does not start with "ab"`))
+	we.CheckThat(Not(HasSuffix("yz")).String(), EqualTo(`does not end with "yz"`))
+	we.CheckThat(Not(Contains("say \"hi\"\n")).String(),
+		EqualTo(`does not contain "say \"hi\"\n"`))
+	we.CheckThat(Not(EqualToIgnoringCase("Go")).String(),
+		EqualTo(`is not equal to "Go" (ignoring case)`))
+	we.CheckThat(Not(ContainsAll("a", "b")).String(),
+		EqualTo(`does not contain all of ["a" "b"]`))
+	we.CheckThat(Not(ToLen(GreaterThan(3))).String(),
+		EqualTo("has a length that is not greater than 3"))
+}
@@ -0,0 +1,17 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+	Provides Matchers on XML documents given as strings, byte slices
+	or io.Readers:  checking that they are well-formed, comparing them
+	for equivalence (ignoring attribute order, insignificant whitespace
+	and namespace prefixes) and applying other matchers to the values
+	selected by a subset of XPath, such as "/order/item[2]/@sku".
+
+	Readers are read without being used up where possible, so several
+	matchers can check the same *bytes.Buffer or io.Seeker (such as an
+	*os.File).  Other readers can only be matched once:  a later matcher
+	sees them as empty, and so does not match.
+*/
+package xml
@@ -0,0 +1,327 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package xml
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/rdrdr/hamcrest/base"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"xml"
+)
+
+// A parsed XML element or (if name.Local is empty) a run of text.
+// Comments, processing instructions, directives, whitespace-only text
+// and namespace declarations are discarded.
+type _Node struct {
+	name xml.Name
+	attrs []xml.Attr // sorted by namespace, then name
+	children []*_Node
+	text string
+}
+
+func (self *_Node) isText() bool {
+	return self.name.Local == ""
+}
+
+// Returns the concatenated text of the node and its descendants, as
+// XPath's string() function does.
+func (self *_Node) stringValue() string {
+	if self.isText() {
+		return self.text
+	}
+	var buffer bytes.Buffer
+	for _, child := range self.children {
+		buffer.WriteString(child.stringValue())
+	}
+	return buffer.String()
+}
+
+// Returns the value of the attribute with the given local name.
+func (self *_Node) attr(local string) (string, bool) {
+	for _, attr := range self.attrs {
+		if attr.Name.Local == local {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+type _Attrs []xml.Attr
+
+func (self _Attrs) Len() int {
+	return len(self)
+}
+
+func (self _Attrs) Less(i, j int) bool {
+	a, b := self[i].Name, self[j].Name
+	return a.Space < b.Space || (a.Space == b.Space && a.Local < b.Local)
+}
+
+func (self _Attrs) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+}
+
+// Parses an XML document given as a string, a []byte or an io.Reader
+// (read with base.ReadAllRewinding, so that other matchers can read a
+// *bytes.Buffer or an io.Seeker again), returning its root element.
+// Returns a non-nil error describing the problem if the input is not
+// well-formed, and ok=false if the input is of some other type.
+func _Parse(actual interface{}) (root *_Node, err os.Error, ok bool) {
+	var reader io.Reader
+	switch input := actual.(type) {
+	case string:
+		reader = strings.NewReader(input)
+	case []byte:
+		reader = bytes.NewBuffer(input)
+	case io.Reader:
+		data, err := base.ReadAllRewinding(input)
+		if err != nil {
+			return nil, err, true
+		}
+		reader = bytes.NewBuffer(data)
+	default:
+		return nil, nil, false
+	}
+	parser := xml.NewParser(reader)
+	var open []*_Node
+	for {
+		token, err := parser.Token()
+		if err == os.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err, true
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &_Node{name: t.Name}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" ||
+						(attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+					continue
+				}
+				node.attrs = append(node.attrs, attr)
+			}
+			sort.Sort(_Attrs(node.attrs))
+			if len(open) > 0 {
+				parent := open[len(open)-1]
+				parent.children = append(parent.children, node)
+			} else if root != nil {
+				return nil, os.NewError(fmt.Sprintf(
+					"more than one root element: <%v> follows <%v>",
+					t.Name.Local, root.name.Local)), true
+			} else {
+				root = node
+			}
+			open = append(open, node)
+		case xml.EndElement:
+			open = open[:len(open)-1]
+		case xml.CharData:
+			text := string(t)
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			if len(open) == 0 {
+				return nil, os.NewError(fmt.Sprintf(
+					"text %q outside of the root element", text)), true
+			}
+			parent := open[len(open)-1]
+			if n := len(parent.children); n > 0 && parent.children[n-1].isText() {
+				parent.children[n-1].text += text
+			} else {
+				parent.children = append(parent.children, &_Node{text: text})
+			}
+		}
+	}
+	if root == nil {
+		return nil, os.NewError("no root element"), true
+	}
+	if len(open) > 0 {
+		return nil, os.NewError(fmt.Sprintf(
+			"unexpected end of document inside <%v>",
+			open[len(open)-1].name.Local)), true
+	}
+	return root, nil, true
+}
+
+// Returns a matcher whose match function parses its input (see _Parse)
+// and passes the root element to the given function.  Inputs that are
+// not well-formed fail to match, and inputs of unsupported types
+// cannot be matched at all.  The matcher has the given structured
+// description (see base.Matcher.WithStructure), with the given child
+// matcher, if it is not nil.
+func _NewXMLMatcher(f func(root *_Node) *base.Result,
+		name string, params []interface{}, child *base.Matcher,
+		format string, args...interface{}) *base.Matcher {
+	match := func(actual interface{}) *base.Result {
+		root, err, ok := _Parse(actual)
+		if !ok {
+			return base.NewErrorResultf(
+				"Could not apply to %T: expected string, []byte or io.Reader", actual)
+		}
+		if err != nil {
+			return base.NewResultf(false, "not well-formed XML: %v", err)
+		}
+		return f(root)
+	}
+	return base.NewMatcherf(match, format, args...).
+		WithStructure(name, params, _Children(child)...)
+}
+
+// Returns the given matcher as a list of children, or none if it is
+// nil.
+func _Children(child *base.Matcher) []*base.Matcher {
+	if child == nil {
+		return nil
+	}
+	return []*base.Matcher{child}
+}
+
+// Matches strings, byte slices and readers that contain a well-formed
+// XML document:  a single root element with properly nested and
+// closed tags.
+func IsWellFormedXML() *base.Matcher {
+	return _NewXMLMatcher(func(root *_Node) *base.Result {
+		return base.NewResultf(true, "well-formed XML with root <%v>", root.name.Local)
+	}, "IsWellFormedXML", nil, nil, "IsWellFormedXML")
+}
+
+// Returns a matcher that parses XML input and matches if it is
+// equivalent to the given XML document:  the same elements, attributes
+// and text, regardless of
+//    - the order of attributes,
+//    - whitespace between elements and around text (runs of
+//      whitespace within text are treated as a single space),
+//    - namespace prefixes (elements and attributes are compared by
+//      namespace URI and local name), and
+//    - comments and processing instructions.
+// On a mismatch, each difference is reported as a separate cause,
+// identified by its path, such as /order/item[2]/@sku.
+//
+// If the expected document is not well-formed, the returned matcher
+// reports the parse error as a matcher error on every input.
+func EquivalentXML(expected string) *base.Matcher {
+	want, err, _ := _Parse(expected)
+	if err != nil {
+		return base.NewErrorMatcherf(err, "EquivalentXML[%v]", expected).
+			WithStructure("EquivalentXML", []interface{}{expected})
+	}
+	return _NewXMLMatcher(func(root *_Node) *base.Result {
+		differences := _Compare("/" + want.name.Local, want, root, nil)
+		if len(differences) == 0 {
+			return base.NewResultf(true, "equivalent to expected XML")
+		}
+		causes := make([]*base.Result, len(differences))
+		for i, difference := range differences {
+			causes[i] = base.NewResultf(false, "%v", difference)
+		}
+		return base.NewResultf(false,
+			"%v differences from expected XML", len(differences)).
+			WithCauses(causes...)
+	}, "EquivalentXML", []interface{}{expected}, nil, "EquivalentXML[%v]", expected)
+}
+
+// Appends to differences a description of each way in which actual
+// differs from expected, for nodes at the given path.
+func _Compare(path string, expected, actual *_Node, differences []string) []string {
+	difference := func(format string, args...interface{}) {
+		differences = append(differences, path + ": " + fmt.Sprintf(format, args...))
+	}
+	if expected.isText() || actual.isText() {
+		if expected.isText() != actual.isText() {
+			difference("expected %v but was %v", _Describe(expected), _Describe(actual))
+		} else if _Collapse(expected.text) != _Collapse(actual.text) {
+			difference("expected text %q but was %q",
+				_Collapse(expected.text), _Collapse(actual.text))
+		}
+		return differences
+	}
+	if !_SameName(expected.name, actual.name) {
+		difference("expected %v but was %v", _Describe(expected), _Describe(actual))
+		return differences
+	}
+	for _, attr := range expected.attrs {
+		value, found := _FindAttr(actual.attrs, attr.Name)
+		switch {
+		case !found:
+			differences = append(differences, fmt.Sprintf(
+				"%v/@%v: missing, expected %q", path, attr.Name.Local, attr.Value))
+		case value != attr.Value:
+			differences = append(differences, fmt.Sprintf(
+				"%v/@%v: expected %q but was %q", path, attr.Name.Local, attr.Value, value))
+		}
+	}
+	for _, attr := range actual.attrs {
+		if _, found := _FindAttr(expected.attrs, attr.Name); !found {
+			differences = append(differences, fmt.Sprintf(
+				"%v/@%v: unexpected %q", path, attr.Name.Local, attr.Value))
+		}
+	}
+	if len(expected.children) != len(actual.children) {
+		difference("expected %v children but was %v",
+			len(expected.children), len(actual.children))
+	}
+	for i := 0; i < len(expected.children) && i < len(actual.children); i++ {
+		differences = _Compare(_ChildPath(path, expected, i),
+			expected.children[i], actual.children[i], differences)
+	}
+	return differences
+}
+
+func _FindAttr(attrs []xml.Attr, name xml.Name) (string, bool) {
+	for _, attr := range attrs {
+		if _SameName(attr.Name, name) {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+func _SameName(a, b xml.Name) bool {
+	return a.Space == b.Space && a.Local == b.Local
+}
+
+// Returns the path of the i'th child of parent (at the given path),
+// such as /order/item[2] or /order/text()[1].
+func _ChildPath(path string, parent *_Node, i int) string {
+	child := parent.children[i]
+	step := child.name.Local
+	if child.isText() {
+		step = "text()"
+	}
+	position, count := 0, 0
+	for j, sibling := range parent.children {
+		if sibling.isText() == child.isText() && sibling.name.Local == child.name.Local {
+			count++
+			if j <= i {
+				position++
+			}
+		}
+	}
+	if count > 1 {
+		return fmt.Sprintf("%v/%v[%v]", path, step, position)
+	}
+	return path + "/" + step
+}
+
+// Describes a node for a mismatch message.
+func _Describe(node *_Node) string {
+	if node.isText() {
+		return fmt.Sprintf("text %q", _Collapse(node.text))
+	}
+	if node.name.Space != "" {
+		return fmt.Sprintf("element <%v> (in namespace %q)", node.name.Local, node.name.Space)
+	}
+	return fmt.Sprintf("element <%v>", node.name.Local)
+}
+
+// Trims text and replaces each run of whitespace with a single space.
+func _Collapse(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,103 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package xml
+
+import (
+	"bytes"
+	"github.com/rdrdr/hamcrest/asserter"
+	"github.com/rdrdr/hamcrest/base"
+	. "github.com/rdrdr/hamcrest/core"
+	"io"
+	"testing"
+)
+
+var Matched = base.Matched()
+var DidNotMatch = base.DidNotMatch()
+var Errored = base.Errored()
+
+const order = `<?xml version="1.0"?>
+<o:order xmlns:o="urn:orders" id="7">
+	<!-- two items -->
+	<o:item sku="a-1" qty="2">Widget  one</o:item>
+	<o:item qty="1" sku="b-2">Gadget</o:item>
+	<note>Hello <b>big</b> world</note>
+</o:order>`
+
+func Test_IsWellFormedXML(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(IsWellFormedXML().Match(order), Matched)
+	we.CheckThat(IsWellFormedXML().Match([]byte(`<a/>`)), Matched)
+	we.CheckThat(IsWellFormedXML().Match(bytes.NewBufferString(`<a><b/></a>`)), Matched)
+	we.CheckThat(IsWellFormedXML().Match(`<a><b></a>`), DidNotMatch.
+		Comment("mismatched tags"))
+	we.CheckThat(IsWellFormedXML().Match(`<a>`), DidNotMatch.
+		Comment("unclosed root"))
+	we.CheckThat(IsWellFormedXML().Match(`<a/><b/>`), DidNotMatch.
+		Comment("two roots"))
+	we.CheckThat(IsWellFormedXML().Match(``), DidNotMatch)
+	we.CheckThat(IsWellFormedXML().Match(7), Errored)
+}
+
+func Test_readersCanBeMatchedMoreThanOnce(t *testing.T) {
+	we := asserter.Using(t)
+	reader := bytes.NewBufferString(order)
+	we.CheckThat(AllOf(IsWellFormedXML(), AtXPath("/order/@id", EqualTo("7"))).
+		Match(reader), Matched)
+	we.CheckThat(reader.String(), EqualTo(order).Comment("not consumed"))
+	limited := io.LimitReader(bytes.NewBufferString(order), 1000)
+	we.CheckThat(IsWellFormedXML().Match(limited), Matched)
+	we.CheckThat(IsWellFormedXML().Match(limited), DidNotMatch.
+		Comment("already consumed"))
+	empty := io.LimitReader(bytes.NewBufferString(order), 0)
+	we.CheckThat(IsWellFormedXML().Match(empty), DidNotMatch.
+		Comment("empty, not an error"))
+}
+
+func Test_EquivalentXML(t *testing.T) {
+	we := asserter.Using(t)
+	equivalent := `<order xmlns="urn:orders" id="7">` +
+		`<item qty="2" sku="a-1">Widget one</item>` +
+		`<item sku="b-2" qty="1">Gadget</item>` +
+		`<note xmlns="">Hello <b>big</b> world</note>` +
+		`</order>`
+	we.CheckThat(EquivalentXML(equivalent).Match(order), Matched.
+		Comment("different prefixes, attribute order and whitespace"))
+	we.CheckThat(EquivalentXML(`<order id="7"/>`).Match(order), DidNotMatch.
+		Comment("different namespace"))
+	we.CheckThat(EquivalentXML(`<a><b/><c/></a>`).Match(`<a><c/><b/></a>`), DidNotMatch.
+		Comment("element order matters"))
+
+	result := EquivalentXML(`<a x="1"><b>one</b></a>`).Match(`<a x="2" y="3"><b>two</b></a>`)
+	we.CheckThat(result, DidNotMatch)
+	we.CheckThat(len(result.Causes()), EqualTo(3).
+		Comment("one cause per difference"))
+	we.CheckThat(EquivalentXML(`<a>`).Match(`<a/>`), Errored)
+}
+
+func Test_Collapse(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(_Collapse("  Widget \n\t one "), EqualTo("Widget one"))
+}
+
+func Test_structure(t *testing.T) {
+	we := asserter.Using(t)
+	id := EqualTo("7")
+	matcher := AllOf(IsWellFormedXML(), AtXPath("/order/@id", id), EquivalentXML(order))
+	var names []string
+	matcher.Inspect(func(m *base.Matcher) bool {
+		names = append(names, m.Name())
+		return true
+	})
+	we.CheckThat(names, DeepEqualTo([]string{"AllOf", "IsWellFormedXML",
+		"AtXPath", "EqualTo", "EquivalentXML"}))
+	atXPath := AtXPath("/order/@id", id)
+	we.CheckThat(atXPath.Params(), DeepEqualTo([]interface{}{"/order/@id"}))
+	we.CheckThat(atXPath.Children()[0] == id, Is(True()))
+	we.CheckThat(AtXPath("a", id).Children()[0] == id, Is(True()).
+		Comment("invalid expression"))
+	we.CheckThat(EquivalentXML(order).Params(), DeepEqualTo([]interface{}{order}))
+	we.CheckThat(EquivalentXML("<a>").Name(), EqualTo("EquivalentXML").
+		Comment("invalid document"))
+}
@@ -0,0 +1,278 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package xml
+
+import (
+	"fmt"
+	"github.com/rdrdr/hamcrest/base"
+	"os"
+	"strconv"
+	"strings"
+	"xml"
+)
+
+// Returns a matcher that parses XML input and applies the given matcher
+// to the string value of the first node selected by the given XPath
+// expression (as XPath's string() function does):  the value of an
+// attribute, or the concatenated text within an element.  If the
+// expression selects nothing, the matcher fails to match.
+//
+// The supported subset of XPath is absolute location paths made of:
+//    /name         child elements with the given name (or * for any)
+//    //name        descendant elements with the given name
+//    /@name        an attribute (or @* for any), as the last step
+//    /text()       child text, as the last step
+// where element steps may have any number of predicates:
+//    [n]           the n'th of the matching siblings (from 1)
+//    [last()]      the last of the matching siblings
+//    [@name]       elements that have the given attribute
+//    [@name='v']   elements whose attribute has the given value
+// Namespace prefixes in names are ignored.  For example:
+//    AtXPath("/order/item[@sku='a-1']/@qty", EqualTo("2"))
+//    AtXPath("//item[last()]", HasPrefix("Widget"))
+//
+// If the expression is invalid, the returned matcher reports the error
+// as a matcher error on every input.
+func AtXPath(expr string, matcher *base.Matcher) *base.Matcher {
+	steps, err := _ParseXPath(expr)
+	if err != nil {
+		return base.NewErrorMatcherf(err, "AtXPath[%q][%v]", expr, matcher).
+			WithStructure("AtXPath", []interface{}{expr}, matcher)
+	}
+	return _NewXMLMatcher(func(root *_Node) *base.Result {
+		selected := _EvaluateXPath(steps, root)
+		if len(selected) == 0 {
+			return base.NewResultf(false, "%v selected nothing", expr)
+		}
+		result := matcher.Match(selected[0])
+		return base.NewResultf(result.Matched(),
+			"%v selected %q (first of %v)", expr, selected[0], len(selected)).
+			WithError(result.Err()).
+			WithCauses(result)
+	}, "AtXPath", []interface{}{expr}, matcher, "AtXPath[%q][%v]", expr, matcher)
+}
+
+// A single step of an XPath location path.
+type _XStep struct {
+	descendant bool // preceded by "//" rather than "/"
+	name string     // local name, or "*"
+	attribute bool  // an @name step
+	text bool       // a text() step
+	predicates []*_XPredicate
+}
+
+// A predicate of an element step:  a position (index, from 1, or last)
+// or a test of an attribute (its presence, or its value).
+type _XPredicate struct {
+	index int
+	last bool
+	attr string
+	value string
+	hasValue bool
+}
+
+func _XPathError(expr, problem string) os.Error {
+	return os.NewError(fmt.Sprintf("invalid XPath %q: %v", expr, problem))
+}
+
+// Parses an XPath expression, as described for AtXPath.
+func _ParseXPath(expr string) ([]*_XStep, os.Error) {
+	if !strings.HasPrefix(expr, "/") {
+		return nil, _XPathError(expr, "must begin with \"/\"")
+	}
+	var steps []*_XStep
+	rest := expr
+	for rest != "" {
+		if len(steps) > 0 {
+			if last := steps[len(steps)-1]; last.attribute || last.text {
+				return nil, _XPathError(expr, "nothing may follow an attribute or text() step")
+			}
+		}
+		step := &_XStep{}
+		if strings.HasPrefix(rest, "//") {
+			step.descendant = true
+			rest = rest[2:]
+		} else if strings.HasPrefix(rest, "/") {
+			rest = rest[1:]
+		} else {
+			return nil, _XPathError(expr, fmt.Sprintf("expected \"/\" before %q", rest))
+		}
+		end := strings.IndexAny(rest, "/[")
+		if end < 0 {
+			end = len(rest)
+		}
+		name := rest[:end]
+		rest = rest[end:]
+		switch {
+		case name == "text()":
+			step.text = true
+		case strings.HasPrefix(name, "@"):
+			step.attribute = true
+			name = name[1:]
+		}
+		if colon := strings.Index(name, ":"); colon >= 0 {
+			name = name[colon+1:]
+		}
+		if name == "" {
+			return nil, _XPathError(expr, "missing name")
+		}
+		step.name = name
+		for strings.HasPrefix(rest, "[") {
+			if step.attribute || step.text {
+				return nil, _XPathError(expr, "predicates are only supported on elements")
+			}
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, _XPathError(expr, "missing \"]\"")
+			}
+			predicate, err := _ParsePredicate(expr, rest[1:end])
+			if err != nil {
+				return nil, err
+			}
+			step.predicates = append(step.predicates, predicate)
+			rest = rest[end+1:]
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func _ParsePredicate(expr, inside string) (*_XPredicate, os.Error) {
+	inside = strings.TrimSpace(inside)
+	if inside == "last()" {
+		return &_XPredicate{last: true}, nil
+	}
+	if strings.HasPrefix(inside, "@") {
+		predicate := &_XPredicate{attr: inside[1:]}
+		if equals := strings.Index(inside, "="); equals >= 0 {
+			predicate.attr = strings.TrimSpace(inside[1:equals])
+			value := strings.TrimSpace(inside[equals+1:])
+			if len(value) < 2 || (value[0] != '\'' && value[0] != '"') ||
+					value[len(value)-1] != value[0] {
+				return nil, _XPathError(expr, fmt.Sprintf("invalid value in [%v]", inside))
+			}
+			predicate.value, predicate.hasValue = value[1:len(value)-1], true
+		}
+		if colon := strings.Index(predicate.attr, ":"); colon >= 0 {
+			predicate.attr = predicate.attr[colon+1:]
+		}
+		if predicate.attr == "" {
+			return nil, _XPathError(expr, fmt.Sprintf("missing attribute name in [%v]", inside))
+		}
+		return predicate, nil
+	}
+	index, err := strconv.Atoi(inside)
+	if err != nil || index < 1 {
+		return nil, _XPathError(expr, fmt.Sprintf("unsupported predicate [%v]", inside))
+	}
+	return &_XPredicate{index: index}, nil
+}
+
+// Evaluates the steps of an XPath expression against a document,
+// returning the string values of the selected nodes in document order.
+func _EvaluateXPath(steps []*_XStep, root *_Node) []string {
+	document := &_Node{name: xml.Name{Local: "/"}, children: []*_Node{root}}
+	context := []*_Node{document}
+	for _, step := range steps {
+		var parents []*_Node
+		for _, node := range context {
+			if step.descendant {
+				parents = _DescendantsOrSelf(node, parents)
+			} else {
+				parents = append(parents, node)
+			}
+		}
+		if step.attribute || step.text {
+			return _Leaves(step, _Unique(parents))
+		}
+		var selected []*_Node
+		for _, parent := range _Unique(parents) {
+			var matching []*_Node
+			for _, child := range parent.children {
+				if !child.isText() && (step.name == "*" || child.name.Local == step.name) {
+					matching = append(matching, child)
+				}
+			}
+			for _, predicate := range step.predicates {
+				matching = predicate.filter(matching)
+			}
+			selected = append(selected, matching...)
+		}
+		context = _Unique(selected)
+	}
+	values := make([]string, len(context))
+	for i, node := range context {
+		values[i] = node.stringValue()
+	}
+	return values
+}
+
+// Returns the values selected by a final attribute or text() step.
+func _Leaves(step *_XStep, parents []*_Node) []string {
+	var values []string
+	for _, parent := range parents {
+		if step.text {
+			for _, child := range parent.children {
+				if child.isText() {
+					values = append(values, child.text)
+				}
+			}
+			continue
+		}
+		for _, attr := range parent.attrs {
+			if step.name == "*" || attr.Name.Local == step.name {
+				values = append(values, attr.Value)
+			}
+		}
+	}
+	return values
+}
+
+func (self *_XPredicate) filter(nodes []*_Node) []*_Node {
+	switch {
+	case self.last:
+		if len(nodes) == 0 {
+			return nodes
+		}
+		return nodes[len(nodes)-1:]
+	case self.index > 0:
+		if self.index > len(nodes) {
+			return nil
+		}
+		return nodes[self.index-1:self.index]
+	}
+	var filtered []*_Node
+	for _, node := range nodes {
+		if value, ok := node.attr(self.attr); ok && (!self.hasValue || value == self.value) {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered
+}
+
+// Appends the node and all of its descendant elements, in document
+// order.
+func _DescendantsOrSelf(node *_Node, nodes []*_Node) []*_Node {
+	nodes = append(nodes, node)
+	for _, child := range node.children {
+		if !child.isText() {
+			nodes = _DescendantsOrSelf(child, nodes)
+		}
+	}
+	return nodes
+}
+
+// Removes duplicate nodes, keeping the first occurrence of each.
+func _Unique(nodes []*_Node) []*_Node {
+	seen := make(map[*_Node]bool)
+	unique := make([]*_Node, 0, len(nodes))
+	for _, node := range nodes {
+		if !seen[node] {
+			seen[node] = true
+			unique = append(unique, node)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,46 @@
+// Copyright 2011 Mick Killianey.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package xml
+
+import (
+	"github.com/rdrdr/hamcrest/asserter"
+	. "github.com/rdrdr/hamcrest/core"
+	"testing"
+)
+
+func Test_AtXPath(t *testing.T) {
+	we := asserter.Using(t)
+	we.CheckThat(AtXPath("/order/@id", EqualTo("7")).Match(order), Matched)
+	we.CheckThat(AtXPath("/o:order/o:item[2]/@sku", EqualTo("b-2")).Match(order), Matched.
+		Comment("prefixes are ignored"))
+	we.CheckThat(AtXPath("/order/item[@sku='b-2']", EqualTo("Gadget")).Match(order), Matched)
+	we.CheckThat(AtXPath(`/order/item[@sku="b-2"]/@qty`, EqualTo("1")).Match(order), Matched)
+	we.CheckThat(AtXPath("//item[last()]/@qty", EqualTo("1")).Match(order), Matched)
+	we.CheckThat(AtXPath("//b", EqualTo("big")).Match(order), Matched)
+	we.CheckThat(AtXPath("/order/note", EqualTo("Hello big world")).Match(order), Matched)
+	we.CheckThat(AtXPath("/order/note/text()", EqualTo("Hello ")).Match(order), Matched)
+	we.CheckThat(AtXPath("/order/*[@sku]", EqualTo("Widget  one")).Match(order), Matched.
+		Comment("first of the selected nodes"))
+	we.CheckThat(AtXPath("//item[3]", Anything()).Match(order), DidNotMatch)
+	we.CheckThat(AtXPath("/order/@missing", Anything()).Match(order), DidNotMatch)
+}
+
+func Test_ParseXPath(t *testing.T) {
+	we := asserter.Using(t)
+	steps, err := _ParseXPath("/a//b[2][@c='d']/@e")
+	we.AssertThat(err, Nil())
+	we.CheckThat(len(steps), EqualTo(3))
+	we.CheckThat(steps[1].descendant, True())
+	we.CheckThat(len(steps[1].predicates), EqualTo(2))
+	we.CheckThat(steps[1].predicates[1].value, EqualTo("d"))
+	we.CheckThat(steps[2].attribute, True())
+
+	for _, bad := range []string{"", "a", "/", "/a/@b/c", "/a[x]", "/a[0]",
+			"/a[@b='c]", "/a[1", "/@b[1]"} {
+		_, err := _ParseXPath(bad)
+		we.CheckThat(err, Not(Nil()).Comment(bad))
+	}
+	we.CheckThat(AtXPath("a", Anything()).Match(order), Errored)
+}